@@ -0,0 +1,145 @@
+package tiled
+
+import (
+	"time"
+)
+
+// AnimationSync selects which animated tiles share an animation clock
+type AnimationSync int
+
+const (
+	// SyncGlobal makes all animated tiles share a single clock
+	SyncGlobal AnimationSync = iota
+	// SyncTileset gives each tileset its own clock, keyed by tileset name
+	SyncTileset
+	// SyncGroup gives each named group its own clock. The group of a tile is
+	// read from the Animator.GroupProperty of the tileset tile, tiles without
+	// it share the global clock.
+	SyncGroup
+)
+
+const (
+	// DefaultAnimationGroupProperty is the tile property naming the
+	// animation group of a tile
+	DefaultAnimationGroupProperty = "animgroup"
+	// DefaultAnimationOffsetProperty is the tile property holding the
+	// animation phase offset of a tile in milliseconds
+	DefaultAnimationOffsetProperty = "animoffset"
+)
+
+// Animator keeps track of animation clocks and resolves which frame of an
+// animated tile should be displayed.
+type Animator struct {
+	// Sync selects which tiles share a clock. Defaults to SyncGlobal.
+	Sync AnimationSync
+	// The tile property naming the animation group of a tile, used with SyncGroup.
+	GroupProperty string
+	// The int tile property holding a phase offset in milliseconds, so tiles
+	// of the same group can be deliberately out of phase.
+	OffsetProperty string
+
+	elapsed time.Duration
+	offsets map[string]time.Duration
+}
+
+// NewAnimator creates an Animator using the given sync mode and the default
+// tile properties.
+func NewAnimator(sync AnimationSync) *Animator {
+	return &Animator{
+		Sync:           sync,
+		GroupProperty:  DefaultAnimationGroupProperty,
+		OffsetProperty: DefaultAnimationOffsetProperty,
+		offsets:        map[string]time.Duration{},
+	}
+}
+
+// Update advances all clocks by dt
+func (a *Animator) Update(dt time.Duration) {
+	a.elapsed += dt
+}
+
+// SetClockOffset shifts the clock of a tileset or group, depending on the
+// sync mode. The empty key is the global clock.
+func (a *Animator) SetClockOffset(key string, offset time.Duration) {
+	if a.offsets == nil {
+		a.offsets = map[string]time.Duration{}
+	}
+	a.offsets[key] = offset
+}
+
+// ResetClock restarts the clock of a tileset or group, depending on the sync
+// mode, so its animations start again from the first frame.
+func (a *Animator) ResetClock(key string) {
+	a.SetClockOffset(key, -a.elapsed)
+}
+
+func (a *Animator) clockKey(tile *LayerTile, tilesetTile *TilesetTile) string {
+	switch a.Sync {
+	case SyncTileset:
+		return tile.Tileset.Name
+	case SyncGroup:
+		return tilesetTile.Properties.GetString(a.GroupProperty)
+	}
+	return ""
+}
+
+// Elapsed returns the value of the clock driving the animation of the tile,
+// including its phase offset.
+func (a *Animator) Elapsed(tile *LayerTile) time.Duration {
+	if tile == nil || tile.Nil || tile.Tileset == nil {
+		return a.elapsed
+	}
+
+	tilesetTile := tile.Tileset.Tile(tile.ID)
+	if tilesetTile == nil {
+		return a.elapsed
+	}
+
+	return a.elapsed + a.offsets[a.clockKey(tile, tilesetTile)] +
+		time.Duration(tilesetTile.Properties.GetInt(a.OffsetProperty))*time.Millisecond
+}
+
+// Frame returns the tile to display for an animated tile, keeping its flip
+// flags. Tiles without animation are returned as is.
+func (a *Animator) Frame(tile *LayerTile) *LayerTile {
+	if tile == nil || tile.Nil || tile.Tileset == nil {
+		return tile
+	}
+
+	tilesetTile := tile.Tileset.Tile(tile.ID)
+	if tilesetTile == nil || len(tilesetTile.Animation) == 0 {
+		return tile
+	}
+
+	frame := activeFrame(tilesetTile.Animation, a.Elapsed(tile))
+	if frame.TileID == tile.ID {
+		return tile
+	}
+
+	res := *tile
+	res.ID = frame.TileID
+	return &res
+}
+
+func activeFrame(frames []*AnimationFrame, elapsed time.Duration) *AnimationFrame {
+	var total uint32
+	for _, f := range frames {
+		total += f.Duration
+	}
+	if total == 0 {
+		return frames[0]
+	}
+
+	t := elapsed.Milliseconds() % int64(total)
+	if t < 0 {
+		t += int64(total)
+	}
+
+	for _, f := range frames {
+		if t < int64(f.Duration) {
+			return f
+		}
+		t -= int64(f.Duration)
+	}
+	return frames[len(frames)-1]
+}
@@ -0,0 +1,67 @@
+package tiled
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnimatorFrame(t *testing.T) {
+	ts, err := LoadTilesetFile(filepath.Join(GetAssetsDirectory(), "tilesets/testLoadTilesetTile.tsx"))
+	assert.NoError(t, err)
+
+	tile := &LayerTile{ID: 464, Tileset: ts, HorizontalFlip: true}
+	static := &LayerTile{ID: 1, Tileset: ts}
+
+	a := NewAnimator(SyncGlobal)
+	assert.Equal(t, uint32(75), a.Frame(tile).ID)
+	assert.True(t, a.Frame(tile).HorizontalFlip)
+	assert.Same(t, static, a.Frame(static))
+
+	a.Update(600 * time.Millisecond)
+	assert.Equal(t, uint32(76), a.Frame(tile).ID)
+
+	a.Update(500 * time.Millisecond)
+	assert.Equal(t, uint32(75), a.Frame(tile).ID)
+}
+
+func TestAnimatorSync(t *testing.T) {
+	ts, err := LoadTilesetFile(filepath.Join(GetAssetsDirectory(), "tilesets/testLoadTilesetTile.tsx"))
+	assert.NoError(t, err)
+	tile := &LayerTile{ID: 464, Tileset: ts}
+
+	a := NewAnimator(SyncTileset)
+	a.Update(600 * time.Millisecond)
+	assert.Equal(t, uint32(76), a.Frame(tile).ID)
+
+	a.ResetClock(ts.Name)
+	assert.Equal(t, uint32(75), a.Frame(tile).ID)
+
+	// Phase offset from a tile property
+	ts.Tiles[0].Properties = Properties{{Name: DefaultAnimationOffsetProperty, Type: "int", Value: "500"}}
+	assert.Equal(t, uint32(76), a.Frame(tile).ID)
+
+	// Named group clock
+	ts.Tiles[0].Properties = Properties{{Name: DefaultAnimationGroupProperty, Value: "torches"}}
+	a.Sync = SyncGroup
+	assert.Equal(t, uint32(76), a.Frame(tile).ID)
+	a.SetClockOffset("torches", 400*time.Millisecond)
+	assert.Equal(t, uint32(75), a.Frame(tile).ID)
+}
+
+func TestTilesetTileFrameAt(t *testing.T) {
+	ts, err := LoadTilesetFile(filepath.Join(GetAssetsDirectory(), "tilesets/testLoadTilesetTile.tsx"))
+	assert.NoError(t, err)
+	tile := ts.Tiles[0]
+
+	assert.Equal(t, time.Second, tile.AnimationDuration())
+	assert.Equal(t, 500*time.Millisecond, tile.Animation[0].Length())
+	assert.Equal(t, uint32(75), tile.FrameAt(0).TileID)
+	assert.Equal(t, uint32(76), tile.FrameAt(700*time.Millisecond).TileID)
+	assert.Equal(t, uint32(75), tile.FrameAt(1200*time.Millisecond).TileID)
+
+	assert.Nil(t, (&TilesetTile{}).FrameAt(time.Second))
+	assert.Zero(t, (&TilesetTile{}).AnimationDuration())
+}
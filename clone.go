@@ -0,0 +1,254 @@
+package tiled
+
+// Clone returns a deep copy of the map. Tiles, objects and templates of the
+// copy refer to its own tilesets, so the copy can be changed without
+// changing m, as when each instance of a level mutates its own map.
+// Options the map was loaded with are shared.
+func (m *Map) Clone() *Map {
+	c := &cloner{
+		tilesets:  map[*Tileset]*Tileset{},
+		templates: map[*Template]*Template{},
+	}
+	res := *m
+	c.m = &res
+
+	res.BackgroundColor = clonePtr(m.BackgroundColor)
+	if m.Properties != nil {
+		props := m.Properties.clone()
+		res.Properties = &props
+	}
+	res.Tilesets = cloneSlice(m.Tilesets, c.tileset)
+	res.Layers = cloneSlice(m.Layers, c.layer)
+	res.ObjectGroups = cloneSlice(m.ObjectGroups, c.objectGroup)
+	res.ImageLayers = cloneSlice(m.ImageLayers, c.imageLayer)
+	res.Groups = cloneSlice(m.Groups, c.group)
+
+	if m.templates != nil {
+		res.templates = make(map[string]*Template, len(m.templates))
+		for path, t := range m.templates {
+			res.templates[path] = c.template(t)
+		}
+	}
+	if m.templateTilesets != nil {
+		res.templateTilesets = make(map[*Tileset]*Tileset, len(m.templateTilesets))
+		for ts, mapTileset := range m.templateTilesets {
+			res.templateTilesets[c.tileset(ts)] = c.tileset(mapTileset)
+		}
+	}
+	return &res
+}
+
+// Clone returns a deep copy of the layer. Its tiles are copied too, but
+// still refer to the tilesets of the map of l.
+func (l *Layer) Clone() *Layer {
+	return (&cloner{}).layer(l)
+}
+
+// Clone returns a deep copy of the tileset
+func (ts *Tileset) Clone() *Tileset {
+	return (&cloner{tilesets: map[*Tileset]*Tileset{}}).tileset(ts)
+}
+
+// cloner deep copies the parts of a map. Tilesets and templates are copied
+// once, and the copies shared by everything referring to them, unless their
+// maps are nil, in which case the originals are shared.
+type cloner struct {
+	// Map copy, the map of the layers copied when not nil
+	m         *Map
+	tilesets  map[*Tileset]*Tileset
+	templates map[*Template]*Template
+}
+
+func cloneSlice[T any](s []*T, clone func(*T) *T) []*T {
+	if s == nil {
+		return nil
+	}
+	res := make([]*T, len(s))
+	for i, v := range s {
+		if v != nil {
+			res[i] = clone(v)
+		}
+	}
+	return res
+}
+
+// clonePtr copies the value v points to, for structs without pointers
+func clonePtr[T any](v *T) *T {
+	if v == nil {
+		return nil
+	}
+	res := *v
+	return &res
+}
+
+func (p Properties) clone() Properties {
+	return cloneSlice(p, func(prop *Property) *Property {
+		res := *prop
+		res.Properties = prop.Properties.clone()
+		return &res
+	})
+}
+
+func cloneImage(img *Image) *Image {
+	if img == nil {
+		return nil
+	}
+	res := *img
+	res.Trans = clonePtr(img.Trans)
+	if img.Data != nil {
+		data := *img.Data
+		data.RawData = append([]byte(nil), img.Data.RawData...)
+		data.DataTiles = cloneSlice(img.Data.DataTiles, clonePtr)
+		res.Data = &data
+	}
+	return &res
+}
+
+func (c *cloner) tileset(ts *Tileset) *Tileset {
+	if ts == nil {
+		return nil
+	}
+	if c.tilesets == nil {
+		return ts
+	}
+	if res, ok := c.tilesets[ts]; ok {
+		return res
+	}
+
+	res := *ts
+	c.tilesets[ts] = &res
+	res.tiles = nil
+	res.TileOffset = clonePtr(ts.TileOffset)
+	res.Grid = clonePtr(ts.Grid)
+	res.Transformations = clonePtr(ts.Transformations)
+	res.Properties = ts.Properties.clone()
+	res.Image = cloneImage(ts.Image)
+	res.TerrainTypes = cloneSlice(ts.TerrainTypes, func(t *Terrain) *Terrain {
+		terrain := *t
+		terrain.Properties = t.Properties.clone()
+		return &terrain
+	})
+	res.Tiles = cloneSlice(ts.Tiles, c.tilesetTile)
+	res.WangSets = cloneSlice(ts.WangSets, func(w *WangSet) *WangSet {
+		set := *w
+		set.WangColors = cloneSlice(w.WangColors, clonePtr)
+		set.WangTiles = cloneSlice(w.WangTiles, clonePtr)
+		return &set
+	})
+	return &res
+}
+
+func (c *cloner) tilesetTile(t *TilesetTile) *TilesetTile {
+	res := *t
+	res.Properties = t.Properties.clone()
+	res.Image = cloneImage(t.Image)
+	res.ObjectGroups = cloneSlice(t.ObjectGroups, c.objectGroup)
+	res.Animation = cloneSlice(t.Animation, clonePtr)
+	return &res
+}
+
+func (c *cloner) layer(l *Layer) *Layer {
+	res := *l
+	if c.m != nil {
+		res._map = c.m
+	}
+	res.TintColor = clonePtr(l.TintColor)
+	res.Properties = l.Properties.clone()
+	res.Tiles = cloneSlice(l.Tiles, func(t *LayerTile) *LayerTile {
+		if t.Nil {
+			// Nil tiles are shared, see NilLayerTile
+			return t
+		}
+		tile := *t
+		tile.Tileset = c.tileset(t.Tileset)
+		return &tile
+	})
+	if l.data != nil {
+		data := *l.data
+		data.RawData = append([]byte(nil), l.data.RawData...)
+		data.DataTiles = cloneSlice(l.data.DataTiles, clonePtr)
+		res.data = &data
+	}
+	return &res
+}
+
+func (c *cloner) objectGroup(g *ObjectGroup) *ObjectGroup {
+	res := *g
+	res.Color = clonePtr(g.Color)
+	res.TintColor = clonePtr(g.TintColor)
+	res.Properties = g.Properties.clone()
+	res.Objects = cloneSlice(g.Objects, c.object)
+	return &res
+}
+
+func (c *cloner) object(o *Object) *Object {
+	res := *o
+	res.Properties = o.Properties.clone()
+	copyShapes(&res, o)
+	res.Template = c.template(o.Template)
+	return &res
+}
+
+// copyShapes sets the shapes and text of dst to copies of the ones of src
+func copyShapes(dst, src *Object) {
+	dst.Ellipses = cloneSlice(src.Ellipses, clonePtr)
+	dst.Polygons = cloneSlice(src.Polygons, func(p *Polygon) *Polygon {
+		return &Polygon{Points: clonePoints(p.Points)}
+	})
+	dst.PolyLines = cloneSlice(src.PolyLines, func(p *PolyLine) *PolyLine {
+		return &PolyLine{Points: clonePoints(p.Points)}
+	})
+	dst.Text = nil
+	if src.Text != nil {
+		text := *src.Text
+		text.Color = clonePtr(src.Text.Color)
+		dst.Text = &text
+	}
+}
+
+func clonePoints(p *Points) *Points {
+	if p == nil {
+		return nil
+	}
+	points := Points(cloneSlice(*p, clonePtr))
+	return &points
+}
+
+func (c *cloner) template(t *Template) *Template {
+	if t == nil {
+		return nil
+	}
+	if c.templates == nil {
+		return t
+	}
+	if res, ok := c.templates[t]; ok {
+		return res
+	}
+
+	res := &Template{}
+	c.templates[t] = res
+	res.Tileset = c.tileset(t.Tileset)
+	if t.Object != nil {
+		res.Object = c.object(t.Object)
+	}
+	return res
+}
+
+func (c *cloner) imageLayer(l *ImageLayer) *ImageLayer {
+	res := *l
+	res.TintColor = clonePtr(l.TintColor)
+	res.Properties = l.Properties.clone()
+	res.Image = cloneImage(l.Image)
+	return &res
+}
+
+func (c *cloner) group(g *Group) *Group {
+	res := *g
+	res.TintColor = clonePtr(g.TintColor)
+	res.Properties = g.Properties.clone()
+	res.Layers = cloneSlice(g.Layers, c.layer)
+	res.ObjectGroups = cloneSlice(g.ObjectGroups, c.objectGroup)
+	res.ImageLayers = cloneSlice(g.ImageLayers, c.imageLayer)
+	res.Groups = cloneSlice(g.Groups, c.group)
+	return &res
+}
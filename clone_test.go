@@ -0,0 +1,84 @@
+package tiled
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapClone(t *testing.T) {
+	m, err := LoadFile(filepath.Join(GetAssetsDirectory(), "test_template.tmx"))
+	assert.NoError(t, err)
+
+	c := m.Clone()
+	assert.Equal(t, m.Width, c.Width)
+	if !assert.Len(t, c.Tilesets, len(m.Tilesets)) {
+		return
+	}
+	for i, ts := range c.Tilesets {
+		assert.NotSame(t, m.Tilesets[i], ts)
+		assert.Equal(t, m.Tilesets[i].Name, ts.Name)
+	}
+
+	for i, l := range c.Layers {
+		assert.NotSame(t, m.Layers[i], l)
+		assert.Same(t, c, l._map)
+		for j, tile := range l.Tiles {
+			if tile.IsNil() {
+				continue
+			}
+			assert.NotSame(t, m.Layers[i].Tiles[j], tile)
+			assert.Contains(t, c.Tilesets, tile.Tileset)
+		}
+	}
+
+	for i, g := range c.ObjectGroups {
+		for j, o := range g.Objects {
+			orig := m.ObjectGroups[i].Objects[j]
+			assert.NotSame(t, orig, o)
+			if orig.Template != nil {
+				assert.NotSame(t, orig.Template, o.Template)
+				assert.Same(t, c.templates[c.GetFileFullPath(orig.TemplateSource)], o.Template)
+			}
+		}
+	}
+
+	// Changing the copy leaves the original as is
+	c.Tilesets[0].Name = "changed"
+	if len(c.ObjectGroups) > 0 && len(c.ObjectGroups[0].Objects) > 0 {
+		c.ObjectGroups[0].Objects[0].Name = "changed"
+		assert.NotEqual(t, "changed", m.ObjectGroups[0].Objects[0].Name)
+	}
+	assert.NotEqual(t, "changed", m.Tilesets[0].Name)
+}
+
+func TestLayerAndTilesetClone(t *testing.T) {
+	m, err := LoadFile(filepath.Join(GetAssetsDirectory(), "racing.tmx"))
+	assert.NoError(t, err)
+
+	l := m.Layers[0]
+	l.Properties = Properties{{Name: "solid", Type: "bool", Value: "true"}}
+	c := l.Clone()
+	assert.Same(t, l._map, c._map)
+	assert.Equal(t, len(l.Tiles), len(c.Tiles))
+	for i, tile := range c.Tiles {
+		if !tile.IsNil() {
+			assert.Same(t, l.Tiles[i].Tileset, tile.Tileset)
+			tile.ID++
+			assert.NotEqual(t, l.Tiles[i].ID, tile.ID)
+			break
+		}
+	}
+	c.Properties[0].Value = "false"
+	assert.True(t, l.Properties.GetBool("solid"))
+
+	ts := m.Tilesets[0]
+	tc := ts.Clone()
+	assert.Equal(t, ts.Name, tc.Name)
+	assert.Equal(t, len(ts.Tiles), len(tc.Tiles))
+	if ts.Image != nil {
+		assert.NotSame(t, ts.Image, tc.Image)
+		assert.Equal(t, *ts.Image, *tc.Image)
+	}
+}
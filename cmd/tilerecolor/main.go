@@ -0,0 +1,350 @@
+// Tool to bake a recolored variant of a tileset, such as a biome variant.
+//
+// Usage:
+//
+//	tilerecolor [flags] tileset.tsx [map.tmx...]
+//
+// The images of the tileset are recolored with a palette mapping and/or an HSL
+// shift and written as PNG next to the originals, along with a new TSX file
+// referencing them. The given maps are updated in place to use the new
+// tileset.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+)
+
+func main() {
+	suffix := flag.String("suffix", "variant", "name appended to the new tileset and image files")
+	palette := flag.String("palette", "", "file of \"#rrggbb #rrggbb\" lines mapping colors to new ones")
+	hue := flag.Float64("hue", 0, "hue shift in degrees")
+	saturation := flag.Float64("saturation", 0, "saturation shift, from -1 to 1")
+	lightness := flag.Float64("lightness", 0, "lightness shift, from -1 to 1")
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		fmt.Println("usage: tilerecolor [flags] tileset.tsx [map.tmx...]")
+		flag.PrintDefaults()
+		return
+	}
+
+	r := &recolor{hue: *hue, saturation: *saturation, lightness: *lightness}
+	if *palette != "" {
+		var err error
+		if r.palette, err = loadPalette(*palette); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+
+	tsx := flag.Arg(0)
+	variant, err := bakeTileset(tsx, *suffix, r)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	for _, m := range flag.Args()[1:] {
+		if err := updateMap(m, tsx, variant); err != nil {
+			fmt.Println(err)
+			return
+		}
+	}
+}
+
+// recolor maps colors through a palette then shifts them in HSL space
+type recolor struct {
+	palette                    map[color.NRGBA]color.NRGBA
+	hue, saturation, lightness float64
+}
+
+func parseColor(s string) (color.NRGBA, error) {
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+	if err != nil || len(strings.TrimPrefix(s, "#")) != 6 {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+func loadPalette(fileName string) (map[color.NRGBA]color.NRGBA, error) {
+	f, err := os.Open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	palette := map[color.NRGBA]color.NRGBA{}
+	s := bufio.NewScanner(f)
+	for line := 1; s.Scan(); line++ {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "//") {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%s:%d: expected two colors", fileName, line)
+		}
+		from, err := parseColor(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", fileName, line, err)
+		}
+		to, err := parseColor(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", fileName, line, err)
+		}
+		palette[from] = to
+	}
+	return palette, s.Err()
+}
+
+func (r *recolor) color(c color.NRGBA) color.NRGBA {
+	if c.A == 0 {
+		return c
+	}
+
+	if to, ok := r.palette[color.NRGBA{R: c.R, G: c.G, B: c.B, A: 0xff}]; ok {
+		c.R, c.G, c.B = to.R, to.G, to.B
+	}
+
+	if r.hue == 0 && r.saturation == 0 && r.lightness == 0 {
+		return c
+	}
+	h, s, l := toHSL(c)
+	h = math.Mod(h+r.hue/360+1, 1)
+	s = clamp(s + r.saturation)
+	l = clamp(l + r.lightness)
+	c.R, c.G, c.B = fromHSL(h, s, l)
+	return c
+}
+
+func (r *recolor) image(src image.Image) *image.NRGBA {
+	dst := image.NewNRGBA(src.Bounds())
+	draw.Draw(dst, dst.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	for y := dst.Rect.Min.Y; y < dst.Rect.Max.Y; y++ {
+		for x := dst.Rect.Min.X; x < dst.Rect.Max.X; x++ {
+			dst.SetNRGBA(x, y, r.color(dst.NRGBAAt(x, y)))
+		}
+	}
+	return dst
+}
+
+func clamp(v float64) float64 {
+	return max(0, min(v, 1))
+}
+
+func toHSL(c color.NRGBA) (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	hi, lo := max(r, g, b), min(r, g, b)
+	l = (hi + lo) / 2
+	if hi == lo {
+		return 0, 0, l
+	}
+
+	d := hi - lo
+	if l > 0.5 {
+		s = d / (2 - hi - lo)
+	} else {
+		s = d / (hi + lo)
+	}
+	switch hi {
+	case r:
+		h = (g - b) / d
+		if g < b {
+			h += 6
+		}
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h / 6, s, l
+}
+
+func fromHSL(h, s, l float64) (uint8, uint8, uint8) {
+	if s == 0 {
+		v := uint8(math.Round(l * 255))
+		return v, v, v
+	}
+
+	var q float64
+	if l < 0.5 {
+		q = l * (1 + s)
+	} else {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+
+	channel := func(t float64) uint8 {
+		t = math.Mod(t+1, 1)
+		var v float64
+		switch {
+		case t < 1.0/6:
+			v = p + (q-p)*6*t
+		case t < 1.0/2:
+			v = q
+		case t < 2.0/3:
+			v = p + (q-p)*(2.0/3-t)*6
+		default:
+			v = p
+		}
+		return uint8(math.Round(v * 255))
+	}
+	return channel(h + 1.0/3), channel(h), channel(h - 1.0/3)
+}
+
+// variantName returns the name of the variant of a file
+func variantName(fileName, suffix, ext string) string {
+	return strings.TrimSuffix(fileName, filepath.Ext(fileName)) + "_" + suffix + ext
+}
+
+// rewriteXML copies an XML document token by token, letting rewrite change
+// the attributes of its elements. Everything else is kept as it is.
+func rewriteXML(data []byte, rewrite func(*xml.StartElement) error) ([]byte, error) {
+	var buf bytes.Buffer
+	d := xml.NewDecoder(bytes.NewReader(data))
+	e := xml.NewEncoder(&buf)
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			start.Attr = append([]xml.Attr(nil), start.Attr...)
+			if err := rewrite(&start); err != nil {
+				return nil, err
+			}
+			tok = start
+		}
+		if err := e.EncodeToken(tok); err != nil {
+			return nil, err
+		}
+	}
+	if err := e.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// attr returns the attribute of an element with the given name, or nil
+func attr(start *xml.StartElement, name string) *xml.Attr {
+	for i := range start.Attr {
+		if start.Attr[i].Name.Space == "" && start.Attr[i].Name.Local == name {
+			return &start.Attr[i]
+		}
+	}
+	return nil
+}
+
+// bakeTileset writes the recolored images and TSX file of a tileset and
+// returns the name of the new TSX file.
+func bakeTileset(tsx, suffix string, r *recolor) (string, error) {
+	if _, err := tiled.LoadTilesetFile(tsx); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(tsx)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Dir(tsx)
+	data, err = rewriteXML(data, func(start *xml.StartElement) error {
+		source := attr(start, "source")
+		if start.Name.Local != "image" || source == nil {
+			return nil
+		}
+		original := filepath.FromSlash(source.Value)
+		variant := variantName(original, suffix, ".png")
+		if err := bakeImage(filepath.Join(dir, original), filepath.Join(dir, variant), r); err != nil {
+			return err
+		}
+		source.Value = filepath.ToSlash(variant)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", tsx, err)
+	}
+
+	variant := variantName(tsx, suffix, filepath.Ext(tsx))
+	return variant, os.WriteFile(variant, data, 0o644)
+}
+
+func bakeImage(src, dst string, r *recolor) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", src, err)
+	}
+
+	w, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if err := png.Encode(w, r.image(img)); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// updateMap makes a map use the variant instead of the original tileset
+func updateMap(fileName, tsx, variant string) error {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+
+	dir := filepath.Dir(fileName)
+	original, err := filepath.Abs(tsx)
+	if err != nil {
+		return err
+	}
+
+	data, err = rewriteXML(data, func(start *xml.StartElement) error {
+		source := attr(start, "source")
+		if start.Name.Local != "tileset" || source == nil {
+			return nil
+		}
+		path, err := filepath.Abs(filepath.Join(dir, filepath.FromSlash(source.Value)))
+		if err != nil || path != original {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, variant)
+		if err != nil {
+			return err
+		}
+		source.Value = filepath.ToSlash(rel)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	return os.WriteFile(fileName, data, 0o644)
+}
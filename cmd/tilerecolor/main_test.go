@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/stretchr/testify/assert"
+)
+
+// copyAsset copies a file of the assets directory to the same path in dir
+func copyAsset(t *testing.T, dir, name string) string {
+	data, err := os.ReadFile(filepath.Join("..", "..", "assets", name))
+	assert.NoError(t, err)
+	dst := filepath.Join(dir, name)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(dst), 0o755))
+	assert.NoError(t, os.WriteFile(dst, data, 0o644))
+	return dst
+}
+
+func TestRecolorFixture(t *testing.T) {
+	dir := t.TempDir()
+	tmx := copyAsset(t, dir, "test_wangsets_map.tmx")
+	tsx := copyAsset(t, dir, "tilesets/test_wangset_tileset.tsx")
+	copyAsset(t, dir, "tilesets/RPG_Nature_Tileset.png")
+
+	r := &recolor{hue: 180}
+	variant, err := bakeTileset(tsx, "winter", r)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "tilesets", "test_wangset_tileset_winter.tsx"), variant)
+	assert.FileExists(t, filepath.Join(dir, "tilesets", "RPG_Nature_Tileset_winter.png"))
+
+	original, err := tiled.LoadTilesetFile(tsx)
+	assert.NoError(t, err)
+	baked, err := tiled.LoadTilesetFile(variant)
+	assert.NoError(t, err)
+	assert.Equal(t, "RPG_Nature_Tileset_winter.png", baked.Image.Source)
+	// Only the image source changes
+	assert.Equal(t, original.Name, baked.Name)
+	assert.Equal(t, original.TileCount, baked.TileCount)
+	assert.Equal(t, len(original.WangSets), len(baked.WangSets))
+	assert.Equal(t, len(original.Tiles), len(baked.Tiles))
+
+	before, err := tiled.LoadFile(tmx)
+	assert.NoError(t, err)
+	assert.NoError(t, updateMap(tmx, tsx, variant))
+	after, err := tiled.LoadFile(tmx)
+	assert.NoError(t, err)
+	assert.Equal(t, "tilesets/test_wangset_tileset_winter.tsx", after.Tilesets[0].Source)
+	assert.Equal(t, "RPG_Nature_Tileset_winter.png", after.Tilesets[0].Image.Source)
+	assert.Equal(t, len(before.Layers), len(after.Layers))
+	for i := range before.Layers {
+		for j := range before.Layers[i].Tiles {
+			assert.Equal(t, before.Layers[i].Tiles[j].ID, after.Layers[i].Tiles[j].ID)
+		}
+	}
+
+	// Maps using other tilesets are left untouched
+	data, err := os.ReadFile(tmx)
+	assert.NoError(t, err)
+	assert.NoError(t, updateMap(tmx, filepath.Join(dir, "other.tsx"), variant))
+	unchanged, err := os.ReadFile(tmx)
+	assert.NoError(t, err)
+	assert.Equal(t, string(data), string(unchanged))
+}
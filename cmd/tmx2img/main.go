@@ -25,6 +25,10 @@ func main() {
 		return
 	}
 
+	for _, warning := range render.UnsupportedFeatures(m) {
+		fmt.Println("warning:", warning)
+	}
+
 	rend, err := render.NewRenderer(m)
 	if err != nil {
 		fmt.Println(err)
@@ -0,0 +1,62 @@
+// Tool to render the visual difference between two versions of a TMX file.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image/png"
+	"os"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/Tsukumogami-Software/go-tiled/render"
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: tmxdiff old.tmx new.tmx [diff.png]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() < 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	img := flag.Arg(2)
+	if img == "" {
+		img = "diff.png"
+	}
+
+	before, err := tiled.LoadFile(flag.Arg(0))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	after, err := tiled.LoadFile(flag.Arg(1))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	diff, err := render.DiffMaps(before, after)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	w, err := os.Create(img)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer w.Close()
+	if err := png.Encode(w, diff.Image); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("%d pixels changed in %d tiles\n", diff.ChangedPixels, len(diff.ChangedTiles))
+	for _, t := range diff.ChangedTiles {
+		fmt.Printf("  %d,%d\n", t.X, t.Y)
+	}
+}
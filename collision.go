@@ -0,0 +1,278 @@
+package tiled
+
+import "math"
+
+// CollisionKind describes how a tile collides, following conventional tile
+// classes used by platformers
+type CollisionKind string
+
+const (
+	// CollisionSolid blocks from all sides. Tiles with collision shapes but
+	// no other kind are solid.
+	CollisionSolid CollisionKind = "solid"
+	// CollisionOneWay only blocks from above, like platforms that can be
+	// jumped through
+	CollisionOneWay CollisionKind = "oneway"
+	// CollisionLadder can be climbed and doesn't block
+	CollisionLadder CollisionKind = "ladder"
+	// CollisionSlope is a ground whose height goes linearly from its left
+	// edge to its right edge
+	CollisionSlope CollisionKind = "slope"
+)
+
+const (
+	// SlopeLeftProperty is the number tile property holding the height in
+	// pixels of the left edge of a slope, from the bottom of the tile
+	SlopeLeftProperty = "slopeleft"
+	// SlopeRightProperty is the number tile property holding the height in
+	// pixels of the right edge of a slope, from the bottom of the tile
+	SlopeRightProperty = "sloperight"
+)
+
+var collisionKinds = []CollisionKind{CollisionSolid, CollisionOneWay, CollisionLadder, CollisionSlope}
+
+// Collider describes the collision of a single tile of a layer
+type Collider struct {
+	Kind CollisionKind
+	// Position of the tile in the layer, in tiles
+	X, Y int
+	// Area of the tile in map pixels
+	Bounds Rectangle
+	// Height of the left and right edges of a slope from the bottom of the
+	// tile, in pixels. Horizontally flipped tiles have their heights swapped.
+	SlopeLeft, SlopeRight float64
+	// Collision shapes of the tile, positioned in map pixels, flips of the
+	// tile applied
+	Objects []*Object
+	// The tile
+	Tile *LayerTile
+}
+
+// collisionKind finds the kind of a tile from its class, or from a true bool
+// property named after a kind. Tiles with collision shapes default to
+// CollisionSolid, others have no collision.
+func collisionKind(t *TilesetTile) CollisionKind {
+	class := t.Class
+	if class == "" {
+		class = t.Type
+	}
+	for _, kind := range collisionKinds {
+		if class == string(kind) {
+			return kind
+		}
+	}
+	for _, kind := range collisionKinds {
+		if t.Properties.GetBool(string(kind)) {
+			return kind
+		}
+	}
+	for _, g := range t.ObjectGroups {
+		if len(g.Objects) > 0 {
+			return CollisionSolid
+		}
+	}
+	return ""
+}
+
+// number reads an int or float property
+func number(p Properties, name string) float64 {
+	if v := p.GetFloat(name); v != 0 {
+		return v
+	}
+	return float64(p.GetInt(name))
+}
+
+// Colliders returns the collision of each tile of the layer with a known
+// CollisionKind, row by row.
+func (l *Layer) Colliders() []*Collider {
+	var res []*Collider
+
+	for cell, tile := range l.Cells() {
+		if tile.Tileset == nil {
+			continue
+		}
+		t := tile.Tileset.Tile(tile.ID)
+		if t == nil {
+			continue
+		}
+		kind := collisionKind(t)
+		if kind == "" {
+			continue
+		}
+
+		px := l.OffsetX + cell.X*l._map.TileWidth
+		py := l.OffsetY + cell.Y*l._map.TileHeight
+		// Tiles are aligned to the bottom left of their cell
+		w, h := tile.Tileset.TileWidth, tile.Tileset.TileHeight
+		py += l._map.TileHeight - h
+
+		c := &Collider{
+			Kind: kind,
+			X:    cell.X,
+			Y:    cell.Y,
+			Bounds: Rectangle{
+				Min: Point{X: float64(px), Y: float64(py)},
+				Max: Point{X: float64(px + w), Y: float64(py + h)},
+			},
+			Tile: tile,
+		}
+
+		if kind == CollisionSlope {
+			c.SlopeLeft = number(t.Properties, SlopeLeftProperty)
+			c.SlopeRight = number(t.Properties, SlopeRightProperty)
+			if tile.HorizontalFlip {
+				c.SlopeLeft, c.SlopeRight = c.SlopeRight, c.SlopeLeft
+			}
+		}
+
+		for _, o := range tile.CollisionObjects() {
+			o.X += float64(px)
+			o.Y += float64(py)
+			c.Objects = append(c.Objects, o)
+		}
+
+		res = append(res, c)
+	}
+
+	return res
+}
+
+// HeightAt returns the height of a slope from the bottom of the tile at x
+// pixels from its left edge. Other kinds have the height of their tile.
+func (c *Collider) HeightAt(x float64) float64 {
+	if c.Kind != CollisionSlope {
+		return c.Bounds.Height()
+	}
+	w := c.Bounds.Width()
+	if w == 0 {
+		return c.SlopeLeft
+	}
+	f := x / w
+	if f < 0 {
+		f = 0
+	} else if f > 1 {
+		f = 1
+	}
+	return c.SlopeLeft + (c.SlopeRight-c.SlopeLeft)*f
+}
+
+// CollisionObjects returns the collision shapes drawn on the tile in the
+// tileset editor, from all its object groups, relative to the top left
+// corner of the tile.
+func (t *TilesetTile) CollisionObjects() []*Object {
+	var res []*Object
+	for _, g := range t.ObjectGroups {
+		res = append(res, g.Objects...)
+	}
+	return res
+}
+
+// CollisionObjects returns copies of the collision shapes of the tile,
+// relative to the top left corner of the tile and mirrored by its flips like
+// its image.
+func (t *LayerTile) CollisionObjects() []*Object {
+	if t == nil || t.IsNil() || t.Tileset == nil {
+		return nil
+	}
+	tilesetTile := t.Tileset.Tile(t.ID)
+	if tilesetTile == nil {
+		return nil
+	}
+
+	objects := tilesetTile.CollisionObjects()
+	res := make([]*Object, len(objects))
+	for i, o := range objects {
+		flipped := *o
+		width, height := float64(t.Tileset.TileWidth), float64(t.Tileset.TileHeight)
+		// Like images, the diagonal flip comes first
+		if t.DiagonalFlip {
+			mirrorObject(&flipped, diagonalMirror, width, height)
+			width, height = height, width
+		}
+		if t.HorizontalFlip {
+			mirrorObject(&flipped, horizontalMirror, width, height)
+		}
+		if t.VerticalFlip {
+			mirrorObject(&flipped, verticalMirror, width, height)
+		}
+		res[i] = &flipped
+	}
+	return res
+}
+
+type mirror int
+
+const (
+	// Swaps the x and y axis
+	diagonalMirror mirror = iota
+	// Mirrors across the vertical middle line
+	horizontalMirror
+	// Mirrors across the horizontal middle line
+	verticalMirror
+)
+
+// apply mirrors a point relative to a tile of the given size, or a vector
+// relative to the origin when width and height are 0
+func (m mirror) apply(p Point, width, height float64) Point {
+	switch m {
+	case diagonalMirror:
+		return Point{X: p.Y, Y: p.X}
+	case horizontalMirror:
+		return Point{X: width - p.X, Y: p.Y}
+	}
+	return Point{X: p.X, Y: height - p.Y}
+}
+
+// mirrorObject mirrors o, a shallow copy, within a tile of the given size.
+// Mirroring reverses the rotation. Rectangles and ellipses are moved to the
+// corner that becomes their top left one, so they keep a positive size.
+func mirrorObject(o *Object, m mirror, width, height float64) {
+	origin := m.apply(Point{X: o.X, Y: o.Y}, width, height)
+	o.X, o.Y = origin.X, origin.Y
+	o.Rotation = -o.Rotation
+
+	mirrorPoints := func(points *Points) *Points {
+		if points == nil {
+			return nil
+		}
+		res := make(Points, len(*points))
+		for i, p := range *points {
+			q := m.apply(*p, 0, 0)
+			res[i] = &q
+		}
+		return &res
+	}
+	switch {
+	case len(o.Polygons) > 0:
+		polygons := make([]*Polygon, len(o.Polygons))
+		for i, p := range o.Polygons {
+			polygons[i] = &Polygon{Points: mirrorPoints(p.Points)}
+		}
+		o.Polygons = polygons
+		return
+	case len(o.PolyLines) > 0:
+		polyLines := make([]*PolyLine, len(o.PolyLines))
+		for i, p := range o.PolyLines {
+			polyLines[i] = &PolyLine{Points: mirrorPoints(p.Points)}
+		}
+		o.PolyLines = polyLines
+		return
+	case o.IsPoint():
+		return
+	}
+
+	// The corner becoming the top left one, relative to the origin
+	var corner Point
+	switch m {
+	case diagonalMirror:
+		o.Width, o.Height = o.Height, o.Width
+		return
+	case horizontalMirror:
+		corner = Point{X: -o.Width}
+	case verticalMirror:
+		corner = Point{Y: -o.Height}
+	}
+	sin, cos := math.Sincos(o.Rotation * math.Pi / 180)
+	o.X += corner.X*cos - corner.Y*sin
+	o.Y += corner.X*sin + corner.Y*cos
+}
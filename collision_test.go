@@ -0,0 +1,119 @@
+package tiled
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const collisionTestMap = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="4" height="1" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="platforms" tilewidth="16" tileheight="16" tilecount="5" columns="5">
+<image source="platforms.png" width="80" height="16"/>
+<tile id="1" class="oneway"/>
+<tile id="2">
+<properties><property name="ladder" type="bool" value="true"/></properties>
+</tile>
+<tile id="3" class="slope">
+<properties>
+<property name="slopeleft" type="int" value="0"/>
+<property name="sloperight" type="float" value="8"/>
+</properties>
+</tile>
+<tile id="4">
+<objectgroup draworder="index">
+<object id="1" x="0" y="8" width="16" height="8"/>
+</objectgroup>
+</tile>
+</tileset>
+<layer id="1" name="Ground" width="4" height="1">
+<data encoding="csv">2,3,2147483652,5</data>
+</layer>
+</map>`
+
+func TestLayerColliders(t *testing.T) {
+	m, err := LoadReader(GetAssetsDirectory(), bytes.NewBufferString(collisionTestMap))
+	assert.NoError(t, err)
+
+	colliders := m.Layers[0].Colliders()
+	if !assert.Len(t, colliders, 4) {
+		return
+	}
+
+	assert.Equal(t, CollisionOneWay, colliders[0].Kind)
+	assert.Equal(t, Rectangle{Min: Point{X: 0, Y: 0}, Max: Point{X: 16, Y: 16}}, colliders[0].Bounds)
+	assert.Equal(t, CollisionLadder, colliders[1].Kind)
+	assert.Equal(t, 1, colliders[1].X)
+
+	slope := colliders[2]
+	assert.Equal(t, CollisionSlope, slope.Kind)
+	// Horizontally flipped
+	assert.Equal(t, 8.0, slope.SlopeLeft)
+	assert.Equal(t, 0.0, slope.SlopeRight)
+	assert.Equal(t, 4.0, slope.HeightAt(8))
+
+	solid := colliders[3]
+	assert.Equal(t, CollisionSolid, solid.Kind)
+	if assert.Len(t, solid.Objects, 1) {
+		assert.Equal(t, 48.0, solid.Objects[0].X)
+		assert.Equal(t, 8.0, solid.Objects[0].Y)
+	}
+	assert.Equal(t, 16.0, solid.HeightAt(0))
+}
+
+func TestLayerTileCollisionObjects(t *testing.T) {
+	ts := &Tileset{TileWidth: 16, TileHeight: 16, Tiles: []*TilesetTile{{
+		ID: 1,
+		ObjectGroups: []*ObjectGroup{{Objects: []*Object{
+			{ID: 1, X: 2, Y: 4, Width: 6, Height: 4},
+			{ID: 2, X: 2, Y: 2, Polygons: []*Polygon{{Points: &Points{{0, 0}, {4, 0}, {0, 4}}}}},
+			{ID: 3, Width: 4, Height: 2, Rotation: 90},
+		}}},
+	}}}
+	assert.Len(t, ts.Tiles[0].CollisionObjects(), 3)
+
+	objects := (&LayerTile{ID: 1, Tileset: ts}).CollisionObjects()
+	assert.Equal(t, 2.0, objects[0].X)
+	assert.NotSame(t, ts.Tiles[0].ObjectGroups[0].Objects[0], objects[0])
+
+	objects = (&LayerTile{ID: 1, Tileset: ts, HorizontalFlip: true}).CollisionObjects()
+	assert.Equal(t, Rectangle{Min: Point{X: 8, Y: 4}, Max: Point{X: 14, Y: 8}}, objects[0].BoundingBox())
+	assert.Equal(t, 14.0, objects[1].X)
+	assert.Equal(t, &Points{{0, 0}, {-4, 0}, {0, 4}}, objects[1].Polygons[0].Points)
+	assert.Equal(t, -90.0, objects[2].Rotation)
+	box := objects[2].BoundingBox()
+	assert.InDelta(t, 16, box.Min.X, 1e-9)
+	assert.InDelta(t, 18, box.Max.X, 1e-9)
+	assert.InDelta(t, 4, box.Max.Y, 1e-9)
+	// The shapes of the tileset are left untouched
+	assert.Equal(t, &Points{{0, 0}, {4, 0}, {0, 4}}, ts.Tiles[0].ObjectGroups[0].Objects[1].Polygons[0].Points)
+
+	objects = (&LayerTile{ID: 1, Tileset: ts, VerticalFlip: true}).CollisionObjects()
+	assert.Equal(t, Rectangle{Min: Point{X: 2, Y: 8}, Max: Point{X: 8, Y: 12}}, objects[0].BoundingBox())
+
+	objects = (&LayerTile{ID: 1, Tileset: ts, DiagonalFlip: true, HorizontalFlip: true}).CollisionObjects()
+	assert.Equal(t, Rectangle{Min: Point{X: 8, Y: 2}, Max: Point{X: 12, Y: 8}}, objects[0].BoundingBox())
+
+	assert.Nil(t, (&LayerTile{ID: 0, Tileset: ts}).CollisionObjects())
+}
+
+func TestLayerHeightfield(t *testing.T) {
+	m, err := LoadReader(GetAssetsDirectory(), bytes.NewBufferString(collisionTestMap))
+	assert.NoError(t, err)
+
+	h := m.Layers[0].Heightfield()
+	assert.Len(t, h, 64)
+
+	// One-way platform
+	assert.Equal(t, 0.0, h[0])
+	// Ladder
+	assert.True(t, math.IsInf(h[20], 1))
+	// Slope going down from 8 pixels high on the left
+	assert.Equal(t, 8.25, h[32])
+	assert.Equal(t, 15.75, h[47])
+	assert.InDelta(t, 12.0, h.At(40), 1e-9)
+	// Collision shape
+	assert.Equal(t, 8.0, h[50])
+}
@@ -0,0 +1,86 @@
+package tiled
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// debugMap is the JSON form of a map written by DebugJSON. Fields of the
+// embedded model are shadowed where the form differs.
+type debugMap struct {
+	*Map
+	Layers   []*debugLayer
+	Groups   []*debugGroup
+	Tilesets []*debugTileset
+}
+
+type debugLayer struct {
+	*Layer
+	// GIDs of the tiles, flip flags included, row by row
+	Tiles []uint32
+	// Number of tiles used, by tileset name
+	TileCounts map[string]int
+}
+
+type debugGroup struct {
+	*Group
+	Layers []*debugLayer
+	Groups []*debugGroup
+}
+
+type debugTileset struct {
+	*Tileset
+	BaseDir string
+	// Number of cells of tile layers using each tile, by tile ID
+	TileUses map[uint32]int `json:",omitempty"`
+}
+
+// DebugJSON writes the whole decoded map as indented JSON, along with values
+// computed while loading such as the GIDs of tile layers, the number of
+// tiles used from each tileset and the directories of tilesets, so tools
+// written in other languages and tests can inspect what was parsed.
+func (m *Map) DebugJSON(w io.Writer) error {
+	tilesets := make(map[*Tileset]*debugTileset, len(m.Tilesets))
+	dm := &debugMap{Map: m}
+	for _, ts := range m.Tilesets {
+		dts := &debugTileset{Tileset: ts, BaseDir: ts.BaseDir()}
+		tilesets[ts] = dts
+		dm.Tilesets = append(dm.Tilesets, dts)
+	}
+
+	debugLayers := func(layers []*Layer) []*debugLayer {
+		res := make([]*debugLayer, 0, len(layers))
+		for _, l := range layers {
+			dl := &debugLayer{Layer: l, Tiles: make([]uint32, len(l.Tiles)), TileCounts: map[string]int{}}
+			for i, t := range l.Tiles {
+				dl.Tiles[i] = t.gid()
+				if t.IsNil() || t.Tileset == nil {
+					continue
+				}
+				dl.TileCounts[t.Tileset.Name]++
+				if dts := tilesets[t.Tileset]; dts != nil {
+					if dts.TileUses == nil {
+						dts.TileUses = map[uint32]int{}
+					}
+					dts.TileUses[t.ID]++
+				}
+			}
+			res = append(res, dl)
+		}
+		return res
+	}
+	var debugGroups func(groups []*Group) []*debugGroup
+	debugGroups = func(groups []*Group) []*debugGroup {
+		res := make([]*debugGroup, 0, len(groups))
+		for _, g := range groups {
+			res = append(res, &debugGroup{Group: g, Layers: debugLayers(g.Layers), Groups: debugGroups(g.Groups)})
+		}
+		return res
+	}
+	dm.Layers = debugLayers(m.Layers)
+	dm.Groups = debugGroups(m.Groups)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(dm)
+}
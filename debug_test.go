@@ -0,0 +1,49 @@
+package tiled
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDebugJSON(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="1" tilewidth="16" tileheight="16" backgroundcolor="#ff0000">
+<tileset firstgid="1" name="tiles" tilewidth="16" tileheight="16" tilecount="4" columns="4"/>
+<layer id="1" name="Ground" width="2" height="1"><data encoding="csv">2,2147483650</data></layer>
+<group id="2" name="Details">
+<layer id="3" name="Decals" width="2" height="1"><data encoding="csv">0,4</data></layer>
+</group>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	var buf bytes.Buffer
+	assert.NoError(t, m.DebugJSON(&buf))
+
+	var dump struct {
+		BackgroundColor string
+		Layers          []struct {
+			Name       string
+			Tiles      []uint32
+			TileCounts map[string]int
+		}
+		Groups []struct {
+			Layers []struct{ Tiles []uint32 }
+		}
+		Tilesets []struct {
+			Name     string
+			BaseDir  string
+			TileUses map[string]int
+		}
+	}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &dump))
+	assert.Equal(t, "#ff0000", dump.BackgroundColor)
+	assert.Equal(t, []uint32{2, 2147483650}, dump.Layers[0].Tiles)
+	assert.Equal(t, map[string]int{"tiles": 2}, dump.Layers[0].TileCounts)
+	assert.Equal(t, []uint32{0, 4}, dump.Groups[0].Layers[0].Tiles)
+	assert.Equal(t, ".", dump.Tilesets[0].BaseDir)
+	assert.Equal(t, map[string]int{"1": 2, "3": 1}, dump.Tilesets[0].TileUses)
+}
@@ -4,6 +4,7 @@ package tiled_test
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/Tsukumogami-Software/go-tiled"
 )
@@ -57,3 +58,25 @@ func ExampleWangSet_GetWangColors() {
 	//   BottomLeft:  Water
 	//   TopLeft:     Rock
 }
+
+func ExampleLoadReader() {
+	// A map received over the network, referencing a tileset by a path
+	// relative to the assets directory
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="32" tileheight="32">
+ <tileset firstgid="1" source="tilesets/test2.tsx"/>
+ <layer id="1" name="Ground" width="1" height="1"><data encoding="csv">117</data></layer>
+</map>`
+
+	tiledMap, err := tiled.LoadReader("assets", strings.NewReader(tmx))
+	if err != nil {
+		fmt.Printf("error parsing tiledMap: %s", err.Error())
+		os.Exit(2)
+	}
+
+	tile := tiledMap.Layers[0].Tiles[0]
+	fmt.Println(tile.Tileset.Name, tile.ID)
+
+	// Output:
+	// ProjectUtumno_full 116
+}
@@ -0,0 +1,121 @@
+package tiled
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ExtensionPrefix prefixes the names of the properties this package gives a
+// meaning to, so they do not collide with the properties of a game. The
+// conventions older than the prefix, such as DefaultPathSpeedProperty, keep
+// their names and are registered as is.
+const ExtensionPrefix = "x-"
+
+const (
+	// ZOffsetProperty is the int property offsetting the draw order of a
+	// layer or object relative to its siblings
+	ZOffsetProperty = ExtensionPrefix + "zoffset"
+	// XDevOnlyProperty is the prefixed form of DevOnlyProperty
+	XDevOnlyProperty = ExtensionPrefix + DevOnlyProperty
+	// XEditorOnlyProperty is the prefixed form of EditorOnlyProperty
+	XEditorOnlyProperty = ExtensionPrefix + EditorOnlyProperty
+	// ShaderProperty is the string property naming the shader a layer or
+	// object is drawn with
+	ShaderProperty = ExtensionPrefix + "shader"
+	// ScrollXProperty and ScrollYProperty are the float properties holding
+	// the speed in pixels per second a layer scrolls at
+	ScrollXProperty = ExtensionPrefix + "scrollx"
+	ScrollYProperty = ExtensionPrefix + "scrolly"
+)
+
+var (
+	extensionsMu sync.RWMutex
+	extensions   = map[string]string{
+		ZOffsetProperty:     "int",
+		XDevOnlyProperty:    "bool",
+		XEditorOnlyProperty: "bool",
+		ShaderProperty:      "string",
+		ScrollXProperty:     "float",
+		ScrollYProperty:     "float",
+
+		DefaultAnimationGroupProperty:  "string",
+		DefaultAnimationOffsetProperty: "int",
+		DefaultPathSpeedProperty:       "float",
+		DefaultPathModeProperty:        "string",
+		SlopeLeftProperty:              "float",
+		SlopeRightProperty:             "float",
+	}
+)
+
+// RegisterExtensionProperty registers an extension property of the given
+// Tiled type, so conventions added by other packages are listed by
+// ExtensionProperties. It panics if name lacks ExtensionPrefix or is already
+// registered.
+func RegisterExtensionProperty(name, typ string) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	if !strings.HasPrefix(name, ExtensionPrefix) {
+		panic("tiled: RegisterExtensionProperty name lacks prefix " + ExtensionPrefix + ": " + name)
+	}
+	if _, dup := extensions[name]; dup {
+		panic("tiled: RegisterExtensionProperty called twice for property " + name)
+	}
+	extensions[name] = typ
+}
+
+// unregisterExtensionProperty removes a registered extension property, so
+// tests can register theirs without leaking them
+func unregisterExtensionProperty(name string) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	delete(extensions, name)
+}
+
+// ExtensionProperties returns the names of the registered extension
+// properties, sorted
+func ExtensionProperties() []string {
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ExtensionPropertyType returns the Tiled type of a registered extension
+// property, and whether it is registered
+func ExtensionPropertyType(name string) (string, bool) {
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+	typ, ok := extensions[name]
+	return typ, ok
+}
+
+// ZOffset returns the ZOffsetProperty, 0 when unset
+func (p Properties) ZOffset() int {
+	return p.GetInt(ZOffsetProperty)
+}
+
+// DevOnly returns whether the XDevOnlyProperty or DevOnlyProperty is true
+func (p Properties) DevOnly() bool {
+	return p.GetBool(XDevOnlyProperty) || p.GetBool(DevOnlyProperty)
+}
+
+// EditorOnly returns whether the XEditorOnlyProperty or EditorOnlyProperty
+// is true
+func (p Properties) EditorOnly() bool {
+	return p.GetBool(XEditorOnlyProperty) || p.GetBool(EditorOnlyProperty)
+}
+
+// Shader returns the ShaderProperty, empty when unset
+func (p Properties) Shader() string {
+	return p.GetString(ShaderProperty)
+}
+
+// Scroll returns the ScrollXProperty and ScrollYProperty, 0 when unset
+func (p Properties) Scroll() (x, y float64) {
+	return p.GetFloat(ScrollXProperty), p.GetFloat(ScrollYProperty)
+}
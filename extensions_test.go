@@ -0,0 +1,43 @@
+package tiled
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtensionProperties(t *testing.T) {
+	props := Properties{
+		{Name: "x-zoffset", Type: "int", Value: "-2"},
+		{Name: "x-shader", Value: "water"},
+		{Name: "x-scrollx", Type: "float", Value: "1.5"},
+		{Name: "devonly", Type: "bool", Value: "true"},
+		{Name: "x-editoronly", Type: "bool", Value: "true"},
+	}
+	assert.Equal(t, -2, props.ZOffset())
+	assert.Equal(t, "water", props.Shader())
+	x, y := props.Scroll()
+	assert.Equal(t, 1.5, x)
+	assert.Equal(t, 0.0, y)
+	assert.True(t, props.DevOnly())
+	assert.True(t, props.EditorOnly())
+	assert.False(t, Properties(nil).DevOnly())
+
+	RegisterExtensionProperty("x-test-footstep", "string")
+	t.Cleanup(func() { unregisterExtensionProperty("x-test-footstep") })
+	assert.Panics(t, func() { RegisterExtensionProperty("x-test-footstep", "string") })
+	assert.Panics(t, func() { RegisterExtensionProperty("footstep", "string") })
+	assert.Contains(t, ExtensionProperties(), "x-test-footstep")
+	assert.Contains(t, ExtensionProperties(), ZOffsetProperty)
+	assert.Contains(t, ExtensionProperties(), DefaultAnimationGroupProperty)
+	assert.Contains(t, ExtensionProperties(), SlopeRightProperty)
+
+	typ, ok := ExtensionPropertyType(ScrollYProperty)
+	assert.True(t, ok)
+	assert.Equal(t, "float", typ)
+	typ, ok = ExtensionPropertyType(DefaultPathModeProperty)
+	assert.True(t, ok)
+	assert.Equal(t, "string", typ)
+	_, ok = ExtensionPropertyType("x-missing")
+	assert.False(t, ok)
+}
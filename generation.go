@@ -0,0 +1,91 @@
+package tiled
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// GenerationPropertyPrefix prefixes the map properties holding the
+	// generation metadata of procedurally generated maps
+	GenerationPropertyPrefix = "generation."
+
+	generatorProperty = GenerationPropertyPrefix + "generator"
+	seedProperty      = GenerationPropertyPrefix + "seed"
+	parameterPrefix   = GenerationPropertyPrefix + "param."
+)
+
+// GenerationInfo records how a map was procedurally generated, so it can be
+// generated again and audited
+type GenerationInfo struct {
+	// Name and version of the generator
+	Generator string
+	// Seed of the random number generator
+	Seed int64
+	// Parameters given to the generator
+	Parameters map[string]string
+}
+
+// Generation returns the generation metadata stored in the map properties,
+// and whether the map has any.
+func (m *Map) Generation() (*GenerationInfo, bool) {
+	if m.Properties == nil {
+		return nil, false
+	}
+
+	info := &GenerationInfo{Parameters: map[string]string{}}
+	found := false
+	for _, p := range *m.Properties {
+		switch {
+		case p.Name == generatorProperty:
+			info.Generator = p.Value
+		case p.Name == seedProperty:
+			// Seeds are stored as strings as int properties are 32 bits in Tiled
+			seed, err := strconv.ParseInt(p.Value, 10, 64)
+			if err != nil {
+				continue
+			}
+			info.Seed = seed
+		case strings.HasPrefix(p.Name, parameterPrefix):
+			info.Parameters[strings.TrimPrefix(p.Name, parameterPrefix)] = p.Value
+		default:
+			continue
+		}
+		found = true
+	}
+
+	if !found {
+		return nil, false
+	}
+	return info, true
+}
+
+// SetGeneration stores generation metadata in the map properties, replacing
+// any previous one.
+func (m *Map) SetGeneration(info *GenerationInfo) {
+	var props Properties
+	if m.Properties != nil {
+		for _, p := range *m.Properties {
+			if !strings.HasPrefix(p.Name, GenerationPropertyPrefix) {
+				props = append(props, p)
+			}
+		}
+	}
+
+	props = append(props,
+		&Property{Name: generatorProperty, Value: info.Generator},
+		&Property{Name: seedProperty, Value: strconv.FormatInt(info.Seed, 10)},
+	)
+
+	names := make([]string, 0, len(info.Parameters))
+	for name := range info.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		props = append(props, &Property{Name: parameterPrefix + name, Value: info.Parameters[name]})
+	}
+
+	m.Properties = &props
+}
@@ -0,0 +1,39 @@
+package tiled
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneration(t *testing.T) {
+	r := bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<properties>
+<property name="title" value="Caves"/>
+<property name="generation.generator" value="caves 1.2"/>
+<property name="generation.seed" value="9007199254740993"/>
+<property name="generation.param.fill" value="0.45"/>
+</properties>
+</map>`)
+	m, err := LoadReader(GetAssetsDirectory(), r)
+	assert.NoError(t, err)
+
+	info, ok := m.Generation()
+	if assert.True(t, ok) {
+		assert.Equal(t, "caves 1.2", info.Generator)
+		assert.Equal(t, int64(9007199254740993), info.Seed)
+		assert.Equal(t, map[string]string{"fill": "0.45"}, info.Parameters)
+	}
+
+	m.SetGeneration(&GenerationInfo{Generator: "caves 1.3", Seed: 42, Parameters: map[string]string{"steps": "4"}})
+	info, ok = m.Generation()
+	if assert.True(t, ok) {
+		assert.Equal(t, &GenerationInfo{Generator: "caves 1.3", Seed: 42, Parameters: map[string]string{"steps": "4"}}, info)
+	}
+	assert.Equal(t, "Caves", m.Properties.GetString("title"))
+
+	_, ok = (&Map{}).Generation()
+	assert.False(t, ok)
+}
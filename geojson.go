@@ -0,0 +1,428 @@
+package tiled
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// ErrInvalidGeoJSON error is returned when GeoJSON can't be converted to objects
+var ErrInvalidGeoJSON = errors.New("tiled: invalid GeoJSON")
+
+// GeoJSON geometry types
+const (
+	GeoJSONPoint      = "Point"
+	GeoJSONLineString = "LineString"
+	GeoJSONPolygon    = "Polygon"
+
+	GeoJSONMultiPoint      = "MultiPoint"
+	GeoJSONMultiLineString = "MultiLineString"
+	GeoJSONMultiPolygon    = "MultiPolygon"
+)
+
+// ellipseSegments is the number of sides of polygons approximating ellipses
+const ellipseSegments = 32
+
+// GeoJSONFeatureCollection is a GeoJSON FeatureCollection
+type GeoJSONFeatureCollection struct {
+	Type     string            `json:"type"`
+	Features []*GeoJSONFeature `json:"features"`
+}
+
+// GeoJSONFeature is a GeoJSON Feature
+type GeoJSONFeature struct {
+	Type       string           `json:"type"`
+	ID         any              `json:"id,omitempty"`
+	Geometry   *GeoJSONGeometry `json:"geometry"`
+	Properties map[string]any   `json:"properties"`
+}
+
+// GeoJSONGeometry is a GeoJSON geometry. Exported coordinates are [x, y]
+// positions in map pixels, y pointing down.
+type GeoJSONGeometry struct {
+	Type        string `json:"type"`
+	Coordinates any    `json:"coordinates"`
+}
+
+// GeoJSON converts the objects of the group into a FeatureCollection. Points
+// become Points, polylines LineStrings, and other shapes Polygons, ellipses
+// being approximated. Object rotation is applied. Custom properties are
+// kept with their type, along with the name, class and layer of the objects.
+func (g *ObjectGroup) GeoJSON() *GeoJSONFeatureCollection {
+	fc := &GeoJSONFeatureCollection{Type: "FeatureCollection", Features: []*GeoJSONFeature{}}
+	fc.add(g)
+	return fc
+}
+
+// GeoJSON converts the objects of all object groups of the map, including
+// the ones nested in groups, into a single FeatureCollection.
+func (m *Map) GeoJSON() *GeoJSONFeatureCollection {
+	fc := &GeoJSONFeatureCollection{Type: "FeatureCollection", Features: []*GeoJSONFeature{}}
+	for _, g := range m.ObjectGroups {
+		fc.add(g)
+	}
+	var addGroups func(groups []*Group)
+	addGroups = func(groups []*Group) {
+		for _, group := range groups {
+			for _, g := range group.ObjectGroups {
+				fc.add(g)
+			}
+			addGroups(group.Groups)
+		}
+	}
+	addGroups(m.Groups)
+	return fc
+}
+
+// WriteGeoJSON writes the feature collection as JSON
+func (fc *GeoJSONFeatureCollection) WriteGeoJSON(w io.Writer) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(fc)
+}
+
+func (fc *GeoJSONFeatureCollection) add(g *ObjectGroup) {
+	for _, o := range g.Objects {
+		f := &GeoJSONFeature{
+			Type:       "Feature",
+			Geometry:   o.geoJSONGeometry(),
+			Properties: geoJSONProperties(o.Properties),
+		}
+		if o.ID != 0 {
+			f.ID = o.ID
+		}
+		setDefault(f.Properties, "name", o.Name)
+		class := o.Class
+		if class == "" {
+			class = o.Type
+		}
+		setDefault(f.Properties, "class", class)
+		setDefault(f.Properties, "layer", g.Name)
+		fc.Features = append(fc.Features, f)
+	}
+}
+
+func setDefault(p map[string]any, name, value string) {
+	if _, ok := p[name]; !ok && value != "" {
+		p[name] = value
+	}
+}
+
+// geoJSONProperties converts custom properties to JSON values of their type
+func geoJSONProperties(props Properties) map[string]any {
+	res := map[string]any{}
+	for _, p := range props {
+		var v any = p.Value
+		switch p.Type {
+		case "int", "object":
+			if i, err := strconv.ParseInt(p.Value, 10, 64); err == nil {
+				v = i
+			}
+		case "float":
+			if f, err := strconv.ParseFloat(p.Value, 64); err == nil {
+				v = f
+			}
+		case "bool":
+			v = p.Value == "true"
+		}
+		res[p.Name] = v
+	}
+	return res
+}
+
+func position(p Point) [2]float64 {
+	return [2]float64{p.X, p.Y}
+}
+
+func (o *Object) geoJSONGeometry() *GeoJSONGeometry {
+	if o.IsPoint() {
+		return &GeoJSONGeometry{Type: GeoJSONPoint, Coordinates: position(Point{X: o.X, Y: o.Y})}
+	}
+
+	var local []Point
+	if len(o.Ellipses) > 0 {
+		rx, ry := o.Width/2, o.Height/2
+		for i := 0; i < ellipseSegments; i++ {
+			sin, cos := math.Sincos(2 * math.Pi * float64(i) / ellipseSegments)
+			local = append(local, Point{X: rx + rx*cos, Y: ry + ry*sin})
+		}
+	} else {
+		local = o.localPoints()
+	}
+
+	var coords [][2]float64
+	for _, p := range local {
+		coords = append(coords, position(o.transform(p)))
+	}
+
+	if len(o.PolyLines) > 0 {
+		return &GeoJSONGeometry{Type: GeoJSONLineString, Coordinates: coords}
+	}
+
+	// Polygon rings are closed
+	coords = append(coords, coords[0])
+	return &GeoJSONGeometry{Type: GeoJSONPolygon, Coordinates: [][][2]float64{coords}}
+}
+
+// GeoJSONImportOptions converts GeoJSON coordinates to map pixels, as
+// x*ScaleX+OffsetX and y*ScaleY+OffsetY. A negative ScaleY turns latitudes,
+// pointing up, into map coordinates, pointing down.
+type GeoJSONImportOptions struct {
+	// Scale of the coordinates, defaults to 1
+	ScaleX, ScaleY float64
+	// Offset in pixels added to the scaled coordinates
+	OffsetX, OffsetY float64
+}
+
+func (opts GeoJSONImportOptions) point(v any) (Point, error) {
+	pos, ok := v.([]any)
+	if !ok || len(pos) < 2 {
+		return Point{}, fmt.Errorf("%w: invalid position %v", ErrInvalidGeoJSON, v)
+	}
+	x, okx := pos[0].(float64)
+	y, oky := pos[1].(float64)
+	if !okx || !oky {
+		return Point{}, fmt.Errorf("%w: invalid position %v", ErrInvalidGeoJSON, v)
+	}
+
+	sx, sy := opts.ScaleX, opts.ScaleY
+	if sx == 0 {
+		sx = 1
+	}
+	if sy == 0 {
+		sy = 1
+	}
+	return Point{X: x*sx + opts.OffsetX, Y: y*sy + opts.OffsetY}, nil
+}
+
+func (opts GeoJSONImportOptions) points(v any) ([]Point, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid positions %v", ErrInvalidGeoJSON, v)
+	}
+	var res []Point
+	for _, pos := range list {
+		p, err := opts.point(pos)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
+
+func geoJSONList(v any) ([]any, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid coordinates %v", ErrInvalidGeoJSON, v)
+	}
+	return list, nil
+}
+
+// shape makes an object of a Point, a LineString or a Polygon, whose outer
+// ring only is kept
+func (opts GeoJSONImportOptions) shape(kind string, coords any) (*Object, error) {
+	if kind == GeoJSONPoint {
+		p, err := opts.point(coords)
+		if err != nil {
+			return nil, err
+		}
+		return &Object{X: p.X, Y: p.Y, Visible: true}, nil
+	}
+
+	if kind == GeoJSONPolygon {
+		rings, err := geoJSONList(coords)
+		if err != nil {
+			return nil, err
+		}
+		if len(rings) == 0 {
+			return nil, fmt.Errorf("%w: polygon without rings", ErrInvalidGeoJSON)
+		}
+		coords = rings[0]
+	}
+
+	points, err := opts.points(coords)
+	if err != nil {
+		return nil, err
+	}
+	if kind == GeoJSONPolygon && len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("%w: %s without positions", ErrInvalidGeoJSON, kind)
+	}
+
+	// Points are relative to the first one
+	origin := points[0]
+	for i := range points {
+		points[i].X -= origin.X
+		points[i].Y -= origin.Y
+	}
+
+	o := &Object{X: origin.X, Y: origin.Y, Visible: true}
+	if kind == GeoJSONPolygon {
+		o.Polygons = []*Polygon{{Points: fromPoints(points)}}
+	} else {
+		o.PolyLines = []*PolyLine{{Points: fromPoints(points)}}
+	}
+	return o, nil
+}
+
+// objects converts a geometry to objects, one per part of Multi geometries
+func (opts GeoJSONImportOptions) objects(g *GeoJSONGeometry) ([]*Object, error) {
+	var kind string
+	switch g.Type {
+	case GeoJSONPoint, GeoJSONLineString, GeoJSONPolygon:
+		o, err := opts.shape(g.Type, g.Coordinates)
+		if err != nil {
+			return nil, err
+		}
+		return []*Object{o}, nil
+	case GeoJSONMultiPoint:
+		kind = GeoJSONPoint
+	case GeoJSONMultiLineString:
+		kind = GeoJSONLineString
+	case GeoJSONMultiPolygon:
+		kind = GeoJSONPolygon
+	default:
+		return nil, fmt.Errorf("%w: unsupported geometry %q", ErrInvalidGeoJSON, g.Type)
+	}
+
+	parts, err := geoJSONList(g.Coordinates)
+	if err != nil {
+		return nil, err
+	}
+	var res []*Object
+	for _, part := range parts {
+		o, err := opts.shape(kind, part)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, o)
+	}
+	return res, nil
+}
+
+// tiledProperties converts JSON values to custom properties, guessing their
+// type. Objects and arrays are kept as JSON strings.
+func tiledProperties(values map[string]any) Properties {
+	var res Properties
+	for name, v := range values {
+		p := &Property{Name: name}
+		switch v := v.(type) {
+		case nil:
+			continue
+		case string:
+			p.Value = v
+		case bool:
+			p.Type = "bool"
+			p.Value = strconv.FormatBool(v)
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+				p.Type = "int"
+				p.Value = strconv.FormatInt(int64(v), 10)
+			} else {
+				p.Type = "float"
+				p.Value = strconv.FormatFloat(v, 'g', -1, 64)
+			}
+		default:
+			data, _ := json.Marshal(v)
+			p.Value = string(data)
+		}
+		res = append(res, p)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// ReadGeoJSON reads a GeoJSON FeatureCollection, or a single Feature
+func ReadGeoJSON(r io.Reader) (*GeoJSONFeatureCollection, error) {
+	var fc GeoJSONFeatureCollection
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidGeoJSON, err)
+	}
+
+	switch fc.Type {
+	case "FeatureCollection":
+		return &fc, nil
+	case "Feature":
+		var f GeoJSONFeature
+		if err := json.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidGeoJSON, err)
+		}
+		return &GeoJSONFeatureCollection{Type: "FeatureCollection", Features: []*GeoJSONFeature{&f}}, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported type %q", ErrInvalidGeoJSON, fc.Type)
+}
+
+// ObjectGroup converts the features to the objects of a new object group.
+// Points become point objects, LineStrings polylines and Polygons polygons,
+// without their holes. Multi geometries make one object per part. The name
+// and class properties become the name and class of the objects, and the
+// layer property written by GeoJSON is dropped. Other properties become
+// custom properties. Objects have no ID, see Map.ImportGeoJSON.
+func (fc *GeoJSONFeatureCollection) ObjectGroup(name string, opts GeoJSONImportOptions) (*ObjectGroup, error) {
+	g := &ObjectGroup{Name: name, Visible: true, Opacity: 1, ParallaxX: 1, ParallaxY: 1, DrawOrder: DrawOrderTopDown}
+
+	for _, f := range fc.Features {
+		if f.Geometry == nil {
+			continue
+		}
+		objects, err := opts.objects(f.Geometry)
+		if err != nil {
+			return nil, err
+		}
+
+		values := map[string]any{}
+		for k, v := range f.Properties {
+			values[k] = v
+		}
+		objectName, _ := values["name"].(string)
+		class, _ := values["class"].(string)
+		delete(values, "name")
+		delete(values, "class")
+		delete(values, "layer")
+
+		for _, o := range objects {
+			o.Name = objectName
+			o.Class = class
+			o.Properties = tiledProperties(values)
+			g.Objects = append(g.Objects, o)
+		}
+	}
+
+	return g, nil
+}
+
+// ImportGeoJSON reads GeoJSON features into a new object group added on top
+// of the map. The group gets its ID from NextLayerID and objects get theirs
+// from NextObjectID.
+func (m *Map) ImportGeoJSON(r io.Reader, name string, opts GeoJSONImportOptions) (*ObjectGroup, error) {
+	fc, err := ReadGeoJSON(r)
+	if err != nil {
+		return nil, err
+	}
+	g, err := fc.ObjectGroup(name, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	g.ID = m.newLayerID()
+	if m.NextObjectID == 0 {
+		m.NextObjectID = 1
+	}
+	for _, o := range g.Objects {
+		o.ID = m.NextObjectID
+		m.NextObjectID++
+	}
+	m.ObjectGroups = append(m.ObjectGroups, g)
+	return g, nil
+}
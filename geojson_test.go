@@ -0,0 +1,88 @@
+package tiled
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectGroupGeoJSON(t *testing.T) {
+	g := &ObjectGroup{
+		Name: "spawns",
+		Objects: []*Object{
+			{ID: 1, Name: "start", X: 10, Y: 20, Properties: Properties{{Name: "team", Type: "int", Value: "2"}}},
+			{ID: 2, Class: "road", X: 5, Y: 5, PolyLines: []*PolyLine{{Points: &Points{{0, 0}, {10, 0}}}}},
+			{ID: 3, X: 0, Y: 0, Width: 4, Height: 2, Properties: Properties{{Name: "solid", Type: "bool", Value: "true"}}},
+		},
+	}
+
+	fc := g.GeoJSON()
+	assert.Len(t, fc.Features, 3)
+
+	assert.Equal(t, GeoJSONPoint, fc.Features[0].Geometry.Type)
+	assert.Equal(t, [2]float64{10, 20}, fc.Features[0].Geometry.Coordinates)
+	assert.Equal(t, map[string]any{"team": int64(2), "name": "start", "layer": "spawns"}, fc.Features[0].Properties)
+
+	assert.Equal(t, GeoJSONLineString, fc.Features[1].Geometry.Type)
+	assert.Equal(t, [][2]float64{{5, 5}, {15, 5}}, fc.Features[1].Geometry.Coordinates)
+	assert.Equal(t, "road", fc.Features[1].Properties["class"])
+
+	assert.Equal(t, GeoJSONPolygon, fc.Features[2].Geometry.Type)
+	assert.Equal(t, [][][2]float64{{{0, 0}, {4, 0}, {4, 2}, {0, 2}, {0, 0}}}, fc.Features[2].Geometry.Coordinates)
+	assert.Equal(t, true, fc.Features[2].Properties["solid"])
+
+	var buf bytes.Buffer
+	assert.NoError(t, fc.WriteGeoJSON(&buf))
+	var decoded map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "FeatureCollection", decoded["type"])
+}
+
+func TestMapImportGeoJSON(t *testing.T) {
+	data := `{
+		"type": "FeatureCollection",
+		"features": [
+			{"type": "Feature", "id": "a", "geometry": {"type": "Point", "coordinates": [1, 2]}, "properties": {"name": "spawn", "hp": 10, "speed": 1.5}},
+			{"type": "Feature", "geometry": {"type": "Polygon", "coordinates": [[[0, 0], [2, 0], [2, 1], [0, 0]]]}, "properties": {"class": "water", "layer": "old"}},
+			{"type": "Feature", "geometry": {"type": "MultiLineString", "coordinates": [[[0, 0], [1, 1]], [[2, 2], [3, 3]]]}, "properties": null},
+			{"type": "Feature", "geometry": null, "properties": {}}
+		]
+	}`
+
+	m := &Map{NextObjectID: 5, NextLayerID: 3}
+	g, err := m.ImportGeoJSON(bytes.NewBufferString(data), "imported", GeoJSONImportOptions{ScaleX: 10, ScaleY: -10, OffsetY: 100})
+	assert.NoError(t, err)
+	assert.Equal(t, []*ObjectGroup{g}, m.ObjectGroups)
+	assert.Equal(t, uint32(9), m.NextObjectID)
+	assert.Equal(t, uint32(3), g.ID)
+	assert.Equal(t, uint32(4), m.NextLayerID)
+	assert.Len(t, g.Objects, 4)
+
+	spawn := g.Objects[0]
+	assert.Equal(t, uint32(5), spawn.ID)
+	assert.Equal(t, "spawn", spawn.Name)
+	assert.True(t, spawn.IsPoint())
+	assert.Equal(t, 10.0, spawn.X)
+	assert.Equal(t, 80.0, spawn.Y)
+	assert.Equal(t, Properties{{Name: "hp", Type: "int", Value: "10"}, {Name: "speed", Type: "float", Value: "1.5"}}, spawn.Properties)
+
+	water := g.Objects[1]
+	assert.Equal(t, "water", water.Class)
+	assert.Empty(t, water.Properties)
+	assert.Equal(t, &Points{{0, 0}, {20, 0}, {20, -10}}, water.Polygons[0].Points)
+
+	assert.Equal(t, 20.0, g.Objects[3].X)
+	assert.Equal(t, &Points{{0, 0}, {10, -10}}, g.Objects[3].PolyLines[0].Points)
+
+	// Maps without NextLayerID get IDs after their highest layer ID
+	m = &Map{Layers: []*Layer{{ID: 1}}, Groups: []*Group{{ID: 2, ImageLayers: []*ImageLayer{{ID: 6}}}}}
+	g, err = m.ImportGeoJSON(bytes.NewBufferString(data), "imported", GeoJSONImportOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(7), g.ID)
+	assert.Equal(t, uint32(8), m.NextLayerID)
+
+	_, err = ReadGeoJSON(bytes.NewBufferString(`{"type": "Topology"}`))
+	assert.ErrorIs(t, err, ErrInvalidGeoJSON)
+}
@@ -5,6 +5,7 @@ go 1.24.0
 require (
 	github.com/hajimehoshi/ebiten/v2 v2.9.6
 	github.com/stretchr/testify v1.10.0
+	golang.org/x/image v0.31.0
 )
 
 require (
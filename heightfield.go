@@ -0,0 +1,106 @@
+package tiled
+
+import (
+	"math"
+)
+
+// Heightfield holds the y coordinate in map pixels of the topmost ground
+// surface of each pixel column of a layer. Columns without ground hold +Inf.
+type Heightfield []float64
+
+// At returns the surface at x map pixels, interpolating between columns
+func (h Heightfield) At(x float64) float64 {
+	if len(h) == 0 {
+		return math.Inf(1)
+	}
+
+	// Samples are taken at the middle of columns
+	x -= 0.5
+	i := int(math.Floor(x))
+	if i < 0 {
+		return h[0]
+	}
+	if i >= len(h)-1 {
+		return h[len(h)-1]
+	}
+
+	a, b := h[i], h[i+1]
+	if math.IsInf(a, 1) || math.IsInf(b, 1) {
+		if x-float64(i) < 0.5 {
+			return a
+		}
+		return b
+	}
+	return a + (b-a)*(x-float64(i))
+}
+
+// surfaceAt returns the topmost y of the object outline crossing the
+// vertical line at x, or +Inf if the object doesn't cross it.
+func surfaceAt(o *Object, x float64) float64 {
+	if len(o.Ellipses) > 0 {
+		rx, ry := o.Width/2, o.Height/2
+		dx := (x - o.X - rx) / rx
+		if rx == 0 || dx < -1 || dx > 1 {
+			return math.Inf(1)
+		}
+		return o.Y + ry - ry*math.Sqrt(1-dx*dx)
+	}
+
+	points := o.localPoints()
+	for i, p := range points {
+		points[i] = o.transform(p)
+	}
+	n := len(points)
+	if len(o.PolyLines) > 0 {
+		// Open chain
+		n--
+	}
+
+	res := math.Inf(1)
+	for i := 0; i < n; i++ {
+		a, b := points[i], points[(i+1)%len(points)]
+		if a.X == b.X || x < math.Min(a.X, b.X) || x > math.Max(a.X, b.X) {
+			continue
+		}
+		res = math.Min(res, a.Y+(x-a.X)*(b.Y-a.Y)/(b.X-a.X))
+	}
+	return res
+}
+
+// Heightfield computes the ground surface of each pixel column of the layer
+// from its colliders, so characters can walk slopes without full polygon
+// collision. Collision shapes of the tiles are used when they have some,
+// slope heights or the tile bounds otherwise. Ladders are ignored.
+func (l *Layer) Heightfield() Heightfield {
+	h := make(Heightfield, l._map.Width*l._map.TileWidth)
+	for i := range h {
+		h[i] = math.Inf(1)
+	}
+
+	for _, c := range l.Colliders() {
+		if c.Kind == CollisionLadder {
+			continue
+		}
+
+		start := max(int(math.Floor(c.Bounds.Min.X)), 0)
+		end := min(int(math.Ceil(c.Bounds.Max.X)), len(h))
+		for x := start; x < end; x++ {
+			cx := float64(x) + 0.5
+
+			y := math.Inf(1)
+			switch {
+			case len(c.Objects) > 0:
+				for _, o := range c.Objects {
+					y = math.Min(y, surfaceAt(o, cx))
+				}
+			case c.Kind == CollisionSlope:
+				y = c.Bounds.Max.Y - c.HeightAt(cx-c.Bounds.Min.X)
+			default:
+				y = c.Bounds.Min.Y
+			}
+			h[x] = math.Min(h[x], y)
+		}
+	}
+
+	return h
+}
@@ -0,0 +1,167 @@
+package httpfs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FS fetches the files below a base URL, keeping them in memory once fetched
+// so maps, external tilesets and images shared by several maps are requested
+// once. It can be passed to tiled.WithFileSystem and to the renderer so web
+// builds load maps from an asset server. It is safe for concurrent use.
+type FS struct {
+	ctx    context.Context
+	client *http.Client
+	base   *url.URL
+
+	mu    sync.Mutex
+	files map[string]*cachedFile
+}
+
+// cachedFile is a file fetched or being fetched, ready once done is closed
+type cachedFile struct {
+	done    chan struct{}
+	data    []byte
+	modTime time.Time
+	err     error
+}
+
+// New returns a file system fetching files relative to baseURL. A nil client
+// uses http.DefaultClient.
+func New(ctx context.Context, client *http.Client, baseURL string) (*FS, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	return &FS{ctx: ctx, client: client, base: base, files: map[string]*cachedFile{}}, nil
+}
+
+// Open implements fs.FS, fetching the file unless it is cached. Files missing
+// on the server fail with fs.ErrNotExist. Failed requests are not cached.
+func (f *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	f.mu.Lock()
+	c, ok := f.files[name]
+	if !ok {
+		c = &cachedFile{done: make(chan struct{})}
+		f.files[name] = c
+	}
+	f.mu.Unlock()
+
+	if !ok {
+		c.data, c.modTime, c.err = f.fetch(name)
+		if c.err != nil {
+			f.mu.Lock()
+			delete(f.files, name)
+			f.mu.Unlock()
+		}
+		close(c.done)
+	}
+	<-c.done
+
+	if c.err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: c.err}
+	}
+	return &file{
+		Reader: bytes.NewReader(c.data),
+		info:   fileInfo{name: path.Base(name), size: int64(len(c.data)), modTime: c.modTime},
+	}, nil
+}
+
+// Forget drops the cached file with the given name, so it is fetched again
+// when next opened
+func (f *FS) Forget(name string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c, ok := f.files[name]; ok && isDone(c) {
+		delete(f.files, name)
+	}
+}
+
+// Clear drops all cached files
+func (f *FS) Clear() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for name, c := range f.files {
+		if isDone(c) {
+			delete(f.files, name)
+		}
+	}
+}
+
+func isDone(c *cachedFile) bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (f *FS) fetch(name string) ([]byte, time.Time, error) {
+	u := f.base.ResolveReference(&url.URL{Path: name})
+	req, err := http.NewRequestWithContext(f.ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return nil, time.Time{}, fs.ErrNotExist
+	default:
+		return nil, time.Time{}, fmt.Errorf("httpfs: GET %s: %s", u, resp.Status)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	modTime, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return data, modTime, nil
+}
+
+// file is an open file of a FS
+type file struct {
+	*bytes.Reader
+	info fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *file) Close() error               { return nil }
+
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+}
+
+func (i fileInfo) Name() string       { return i.name }
+func (i fileInfo) Size() int64        { return i.size }
+func (i fileInfo) Mode() fs.FileMode  { return 0o444 }
+func (i fileInfo) ModTime() time.Time { return i.modTime }
+func (i fileInfo) IsDir() bool        { return false }
+func (i fileInfo) Sys() any           { return nil }
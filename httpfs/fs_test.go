@@ -0,0 +1,65 @@
+package httpfs
+
+import (
+	"context"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFS(t *testing.T) {
+	files := map[string]string{
+		"/assets/maps/level.tmx": `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="1" tilewidth="8" tileheight="8">
+<tileset firstgid="1" source="../tilesets/ground.tsx"/>
+<layer id="1" name="Ground" width="2" height="1"><data encoding="csv">1,2</data></layer>
+</map>`,
+		"/assets/tilesets/ground.tsx": `<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="ground" tilewidth="8" tileheight="8" tilecount="2" columns="2">
+<image source="ground.png" width="16" height="8"/>
+</tileset>`,
+	}
+	requests := map[string]*atomic.Int64{}
+	for name := range files {
+		requests[name] = &atomic.Int64{}
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		content, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		requests[r.URL.Path].Add(1)
+		io.WriteString(w, content)
+	}))
+	defer srv.Close()
+
+	fsys, err := New(context.Background(), nil, srv.URL+"/assets")
+	assert.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		m, err := tiled.LoadFile("maps/level.tmx", tiled.WithFileSystem(fsys))
+		assert.NoError(t, err)
+		assert.Equal(t, "ground", m.Tilesets[0].Name)
+		assert.Equal(t, uint32(1), m.Layers[0].Tiles[1].ID)
+	}
+	assert.Equal(t, int64(1), requests["/assets/maps/level.tmx"].Load())
+	assert.Equal(t, int64(1), requests["/assets/tilesets/ground.tsx"].Load())
+
+	fsys.Forget("maps/level.tmx")
+	data, err := fs.ReadFile(fsys, "maps/level.tmx")
+	assert.NoError(t, err)
+	assert.Equal(t, files["/assets/maps/level.tmx"], string(data))
+	assert.Equal(t, int64(2), requests["/assets/maps/level.tmx"].Load())
+
+	_, err = fsys.Open("maps/missing.tmx")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, err = fsys.Open("../secret")
+	assert.ErrorIs(t, err, fs.ErrInvalid)
+}
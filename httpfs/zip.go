@@ -0,0 +1,156 @@
+// Package httpfs loads maps and their resources from HTTP servers.
+package httpfs
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrRangeUnsupported is returned when a server ignores range requests
+var ErrRangeUnsupported = errors.New("httpfs: server does not support range requests")
+
+const (
+	// Size of the blocks fetched by a RangeReader
+	rangeBlockSize = 64 << 10
+	// Number of blocks kept by a RangeReader
+	rangeCachedBlocks = 16
+)
+
+// RangeReader reads a remote file with HTTP range requests, fetching blocks
+// of the file as they are read. It is safe for concurrent use.
+type RangeReader struct {
+	ctx    context.Context
+	client *http.Client
+	url    string
+	size   int64
+
+	mu     sync.Mutex
+	blocks map[int64][]byte
+	order  []int64 // Cached blocks, least recently fetched first
+}
+
+// NewRangeReader opens the file at url, requesting its size. A nil client
+// uses http.DefaultClient.
+func NewRangeReader(ctx context.Context, client *http.Client, url string) (*RangeReader, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	r := &RangeReader{ctx: ctx, client: client, url: url, blocks: map[int64][]byte{}}
+
+	resp, err := r.get(0, 0)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	// Content-Range: bytes 0-0/size
+	cr := resp.Header.Get("Content-Range")
+	i := strings.LastIndexByte(cr, '/')
+	if i < 0 {
+		return nil, fmt.Errorf("%w: invalid Content-Range %q", ErrRangeUnsupported, cr)
+	}
+	if r.size, err = strconv.ParseInt(cr[i+1:], 10, 64); err != nil {
+		return nil, fmt.Errorf("%w: invalid Content-Range %q", ErrRangeUnsupported, cr)
+	}
+	return r, nil
+}
+
+// Size returns the size of the remote file
+func (r *RangeReader) Size() int64 {
+	return r.size
+}
+
+// get requests the bytes from start to end included
+func (r *RangeReader) get(start, end int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusPartialContent {
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			return nil, ErrRangeUnsupported
+		}
+		return nil, fmt.Errorf("httpfs: GET %s: %s", r.url, resp.Status)
+	}
+	return resp, nil
+}
+
+// block returns the block at index i, fetching it if needed
+func (r *RangeReader) block(i int64) ([]byte, error) {
+	r.mu.Lock()
+	b, ok := r.blocks[i]
+	r.mu.Unlock()
+	if ok {
+		return b, nil
+	}
+
+	start := i * rangeBlockSize
+	end := min(start+rangeBlockSize, r.size) - 1
+	resp, err := r.get(start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	b = make([]byte, end-start+1)
+	if _, err := io.ReadFull(resp.Body, b); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.blocks[i]; !ok {
+		if len(r.order) == rangeCachedBlocks {
+			delete(r.blocks, r.order[0])
+			r.order = r.order[1:]
+		}
+		r.blocks[i] = b
+		r.order = append(r.order, i)
+	}
+	return b, nil
+}
+
+// ReadAt implements io.ReaderAt
+func (r *RangeReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("httpfs: negative offset")
+	}
+	n := 0
+	for n < len(p) {
+		pos := off + int64(n)
+		if pos >= r.size {
+			return n, io.EOF
+		}
+		b, err := r.block(pos / rangeBlockSize)
+		if err != nil {
+			return n, err
+		}
+		n += copy(p[n:], b[pos%rangeBlockSize:])
+	}
+	return n, nil
+}
+
+// OpenZip opens the zip archive at url without downloading it: its central
+// directory and the members opened are fetched with range requests. The
+// archive can be passed to tiled.WithFileSystem to load a single map and its
+// resources out of a large bundle. A nil client uses http.DefaultClient.
+func OpenZip(ctx context.Context, client *http.Client, url string) (*zip.Reader, error) {
+	r, err := NewRangeReader(ctx, client, url)
+	if err != nil {
+		return nil, err
+	}
+	return zip.NewReader(r, r.Size())
+}
@@ -0,0 +1,64 @@
+package httpfs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	add := func(name, content string) {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	add("maps/level.tmx", `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="1" tilewidth="8" tileheight="8">
+<tileset firstgid="1" source="../tilesets/ground.tsx"/>
+<layer id="1" name="Ground" width="2" height="1"><data encoding="csv">1,2</data></layer>
+</map>`)
+	add("tilesets/ground.tsx", `<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="ground" tilewidth="8" tileheight="8" tilecount="2" columns="2">
+<image source="ground.png" width="16" height="8"/>
+</tileset>`)
+	// Padding the archive so members are not fetched all at once
+	add("padding.bin", string(make([]byte, 8*rangeBlockSize)))
+	assert.NoError(t, zw.Close())
+
+	var fetched atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched.Add(1)
+		http.ServeContent(w, r, "bundle.zip", time.Time{}, bytes.NewReader(buf.Bytes()))
+	}))
+	defer srv.Close()
+
+	archive, err := OpenZip(context.Background(), nil, srv.URL)
+	assert.NoError(t, err)
+
+	m, err := tiled.LoadFile("maps/level.tmx", tiled.WithFileSystem(archive))
+	assert.NoError(t, err)
+	assert.Equal(t, "ground", m.Tilesets[0].Name)
+	assert.Equal(t, uint32(1), m.Layers[0].Tiles[1].ID)
+	assert.Less(t, fetched.Load()*rangeBlockSize, int64(buf.Len()))
+}
+
+func TestRangeUnsupported(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("not a range"))
+	}))
+	defer srv.Close()
+
+	_, err := OpenZip(context.Background(), nil, srv.URL)
+	assert.ErrorIs(t, err, ErrRangeUnsupported)
+}
@@ -37,6 +37,17 @@ func SortAnySlice[T any](data []T, lessMethod func(a, b T) bool) []T {
 	return s.data
 }
 
+// SortStableAnySlice sorts a slice with given less method, keeping the
+// original order of equal elements
+func SortStableAnySlice[T any](data []T, lessMethod func(a, b T) bool) []T {
+	s := &sortable[T]{
+		data:       data,
+		lessMethod: lessMethod,
+	}
+	sort.Stable(s)
+	return s.data
+}
+
 type sortable[T any] struct {
 	data       []T
 	lessMethod func(a, b T) bool
@@ -0,0 +1,521 @@
+package internal
+
+import (
+	"bufio"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"math/bits"
+)
+
+// ErrWebPTooLarge is returned for images too large for the WebP format
+var ErrWebPTooLarge = errors.New("webp: image is too large")
+
+const (
+	webpMaxSize        = 1 << 14
+	webpMaxCodeLength  = 15
+	webpMaxCodeLengths = 7
+)
+
+// webpCodeLengthOrder is the order the code lengths of the code length code
+// are written in
+var webpCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// EncodeWebP writes img in the lossless WebP format. Quality goes from 0 to
+// 100, lower qualities dropping least significant color bits for smaller
+// files, 100 keeping the image intact. Repeated pixels, as found in tile maps,
+// are written as backward references and with a color cache.
+func EncodeWebP(w io.Writer, img image.Image, quality int) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > webpMaxSize || height > webpMaxSize {
+		return ErrWebPTooLarge
+	}
+
+	drop := uint((100 - max(0, min(quality, 100))) * 4 / 100)
+	quantize := func(v uint8) uint8 {
+		if drop == 0 {
+			return v
+		}
+		mask := uint8(1)<<drop - 1
+		return uint8(min(int(v)+int(mask>>1+1), 255)) &^ mask
+	}
+
+	// Pixels with the subtract green transform applied, as ARGB
+	pixels := make([]uint32, 0, width*height)
+	alpha := false
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A != 0xff {
+				alpha = true
+			}
+			if c.A == 0 {
+				c = color.NRGBA{}
+			}
+			g := quantize(c.G)
+			pixels = append(pixels, uint32(c.A)<<24|uint32(quantize(c.R)-g)<<16|uint32(g)<<8|uint32(quantize(c.B)-g))
+		}
+	}
+
+	var bw webpBitWriter
+	bw.write(0x2f, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if alpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	bw.write(0, 3) // Version
+
+	// Subtract green transform
+	bw.write(1, 1)
+	bw.write(2, 2)
+	bw.write(0, 1)
+
+	bw.write(1, 1) // Color cache
+	bw.write(webpCacheBits, 4)
+	bw.write(0, 1) // No meta prefix codes
+
+	symbols := webpSymbols(pixels, width)
+
+	// Prefix codes for green, red, blue, alpha and distance, in this order
+	var counts [5][]int
+	counts[0] = make([]int, 256+24+1<<webpCacheBits)
+	for i := 1; i < 4; i++ {
+		counts[i] = make([]int, 256)
+	}
+	counts[4] = make([]int, 40)
+	for _, sym := range symbols {
+		switch sym.kind {
+		case webpLiteral:
+			counts[0][sym.value>>8&0xff]++
+			counts[1][sym.value>>16&0xff]++
+			counts[2][sym.value&0xff]++
+			counts[3][sym.value>>24]++
+		case webpCacheIndex:
+			counts[0][256+24+sym.value]++
+		case webpCopy:
+			prefix, _, _ := webpPrefix(sym.value)
+			counts[0][256+prefix]++
+			prefix, _, _ = webpPrefix(sym.distance)
+			counts[4][prefix]++
+		}
+	}
+
+	var codes [5]webpCode
+	for i := range codes {
+		codes[i] = bw.writeCode(counts[i])
+	}
+
+	for _, sym := range symbols {
+		switch sym.kind {
+		case webpLiteral:
+			codes[0].write(&bw, int(sym.value>>8&0xff))
+			codes[1].write(&bw, int(sym.value>>16&0xff))
+			codes[2].write(&bw, int(sym.value&0xff))
+			codes[3].write(&bw, int(sym.value>>24))
+		case webpCacheIndex:
+			codes[0].write(&bw, 256+24+int(sym.value))
+		case webpCopy:
+			prefix, extraBits, extra := webpPrefix(sym.value)
+			codes[0].write(&bw, 256+prefix)
+			bw.write(extra, extraBits)
+			prefix, extraBits, extra = webpPrefix(sym.distance)
+			codes[4].write(&bw, prefix)
+			bw.write(extra, extraBits)
+		}
+	}
+
+	data := bw.bytes()
+	size := len(data)
+	if size%2 == 1 {
+		data = append(data, 0)
+	}
+
+	out := bufio.NewWriter(w)
+	out.WriteString("RIFF")
+	_ = binary.Write(out, binary.LittleEndian, uint32(4+8+len(data)))
+	out.WriteString("WEBPVP8L")
+	_ = binary.Write(out, binary.LittleEndian, uint32(size))
+	out.Write(data)
+	return out.Flush()
+}
+
+const (
+	// Bits of the color cache index
+	webpCacheBits = 10
+	// Backward references are at least this long, and at most as long as
+	// prefix codes of lengths allow
+	webpMinMatch = 3
+	webpMaxMatch = 4096
+	// Distances in pixels are written after the 120 codes of neighboring
+	// pixels, and are at most as large as prefix codes of distances allow
+	webpMaxDistance = 1<<20 - 120
+	// Positions looked at for each pixel
+	webpHashBits  = 16
+	webpMaxChain  = 32
+	webpCacheHash = 0x1e35a7bd
+)
+
+type webpSymbolKind uint8
+
+const (
+	webpLiteral webpSymbolKind = iota
+	webpCacheIndex
+	webpCopy
+)
+
+// webpSymbol is a pixel written as is or as an index in the color cache, or
+// a backward reference copying value pixels from a distance code
+type webpSymbol struct {
+	kind     webpSymbolKind
+	value    uint32
+	distance uint32
+}
+
+// webpSymbols turns pixels into symbols, finding backward references with
+// hash chains of pairs of pixels and always trying the pixel above and the
+// pixel before, and keeping the color cache the decoder builds.
+func webpSymbols(pixels []uint32, width int) []webpSymbol {
+	n := len(pixels)
+	head := make([]int32, 1<<webpHashBits)
+	for i := range head {
+		head[i] = -1
+	}
+	chain := make([]int32, n)
+	hash := func(i int) uint32 {
+		return (pixels[i]*webpCacheHash ^ pixels[i+1]*0x9e3779b1) >> (32 - webpHashBits)
+	}
+	insert := func(i int) {
+		if i+1 < n {
+			h := hash(i)
+			chain[i] = head[h]
+			head[h] = int32(i)
+		}
+	}
+	matchLength := func(i, j int) int {
+		l := 0
+		for i+l < n && l < webpMaxMatch && pixels[i+l] == pixels[j+l] {
+			l++
+		}
+		return l
+	}
+
+	var cache [1 << webpCacheBits]uint32
+	var cacheSet [1 << webpCacheBits]bool
+	cacheIndex := func(argb uint32) uint32 {
+		return (argb * webpCacheHash) >> (32 - webpCacheBits)
+	}
+	addToCache := func(argb uint32) {
+		k := cacheIndex(argb)
+		cache[k], cacheSet[k] = argb, true
+	}
+
+	symbols := make([]webpSymbol, 0, n/4)
+	for i := 0; i < n; {
+		bestLength, bestDistance := 0, 0
+		try := func(j int) {
+			if j < 0 || j >= i || i-j > webpMaxDistance {
+				return
+			}
+			if l := matchLength(i, j); l > bestLength {
+				bestLength, bestDistance = l, i-j
+			}
+		}
+		try(i - width)
+		try(i - 1)
+		if i+1 < n {
+			for j, depth := head[hash(i)], 0; j >= 0 && depth < webpMaxChain && bestLength < webpMaxMatch; j, depth = chain[j], depth+1 {
+				try(int(j))
+			}
+		}
+
+		if bestLength >= webpMinMatch {
+			symbols = append(symbols, webpSymbol{kind: webpCopy, value: uint32(bestLength), distance: webpDistanceCode(bestDistance, width)})
+			for k := i; k < i+bestLength; k++ {
+				addToCache(pixels[k])
+				insert(k)
+			}
+			i += bestLength
+			continue
+		}
+
+		argb := pixels[i]
+		if k := cacheIndex(argb); cacheSet[k] && cache[k] == argb {
+			symbols = append(symbols, webpSymbol{kind: webpCacheIndex, value: k})
+		} else {
+			symbols = append(symbols, webpSymbol{kind: webpLiteral, value: argb})
+		}
+		addToCache(argb)
+		insert(i)
+		i++
+	}
+	return symbols
+}
+
+// webpDistanceCode returns the code of a distance in pixels, using the codes
+// of the pixel above and the pixel before, the most common in tile maps
+func webpDistanceCode(distance, width int) uint32 {
+	switch distance {
+	case width:
+		return 1
+	case 1:
+		return 2
+	}
+	return uint32(distance + 120)
+}
+
+// webpPrefix returns the prefix code of a length or distance code of at
+// least 1, and its extra bits
+func webpPrefix(v uint32) (int, uint, uint32) {
+	d := v - 1
+	if d < 4 {
+		return int(d), 0, 0
+	}
+	h := bits.Len32(d) - 1
+	second := d >> (h - 1) & 1
+	extraBits := uint(h - 1)
+	return 2*h + int(second), extraBits, d & (1<<extraBits - 1)
+}
+
+// webpBitWriter packs bits least significant first
+type webpBitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+func (bw *webpBitWriter) write(v uint32, n uint) {
+	bw.acc |= uint64(v) << bw.nbits
+	bw.nbits += n
+	for bw.nbits >= 8 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc >>= 8
+		bw.nbits -= 8
+	}
+}
+
+func (bw *webpBitWriter) bytes() []byte {
+	if bw.nbits > 0 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc, bw.nbits = 0, 0
+	}
+	return bw.buf
+}
+
+// webpCode is a canonical prefix code
+type webpCode struct {
+	lengths []int
+	codes   []uint32 // Bit reversed, as they are read least significant first
+}
+
+func newWebPCode(lengths []int) webpCode {
+	c := webpCode{lengths: lengths, codes: make([]uint32, len(lengths))}
+
+	var count [webpMaxCodeLength + 2]uint32
+	for _, l := range lengths {
+		count[l]++
+	}
+	count[0] = 0
+	var next [webpMaxCodeLength + 2]uint32
+	code := uint32(0)
+	for l := 1; l < len(count); l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		code := next[l]
+		next[l]++
+		reversed := uint32(0)
+		for i := 0; i < l; i++ {
+			reversed = reversed<<1 | (code>>i)&1
+		}
+		c.codes[s] = reversed
+	}
+	return c
+}
+
+func (c *webpCode) write(bw *webpBitWriter, symbol int) {
+	bw.write(c.codes[symbol], uint(c.lengths[symbol]))
+}
+
+// writeCode writes the prefix code fitted to the symbol counts and returns it
+func (bw *webpBitWriter) writeCode(counts []int) webpCode {
+	var used []int
+	for s, n := range counts {
+		if n > 0 {
+			used = append(used, s)
+		}
+	}
+
+	// Simple codes hold up to two symbols below 256, a single symbol taking
+	// no bits
+	if len(used) <= 2 && (len(used) == 0 || used[len(used)-1] < 256) {
+		lengths := make([]int, len(counts))
+		bw.write(1, 1)
+		if len(used) == 0 {
+			used = append(used, 0)
+		}
+		bw.write(uint32(len(used)-1), 1)
+		if used[0] < 2 {
+			bw.write(0, 1)
+			bw.write(uint32(used[0]), 1)
+		} else {
+			bw.write(1, 1)
+			bw.write(uint32(used[0]), 8)
+		}
+		if len(used) == 2 {
+			bw.write(uint32(used[1]), 8)
+			lengths[used[0]], lengths[used[1]] = 1, 1
+		}
+		return newWebPCode(lengths)
+	}
+
+	lengths := huffmanLengths(counts, webpMaxCodeLength)
+
+	// Code lengths are written with a code of their own, runs of zeros being
+	// written with symbols 17 and 18
+	type token struct{ symbol, extra int }
+	var tokens []token
+	for i := 0; i < len(lengths); {
+		if lengths[i] != 0 {
+			tokens = append(tokens, token{lengths[i], 0})
+			i++
+			continue
+		}
+		run := 0
+		for i+run < len(lengths) && lengths[i+run] == 0 && run < 138 {
+			run++
+		}
+		switch {
+		case run >= 11:
+			tokens = append(tokens, token{18, run - 11})
+		case run >= 3:
+			tokens = append(tokens, token{17, run - 3})
+		default:
+			for j := 0; j < run; j++ {
+				tokens = append(tokens, token{0, 0})
+			}
+		}
+		i += run
+	}
+
+	lengthCounts := make([]int, 19)
+	for _, t := range tokens {
+		lengthCounts[t.symbol]++
+	}
+	// The code length code needs two symbols at least
+	for s := 0; countUsed(lengthCounts) < 2; s++ {
+		if lengthCounts[s] == 0 {
+			lengthCounts[s] = 1
+		}
+	}
+	lengthLengths := huffmanLengths(lengthCounts, webpMaxCodeLengths)
+	lengthCode := newWebPCode(lengthLengths)
+
+	n := len(webpCodeLengthOrder)
+	for n > 4 && lengthLengths[webpCodeLengthOrder[n-1]] == 0 {
+		n--
+	}
+
+	bw.write(0, 1)
+	bw.write(uint32(n-4), 4)
+	for _, s := range webpCodeLengthOrder[:n] {
+		bw.write(uint32(lengthLengths[s]), 3)
+	}
+	bw.write(0, 1) // All symbols have a length
+	for _, t := range tokens {
+		lengthCode.write(bw, t.symbol)
+		switch t.symbol {
+		case 17:
+			bw.write(uint32(t.extra), 3)
+		case 18:
+			bw.write(uint32(t.extra), 7)
+		}
+	}
+
+	return newWebPCode(lengths)
+}
+
+func countUsed(counts []int) int {
+	n := 0
+	for _, c := range counts {
+		if c > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+type huffmanNode struct {
+	count       int
+	symbol      int
+	left, right *huffmanNode
+}
+
+type huffmanHeap []*huffmanNode
+
+func (h huffmanHeap) Len() int { return len(h) }
+func (h huffmanHeap) Less(i, j int) bool {
+	if h[i].count == h[j].count {
+		return h[i].symbol < h[j].symbol
+	}
+	return h[i].count < h[j].count
+}
+func (h huffmanHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *huffmanHeap) Push(x any)   { *h = append(*h, x.(*huffmanNode)) }
+func (h *huffmanHeap) Pop() any {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// huffmanLengths returns the code lengths of a Huffman code for the counts of
+// at least two symbols, no longer than limit. Small counts are raised until
+// the code fits.
+func huffmanLengths(counts []int, limit int) []int {
+	for floor := 1; ; floor *= 2 {
+		h := huffmanHeap{}
+		for s, c := range counts {
+			if c > 0 {
+				h = append(h, &huffmanNode{count: max(c, floor), symbol: s})
+			}
+		}
+		heap.Init(&h)
+		for h.Len() > 1 {
+			a := heap.Pop(&h).(*huffmanNode)
+			b := heap.Pop(&h).(*huffmanNode)
+			heap.Push(&h, &huffmanNode{count: a.count + b.count, symbol: min(a.symbol, b.symbol), left: a, right: b})
+		}
+
+		lengths := make([]int, len(counts))
+		var walk func(n *huffmanNode, depth int)
+		walk = func(n *huffmanNode, depth int) {
+			if n.left == nil {
+				lengths[n.symbol] = depth
+				return
+			}
+			walk(n.left, depth+1)
+			walk(n.right, depth+1)
+		}
+		walk(h[0], 0)
+
+		longest := 0
+		for _, l := range lengths {
+			longest = max(longest, l)
+		}
+		if longest <= limit {
+			return lengths
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/webp"
+)
+
+// tileMap returns an image of a tile of random colors repeated, with a few
+// rows of noise, like maps rendered from tilesets
+func tileMap(size int) *image.NRGBA {
+	rnd := rand.New(rand.NewSource(1))
+	tile := make([]color.NRGBA, 16*16)
+	for i := range tile {
+		tile[i] = color.NRGBA{uint8(rnd.Intn(256)), uint8(rnd.Intn(256)), uint8(rnd.Intn(256)), uint8(rnd.Intn(2) * 255)}
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			c := tile[y%16*16+x%16]
+			if y < 3 {
+				c = color.NRGBA{uint8(rnd.Intn(256)), uint8(rnd.Intn(256)), uint8(rnd.Intn(256)), 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestEncodeWebP(t *testing.T) {
+	img := tileMap(512)
+	var out bytes.Buffer
+	assert.NoError(t, EncodeWebP(&out, img, 100))
+
+	decoded, err := webp.Decode(bytes.NewReader(out.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, img.Bounds(), decoded.Bounds())
+	for y := 0; y < 512; y++ {
+		for x := 0; x < 512; x++ {
+			want := img.NRGBAAt(x, y)
+			if want.A == 0 {
+				want = color.NRGBA{}
+			}
+			if got := color.NRGBAModel.Convert(decoded.At(x, y)); got != want {
+				t.Fatalf("pixel %d,%d: got %v, want %v", x, y, got, want)
+			}
+		}
+	}
+
+	// Repeated tiles are written as backward references
+	var pngOut bytes.Buffer
+	assert.NoError(t, png.Encode(&pngOut, img))
+	assert.Less(t, out.Len(), pngOut.Len())
+}
+
+func TestEncodeWebPQuality(t *testing.T) {
+	img := tileMap(64)
+	var out bytes.Buffer
+	assert.NoError(t, EncodeWebP(&out, img, 50))
+
+	decoded, err := webp.Decode(bytes.NewReader(out.Bytes()))
+	if !assert.NoError(t, err) {
+		return
+	}
+	// Colors lose their 2 least significant bits
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			want := img.NRGBAAt(x, y)
+			got := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+			if want.A == 0 {
+				continue
+			}
+			for _, c := range [][2]uint8{{want.R, got.R}, {want.G, got.G}, {want.B, got.B}} {
+				if !assert.InDelta(t, c[0], c[1], 3, "pixel %d,%d", x, y) {
+					return
+				}
+			}
+		}
+	}
+
+	assert.ErrorIs(t, EncodeWebP(&out, image.NewNRGBA(image.Rect(0, 0, 0, 5)), 100), ErrWebPTooLarge)
+}
@@ -0,0 +1,190 @@
+package tiled
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ErrUnknownLintRule error is returned when linting a map with a rule name
+// that was not registered
+var ErrUnknownLintRule = errors.New("tiled: unknown lint rule")
+
+// DefaultMaxEmptyRatio is the ratio of empty cells above which the
+// "empty-layers" rule reports a tile layer
+const DefaultMaxEmptyRatio = 0.9
+
+// Issue is a problem found in a map by a lint rule
+type Issue struct {
+	// Name of the rule that found the issue, set by Lint when empty
+	Rule string
+	// Parts of the map involved, when any
+	Layer       *Layer
+	ObjectGroup *ObjectGroup
+	Object      *Object
+	Tileset     *Tileset
+	Message     string
+}
+
+// String returns the issue as "rule: message"
+func (i Issue) String() string {
+	return i.Rule + ": " + i.Message
+}
+
+// LintRule checks a map against a level standard, returning the issues found
+type LintRule func(m *Map) []Issue
+
+var (
+	lintRulesMu sync.RWMutex
+	lintRules   = map[string]LintRule{
+		"unused-tilesets":       lintUnusedTilesets,
+		"objects-out-of-bounds": lintObjectsOutOfBounds,
+		"empty-layers":          EmptyLayersRule(DefaultMaxEmptyRatio),
+	}
+)
+
+// RegisterLintRule registers a rule under the given name, so it is checked
+// by Lint. It is meant to be called from init functions of packages sharing
+// level standards, and panics if the name is already registered or rule is
+// nil.
+func RegisterLintRule(name string, rule LintRule) {
+	lintRulesMu.Lock()
+	defer lintRulesMu.Unlock()
+	if rule == nil {
+		panic("tiled: RegisterLintRule rule is nil")
+	}
+	if _, dup := lintRules[name]; dup {
+		panic("tiled: RegisterLintRule called twice for rule " + name)
+	}
+	lintRules[name] = rule
+}
+
+// unregisterLintRule removes a registered rule, so tests can register theirs
+// without leaking them
+func unregisterLintRule(name string) {
+	lintRulesMu.Lock()
+	defer lintRulesMu.Unlock()
+	delete(lintRules, name)
+}
+
+// LintRules returns the names of the registered lint rules, sorted
+func LintRules() []string {
+	lintRulesMu.RLock()
+	defer lintRulesMu.RUnlock()
+	names := make([]string, 0, len(lintRules))
+	for name := range lintRules {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Lint checks the map against the registered rules with the given names, in
+// order, or against all registered rules in the order of LintRules when none
+// is given. It fails with ErrUnknownLintRule if a name is not registered.
+func (m *Map) Lint(rules ...string) ([]Issue, error) {
+	if len(rules) == 0 {
+		rules = LintRules()
+	}
+
+	var res []Issue
+	for _, name := range rules {
+		lintRulesMu.RLock()
+		rule := lintRules[name]
+		lintRulesMu.RUnlock()
+		if rule == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownLintRule, name)
+		}
+		for _, issue := range rule(m) {
+			if issue.Rule == "" {
+				issue.Rule = name
+			}
+			res = append(res, issue)
+		}
+	}
+	return res, nil
+}
+
+// lintUnusedTilesets reports tilesets no tile of a tile layer or tile object
+// comes from
+func lintUnusedTilesets(m *Map) []Issue {
+	used := map[*Tileset]bool{}
+	m.walkLayers(func(l *Layer) {
+		for _, t := range l.Tiles {
+			if t != nil && !t.IsNil() {
+				used[t.Tileset] = true
+			}
+		}
+	}, func(og *ObjectGroup) {
+		for _, o := range og.Objects {
+			if o.GID == 0 {
+				continue
+			}
+			if t, err := m.TileGIDToTile(o.GID); err == nil {
+				used[t.Tileset] = true
+			}
+		}
+	})
+
+	var res []Issue
+	for _, ts := range m.Tilesets {
+		if !used[ts] {
+			res = append(res, Issue{Tileset: ts, Message: fmt.Sprintf("tileset %q is not used", ts.Name)})
+		}
+	}
+	return res
+}
+
+// lintObjectsOutOfBounds reports objects whose bounding box is entirely
+// outside of the map, of its width and height in tiles of the map tile size
+func lintObjectsOutOfBounds(m *Map) []Issue {
+	bounds := Rectangle{Max: Point{
+		X: float64(m.Width * m.TileWidth),
+		Y: float64(m.Height * m.TileHeight),
+	}}
+
+	var res []Issue
+	m.walkLayers(nil, func(og *ObjectGroup) {
+		for _, o := range og.Objects {
+			box := o.BoundingBox()
+			inside := box.Intersects(bounds) || bounds.Contains(box.Min)
+			if !inside {
+				res = append(res, Issue{
+					ObjectGroup: og,
+					Object:      o,
+					Message:     fmt.Sprintf("object %d of %q is outside of the map", o.ID, og.Name),
+				})
+			}
+		}
+	})
+	return res
+}
+
+// EmptyLayersRule returns a rule reporting tile layers with more than
+// maxEmpty of their cells empty, from 0 to 1. It is registered as
+// "empty-layers" with DefaultMaxEmptyRatio, other thresholds can be
+// registered under other names.
+func EmptyLayersRule(maxEmpty float64) LintRule {
+	return func(m *Map) []Issue {
+		var res []Issue
+		m.walkLayers(func(l *Layer) {
+			if len(l.Tiles) == 0 {
+				return
+			}
+			empty := 0
+			for _, t := range l.Tiles {
+				if t == nil || t.IsNil() {
+					empty++
+				}
+			}
+			if ratio := float64(empty) / float64(len(l.Tiles)); ratio > maxEmpty {
+				res = append(res, Issue{
+					Layer:   l,
+					Message: fmt.Sprintf("layer %q is %.0f%% empty", l.Name, ratio*100),
+				})
+			}
+		}, nil)
+		return res
+	}
+}
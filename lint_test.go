@@ -0,0 +1,71 @@
+package tiled
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	m, err := LoadReader(".", bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="4" height="1" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="ground" tilewidth="16" tileheight="16" tilecount="4" columns="4"/>
+<tileset firstgid="5" name="props" tilewidth="16" tileheight="16" tilecount="4" columns="4"/>
+<tileset firstgid="9" name="unused" tilewidth="16" tileheight="16" tilecount="4" columns="4"/>
+<layer id="1" name="Ground" width="4" height="1"><data encoding="csv">1,2,3,4</data></layer>
+<group id="2" name="Details">
+<layer id="3" name="Decals" width="4" height="1"><data encoding="csv">0,0,0,0</data></layer>
+<objectgroup id="4" name="Objects">
+<object id="1" gid="5" x="0" y="16" width="16" height="16"/>
+<object id="2" x="60" y="10"/>
+<object id="3" x="64" y="0" width="8" height="8"/>
+<object id="4" x="-20" y="0"><polyline points="0,0 30,10"/></object>
+</objectgroup>
+</group>
+</map>`))
+	assert.NoError(t, err)
+
+	issues, err := m.Lint()
+	assert.NoError(t, err)
+	var messages []string
+	for _, issue := range issues {
+		messages = append(messages, issue.String())
+	}
+	assert.Equal(t, []string{
+		`empty-layers: layer "Decals" is 100% empty`,
+		`objects-out-of-bounds: object 3 of "Objects" is outside of the map`,
+		`unused-tilesets: tileset "unused" is not used`,
+	}, messages)
+	assert.Equal(t, m.Tilesets[2], issues[2].Tileset)
+
+	issues, err = m.Lint("empty-layers")
+	assert.NoError(t, err)
+	assert.Len(t, issues, 1)
+
+	RegisterLintRule("test-named-layers", func(m *Map) []Issue {
+		var res []Issue
+		for _, l := range m.Layers {
+			if l.Class == "" {
+				res = append(res, Issue{Layer: l, Message: fmt.Sprintf("layer %q has no class", l.Name)})
+			}
+		}
+		return res
+	})
+	RegisterLintRule("test-mostly-empty", EmptyLayersRule(0.5))
+	t.Cleanup(func() {
+		unregisterLintRule("test-named-layers")
+		unregisterLintRule("test-mostly-empty")
+	})
+	issues, err = m.Lint("test-named-layers", "test-mostly-empty")
+	assert.NoError(t, err)
+	assert.Len(t, issues, 2)
+	assert.Equal(t, "test-named-layers", issues[0].Rule)
+	assert.Equal(t, "test-mostly-empty", issues[1].Rule)
+	assert.Contains(t, LintRules(), "test-named-layers")
+
+	_, err = m.Lint("missing")
+	assert.ErrorIs(t, err, ErrUnknownLintRule)
+	assert.Panics(t, func() { RegisterLintRule("empty-layers", EmptyLayersRule(0)) })
+}
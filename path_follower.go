@@ -0,0 +1,162 @@
+package tiled
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrNotAPath error is returned when following an object which is not a
+// polyline or a polygon
+var ErrNotAPath = errors.New("tiled: object is not a path")
+
+// PathMode selects what happens when a PathFollower reaches the end of its path
+type PathMode string
+
+const (
+	// PathOnce stops at the end of the path
+	PathOnce PathMode = "once"
+	// PathLoop starts again from the beginning of the path
+	PathLoop PathMode = "loop"
+	// PathPingPong goes back and forth along the path
+	PathPingPong PathMode = "pingpong"
+)
+
+const (
+	// DefaultPathSpeedProperty is the float object property holding the speed
+	// of a path in pixels per second
+	DefaultPathSpeedProperty = "speed"
+	// DefaultPathModeProperty is the object property holding the PathMode of
+	// a path
+	DefaultPathModeProperty = "mode"
+)
+
+// PathFollower computes positions along a polyline or polygon object, for
+// example to move platforms along paths drawn in Tiled.
+type PathFollower struct {
+	// Speed in pixels per second
+	Speed float64
+	// What happens at the end of the path. Polygons are closed paths, so
+	// PathLoop goes around them.
+	Mode PathMode
+
+	points []Point
+	// distance along the path at each point
+	distances []float64
+}
+
+// NewPathFollower creates a PathFollower for a polyline or polygon object,
+// reading its speed and mode from the DefaultPathSpeedProperty and
+// DefaultPathModeProperty properties. The mode defaults to PathLoop.
+func NewPathFollower(o *Object) (*PathFollower, error) {
+	var points []Point
+	switch {
+	case len(o.PolyLines) > 0:
+		points = toPoints(o.PolyLines[0].Points)
+	case len(o.Polygons) > 0:
+		points = toPoints(o.Polygons[0].Points)
+		if len(points) > 0 {
+			points = append(points, points[0])
+		}
+	}
+	for i := range points {
+		points[i] = o.transform(points[i])
+	}
+	speed := o.Properties.GetFloat(DefaultPathSpeedProperty)
+	if speed == 0 {
+		speed = float64(o.Properties.GetInt(DefaultPathSpeedProperty))
+	}
+	mode := PathMode(o.Properties.GetString(DefaultPathModeProperty))
+	if mode == "" {
+		mode = PathLoop
+	}
+	return NewPathFollowerFromPoints(points, speed, mode)
+}
+
+// NewPathFollowerFromPoints creates a PathFollower going through points in
+// map coordinates, for paths defined in code. Closed paths repeat their first
+// point at the end.
+func NewPathFollowerFromPoints(points []Point, speed float64, mode PathMode) (*PathFollower, error) {
+	if len(points) == 0 {
+		return nil, ErrNotAPath
+	}
+
+	p := &PathFollower{
+		Speed:     speed,
+		Mode:      mode,
+		points:    make([]Point, len(points)),
+		distances: make([]float64, len(points)),
+	}
+	for i, point := range points {
+		p.points[i] = point
+		if i > 0 {
+			prev := p.points[i-1]
+			p.distances[i] = p.distances[i-1] + math.Hypot(p.points[i].X-prev.X, p.points[i].Y-prev.Y)
+		}
+	}
+	return p, nil
+}
+
+// Length returns the length of the path in pixels
+func (p *PathFollower) Length() float64 {
+	return p.distances[len(p.distances)-1]
+}
+
+// Duration returns the time needed to go once along the path at Speed
+func (p *PathFollower) Duration() time.Duration {
+	if p.Speed <= 0 {
+		return 0
+	}
+	return time.Duration(p.Length() / p.Speed * float64(time.Second))
+}
+
+// distance returns the distance along the path at time t, and whether the
+// path is being followed backwards.
+func (p *PathFollower) distance(t time.Duration) (float64, bool) {
+	length := p.Length()
+	d := p.Speed * t.Seconds()
+	if length == 0 || d <= 0 {
+		return 0, false
+	}
+
+	switch p.Mode {
+	case PathLoop:
+		return math.Mod(d, length), false
+	case PathPingPong:
+		d = math.Mod(d, 2*length)
+		if d > length {
+			return 2*length - d, true
+		}
+		return d, false
+	}
+	return math.Min(d, length), false
+}
+
+// At returns the position on the path at time t, and the rotation in degrees
+// clockwise of the direction of travel, 0 pointing right.
+func (p *PathFollower) At(t time.Duration) (Point, float64) {
+	if len(p.points) == 1 {
+		return p.points[0], 0
+	}
+
+	d, backwards := p.distance(t)
+
+	// Find the segment containing d, skipping empty segments
+	i := 1
+	for i < len(p.points)-1 && p.distances[i] <= d {
+		i++
+	}
+	a, b := p.points[i-1], p.points[i]
+
+	pos := a
+	if l := p.distances[i] - p.distances[i-1]; l > 0 {
+		f := (d - p.distances[i-1]) / l
+		pos = Point{X: a.X + (b.X-a.X)*f, Y: a.Y + (b.Y-a.Y)*f}
+	}
+
+	rotation := math.Atan2(b.Y-a.Y, b.X-a.X) * 180 / math.Pi
+	if backwards {
+		rotation += 180
+	}
+	return pos, math.Mod(rotation+360, 360)
+}
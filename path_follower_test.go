@@ -0,0 +1,78 @@
+package tiled
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathFollower(t *testing.T) {
+	o := &Object{
+		X: 10, Y: 20,
+		PolyLines: []*PolyLine{{Points: &Points{{0, 0}, {100, 0}, {100, 50}}}},
+		Properties: Properties{
+			{Name: "speed", Type: "float", Value: "50"},
+			{Name: "mode", Value: "pingpong"},
+		},
+	}
+
+	p, err := NewPathFollower(o)
+	assert.NoError(t, err)
+	assert.Equal(t, PathPingPong, p.Mode)
+	assert.Equal(t, 150.0, p.Length())
+	assert.Equal(t, 3*time.Second, p.Duration())
+
+	pos, rotation := p.At(time.Second)
+	assert.Equal(t, Point{X: 60, Y: 20}, pos)
+	assert.Equal(t, 0.0, rotation)
+
+	pos, rotation = p.At(2500 * time.Millisecond)
+	assert.Equal(t, Point{X: 110, Y: 45}, pos)
+	assert.Equal(t, 90.0, rotation)
+
+	// Going back along the last segment
+	pos, rotation = p.At(3500 * time.Millisecond)
+	assert.Equal(t, Point{X: 110, Y: 45}, pos)
+	assert.Equal(t, 270.0, rotation)
+
+	p.Mode = PathOnce
+	pos, _ = p.At(10 * time.Second)
+	assert.Equal(t, Point{X: 110, Y: 70}, pos)
+
+	p.Mode = PathLoop
+	pos, _ = p.At(4 * time.Second)
+	assert.Equal(t, Point{X: 60, Y: 20}, pos)
+}
+
+func TestPathFollowerPolygon(t *testing.T) {
+	o := &Object{
+		Polygons:   []*Polygon{{Points: &Points{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}},
+		Properties: Properties{{Name: "speed", Type: "int", Value: "10"}},
+	}
+
+	p, err := NewPathFollower(o)
+	assert.NoError(t, err)
+	assert.Equal(t, PathLoop, p.Mode)
+	assert.Equal(t, 40.0, p.Length())
+
+	pos, rotation := p.At(3500 * time.Millisecond)
+	assert.Equal(t, Point{X: 0, Y: 5}, pos)
+	assert.Equal(t, 270.0, rotation)
+
+	_, err = NewPathFollower(&Object{Width: 10, Height: 10})
+	assert.ErrorIs(t, err, ErrNotAPath)
+}
+
+func TestPathFollowerFromPoints(t *testing.T) {
+	p, err := NewPathFollowerFromPoints([]Point{{X: 0, Y: 0}, {X: 0, Y: 30}}, 10, PathOnce)
+	assert.NoError(t, err)
+	assert.Equal(t, 3*time.Second, p.Duration())
+
+	pos, rotation := p.At(time.Second)
+	assert.Equal(t, Point{X: 0, Y: 10}, pos)
+	assert.Equal(t, 90.0, rotation)
+
+	_, err = NewPathFollowerFromPoints(nil, 10, PathOnce)
+	assert.ErrorIs(t, err, ErrNotAPath)
+}
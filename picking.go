@@ -0,0 +1,298 @@
+package tiled
+
+import (
+	"cmp"
+	"math"
+	"slices"
+)
+
+// TileOpaqueFunc reports whether the pixel at x, y of the image of a tile,
+// before flipping, is opaque
+type TileOpaqueFunc func(tile *LayerTile, x, y int) bool
+
+// hexParams are the cell measures of staggered and hexagonal maps, as Tiled
+// computes them
+type hexParams struct {
+	staggerX, staggerEven  bool
+	tileWidth, tileHeight  float64
+	sideLengthX            float64
+	sideLengthY            float64
+	sideOffsetX            float64
+	sideOffsetY            float64
+	columnWidth, rowHeight float64
+}
+
+func (m *Map) hexParams() hexParams {
+	p := hexParams{
+		staggerX:    m.StaggerAxis == AxisX,
+		staggerEven: m.StaggerIndex == StaggerIndexEven,
+		tileWidth:   float64(m.TileWidth &^ 1),
+		tileHeight:  float64(m.TileHeight &^ 1),
+	}
+	if m.Orientation == "hexagonal" {
+		if p.staggerX {
+			p.sideLengthX = float64(m.HexSideLength)
+		} else {
+			p.sideLengthY = float64(m.HexSideLength)
+		}
+	}
+	p.sideOffsetX = (p.tileWidth - p.sideLengthX) / 2
+	p.sideOffsetY = (p.tileHeight - p.sideLengthY) / 2
+	p.columnWidth = p.sideOffsetX + p.sideLengthX
+	p.rowHeight = p.sideOffsetY + p.sideLengthY
+	return p
+}
+
+// staggered reports whether the row or column at index i is shifted
+func (p hexParams) staggered(i int) bool {
+	return (i&1 == 1) != p.staggerEven
+}
+
+// cellBounds returns the top left corner and the size of the bounding box of
+// the cell at x, y, in pixels
+func (m *Map) cellBounds(x, y int) (left, top, width, height float64) {
+	tw, th := float64(m.TileWidth), float64(m.TileHeight)
+	switch m.Orientation {
+	case "isometric":
+		originX := float64(m.Height) * tw / 2
+		return float64(x-y)*tw/2 + originX - tw/2, float64(x+y) * th / 2, tw, th
+	case "staggered", "hexagonal":
+		p := m.hexParams()
+		if p.staggerX {
+			left, top = float64(x)*p.columnWidth, float64(y)*(p.tileHeight+p.sideLengthY)
+			if p.staggered(x) {
+				top += p.rowHeight
+			}
+		} else {
+			left, top = float64(x)*(p.tileWidth+p.sideLengthX), float64(y)*p.rowHeight
+			if p.staggered(y) {
+				left += p.columnWidth
+			}
+		}
+		return left, top, p.tileWidth, p.tileHeight
+	default:
+		return float64(x) * tw, float64(y) * th, tw, th
+	}
+}
+
+// cellShape returns the outline of the cell at x, y
+func (m *Map) cellShape(x, y int) []Point {
+	left, top, w, h := m.cellBounds(x, y)
+	switch m.Orientation {
+	case "isometric":
+		return []Point{{left + w/2, top}, {left + w, top + h/2}, {left + w/2, top + h}, {left, top + h/2}}
+	case "staggered", "hexagonal":
+		p := m.hexParams()
+		if p.staggerX {
+			return []Point{
+				{left, top + h/2}, {left + p.sideOffsetX, top}, {left + p.sideOffsetX + p.sideLengthX, top},
+				{left + w, top + h/2}, {left + p.sideOffsetX + p.sideLengthX, top + h}, {left + p.sideOffsetX, top + h},
+			}
+		}
+		return []Point{
+			{left + w/2, top}, {left + w, top + p.sideOffsetY}, {left + w, top + p.sideOffsetY + p.sideLengthY},
+			{left + w/2, top + h}, {left, top + p.sideOffsetY + p.sideLengthY}, {left, top + p.sideOffsetY},
+		}
+	default:
+		return []Point{{left, top}, {left + w, top}, {left + w, top + h}, {left, top + h}}
+	}
+}
+
+// TileToPixel returns the top left corner of the bounding box of the cell at
+// x, y, in pixels
+func (m *Map) TileToPixel(x, y int) (float64, float64) {
+	left, top, _, _ := m.cellBounds(x, y)
+	return left, top
+}
+
+// PixelToTile returns the coordinates of the cell holding the point at x, y
+// in pixels, following the map orientation. The cell may be outside of the
+// map.
+func (m *Map) PixelToTile(x, y float64) (int, int) {
+	tw, th := float64(m.TileWidth), float64(m.TileHeight)
+	switch m.Orientation {
+	case "isometric":
+		x -= float64(m.Height) * tw / 2
+		return int(math.Floor(y/th + x/tw)), int(math.Floor(y/th - x/tw))
+	case "staggered", "hexagonal":
+		// Cells overlap their neighbours' bounding boxes, the neighbourhood
+		// of the estimate is searched for the cell holding the point
+		p := m.hexParams()
+		var cx, cy int
+		if p.staggerX {
+			cx, cy = int(math.Floor(x/p.columnWidth)), int(math.Floor(y/(p.tileHeight+p.sideLengthY)))
+		} else {
+			cx, cy = int(math.Floor(x/(p.tileWidth+p.sideLengthX))), int(math.Floor(y/p.rowHeight))
+		}
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if pointInPolygon(Point{x, y}, m.cellShape(cx+dx, cy+dy)) {
+					return cx + dx, cy + dy
+				}
+			}
+		}
+		return cx, cy
+	default:
+		return int(math.Floor(x / tw)), int(math.Floor(y / th))
+	}
+}
+
+// PickTile returns the top most tile of the layer drawn at x, y in pixels,
+// with its cell coordinates. Unlike PixelToTile, it accounts for tile images
+// larger than a cell overlapping their neighbours, like tall isometric tiles.
+// When opaque is not nil, transparent pixels of tile images are not hit.
+func (m *Map) PickTile(layer *Layer, x, y float64, opaque TileOpaqueFunc) (*LayerTile, int, int, bool) {
+	x -= float64(layer.OffsetX)
+	y -= float64(layer.OffsetY)
+
+	var (
+		best         *LayerTile
+		bestX, bestY int
+		bestTop      float64
+		bestLeft     float64
+	)
+	for cell, tile := range layer.Cells() {
+		cx, cy := cell.X, cell.Y
+		left, top, _, height := m.cellBounds(cx, cy)
+		if best != nil && (top < bestTop || top == bestTop && left < bestLeft) {
+			// Drawn below the current pick
+			continue
+		}
+
+		// Tile images are drawn from the bottom left corner of the cell
+		w, h := tileImageSize(tile)
+		if tile.DiagonalFlip {
+			w, h = h, w
+		}
+		imgLeft, imgBottom := left, top+height
+		if off := tile.Tileset.TileOffset; off != nil {
+			imgLeft += float64(off.X)
+			imgBottom += float64(off.Y)
+		}
+		px, py := int(math.Floor(x-imgLeft)), int(math.Floor(y-(imgBottom-float64(h))))
+		if px < 0 || py < 0 || px >= w || py >= h {
+			continue
+		}
+
+		if opaque != nil {
+			if tile.HorizontalFlip {
+				px = w - 1 - px
+			}
+			if tile.VerticalFlip {
+				py = h - 1 - py
+			}
+			if tile.DiagonalFlip {
+				px, py = py, px
+			}
+			if !opaque(tile, px, py) {
+				continue
+			}
+		}
+
+		best, bestX, bestY, bestTop, bestLeft = tile, cx, cy, top, left
+	}
+	return best, bestX, bestY, best != nil
+}
+
+// tileImageSize returns the size of the image of a tile
+func tileImageSize(tile *LayerTile) (int, int) {
+	ts := tile.Tileset
+	if ts.Image == nil {
+		if t := ts.Tile(tile.ID); t != nil && t.Image != nil {
+			return t.Image.Width, t.Image.Height
+		}
+	}
+	return ts.TileWidth, ts.TileHeight
+}
+
+// pointInPolygon reports whether p is inside the polygon, by ray casting
+func pointInPolygon(p Point, polygon []Point) bool {
+	in := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			in = !in
+		}
+	}
+	return in
+}
+
+// ObjectAtPixelPrecise returns the top most visible object of the group at x,
+// y in pixels, or nil. Shape objects are hit inside their outline. Tile
+// objects are hit inside their rectangle and, when opaque is not nil, only on
+// opaque pixels of their tile image, so sprites with transparent margins are
+// not hit around their visible part.
+func (m *Map) ObjectAtPixelPrecise(group *ObjectGroup, x, y float64, opaque TileOpaqueFunc) (*Object, error) {
+	p := Point{X: x - float64(group.OffsetX), Y: y - float64(group.OffsetY)}
+
+	objs := group.Objects
+	if group.DrawOrder != DrawOrderIndex {
+		objs = slices.Clone(objs)
+		slices.SortStableFunc(objs, func(a, b *Object) int { return cmp.Compare(a.Y, b.Y) })
+	}
+
+	for i := len(objs) - 1; i >= 0; i-- {
+		o := objs[i]
+		if !o.Visible {
+			continue
+		}
+		if o.GID == 0 {
+			if o.Contains(p) {
+				return o, nil
+			}
+			continue
+		}
+
+		tile, err := m.TileGIDToTile(o.GID)
+		if err != nil {
+			return nil, err
+		}
+		if tileObjectHit(o, tile, p, opaque) {
+			return o, nil
+		}
+	}
+	return nil, nil
+}
+
+// tileObjectHit reports whether the point hits the image of a tile object,
+// drawn like the renderer does
+func tileObjectHit(o *Object, tile *LayerTile, p Point, opaque TileOpaqueFunc) bool {
+	srcWidth, srcHeight := tileImageSize(tile)
+	if srcWidth == 0 || srcHeight == 0 {
+		return false
+	}
+
+	// Objects without size are drawn at the natural size of their tile
+	width, height := o.Width, o.Height
+	if width == 0 {
+		width = float64(srcWidth)
+	}
+	if height == 0 {
+		height = float64(srcHeight)
+	}
+
+	local := o.untransform(p)
+	u, v := local.X/width, (local.Y+height)/height
+	if u < 0 || v < 0 || u >= 1 || v >= 1 {
+		return false
+	}
+	if opaque == nil {
+		return true
+	}
+
+	w, h := srcWidth, srcHeight
+	if tile.DiagonalFlip {
+		w, h = h, w
+	}
+	px, py := int(u*float64(w)), int(v*float64(h))
+	if tile.HorizontalFlip {
+		px = w - 1 - px
+	}
+	if tile.VerticalFlip {
+		py = h - 1 - py
+	}
+	if tile.DiagonalFlip {
+		px, py = py, px
+	}
+	return opaque(tile, px, py)
+}
@@ -0,0 +1,91 @@
+package tiled
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pickingTestMap = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="isometric" width="4" height="4" tilewidth="64" tileheight="32">
+<tileset firstgid="1" name="blocks" tilewidth="64" tileheight="64" tilecount="1" columns="1">
+<image source="blocks.png" width="64" height="64"/>
+</tileset>
+<layer id="1" name="Ground" width="4" height="4">
+<data encoding="csv">1,1,1,1,1,1,1,1,1,1,1,1,1,1,1,1</data>
+</layer>
+</map>`
+
+func TestPixelToTile(t *testing.T) {
+	m, err := LoadReader(GetAssetsDirectory(), bytes.NewBufferString(pickingTestMap))
+	assert.NoError(t, err)
+
+	x, y := m.PixelToTile(128, 16)
+	assert.Equal(t, []int{0, 0}, []int{x, y})
+	x, y = m.PixelToTile(128, 48)
+	assert.Equal(t, []int{1, 1}, []int{x, y})
+
+	hex := &Map{Orientation: "hexagonal", Width: 4, Height: 4, TileWidth: 14, TileHeight: 12,
+		HexSideLength: 6, StaggerAxis: AxisY, StaggerIndex: StaggerIndexOdd}
+	px, py := hex.TileToPixel(0, 1)
+	assert.Equal(t, []float64{7, 9}, []float64{px, py})
+	x, y = hex.PixelToTile(7, 6)
+	assert.Equal(t, []int{0, 0}, []int{x, y})
+	x, y = hex.PixelToTile(14, 15)
+	assert.Equal(t, []int{0, 1}, []int{x, y})
+}
+
+func TestPickTile(t *testing.T) {
+	m, err := LoadReader(GetAssetsDirectory(), bytes.NewBufferString(pickingTestMap))
+	assert.NoError(t, err)
+	layer := m.Layers[0]
+
+	// Tall tiles in front cover the cell under the point
+	_, x, y, ok := m.PickTile(layer, 128, 10, nil)
+	assert.True(t, ok)
+	assert.Equal(t, []int{1, 1}, []int{x, y})
+
+	// Only the block at the bottom of tile images is opaque
+	opaque := func(tile *LayerTile, px, py int) bool { return py >= 32 }
+	_, x, y, ok = m.PickTile(layer, 128, 10, opaque)
+	assert.True(t, ok)
+	assert.Equal(t, []int{0, 0}, []int{x, y})
+
+	_, _, _, ok = m.PickTile(layer, 0, 0, nil)
+	assert.False(t, ok)
+}
+
+func TestObjectAtPixelPrecise(t *testing.T) {
+	m, err := LoadReader(GetAssetsDirectory(), bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="8" height="8" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="sprites" tilewidth="32" tileheight="32" tilecount="1" columns="1">
+<image source="sprites.png" width="32" height="32"/>
+</tileset>
+<objectgroup id="1" name="Objects">
+<object id="1" x="0" y="0" width="64" height="64"><ellipse/></object>
+<object id="2" gid="1" x="16" y="48" width="32" height="32"/>
+<object id="3" x="100" y="0"><polyline points="0,0 10,10"/></object>
+</objectgroup>
+</map>`))
+	assert.NoError(t, err)
+	group := m.ObjectGroups[0]
+
+	o, err := m.ObjectAtPixelPrecise(group, 20, 20, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), o.ID)
+
+	// The sprite is opaque in its right half only
+	opaque := func(tile *LayerTile, px, py int) bool { return px >= 16 }
+	o, err = m.ObjectAtPixelPrecise(group, 20, 20, opaque)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), o.ID)
+
+	o, err = m.ObjectAtPixelPrecise(group, 2, 2, opaque)
+	assert.NoError(t, err)
+	assert.Nil(t, o)
+
+	o, err = m.ObjectAtPixelPrecise(group, 105, 5, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, o)
+}
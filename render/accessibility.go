@@ -0,0 +1,240 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"slices"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+)
+
+// Default accessibility rules
+const (
+	DefaultHazardClass   = "hazard"
+	DefaultMinContrast   = 3.0
+	DefaultMinObjectSize = 16.0
+)
+
+// AccessibilityRules configures the heuristics checked by AccessibilityReport.
+// Zero values use the defaults.
+type AccessibilityRules struct {
+	// Class of the tiles that must stand out from their surroundings
+	HazardClass string
+	// Minimum WCAG contrast ratio between hazard tiles and their neighbours,
+	// from 1 to 21
+	MinContrast float64
+	// Minimum width and height in pixels of objects
+	MinObjectSize float64
+	// Classes of the objects checked for their size, all when empty
+	ObjectClasses []string
+}
+
+// AccessibilityIssue is a part of a map failing an accessibility rule
+type AccessibilityIssue struct {
+	// Rule failed, "contrast" or "size"
+	Rule string
+	// Tile position, for contrast issues
+	X, Y int
+	// Object too small, for size issues
+	Object  *tiled.Object
+	Message string
+}
+
+// AccessibilityReport lists the accessibility issues found in a map
+type AccessibilityReport struct {
+	Issues []AccessibilityIssue
+}
+
+// AccessibilityReport checks the visible layers and object groups of the map
+// against the rules. The contrast of hazard tiles is computed from the
+// average color of the tile drawn on top of each cell, with the cells next
+// to it.
+func (r *Renderer) AccessibilityReport(rules AccessibilityRules) (*AccessibilityReport, error) {
+	if rules.HazardClass == "" {
+		rules.HazardClass = DefaultHazardClass
+	}
+	if rules.MinContrast == 0 {
+		rules.MinContrast = DefaultMinContrast
+	}
+	if rules.MinObjectSize == 0 {
+		rules.MinObjectSize = DefaultMinObjectSize
+	}
+
+	rep := &AccessibilityReport{}
+	if err := r.checkContrast(rep, rules); err != nil {
+		return nil, err
+	}
+
+	for _, objectGroup := range r.m.ObjectGroups {
+		if objectGroup.Visible {
+			checkObjectSizes(rep, rules, objectGroup)
+		}
+	}
+	for _, group := range r.m.Groups {
+		if group.Visible {
+			for _, objectGroup := range group.ObjectGroups {
+				if objectGroup.Visible {
+					checkObjectSizes(rep, rules, objectGroup)
+				}
+			}
+		}
+	}
+
+	return rep, nil
+}
+
+// topTiles returns the tile drawn on top of each cell by the visible layers,
+// by row
+func (r *Renderer) topTiles() [][]*tiled.LayerTile {
+	top := make([][]*tiled.LayerTile, r.m.Height)
+	for y := range top {
+		top[y] = make([]*tiled.LayerTile, r.m.Width)
+	}
+	for _, layer := range r.m.Layers {
+		if !layer.Visible || layer.Opacity == 0 {
+			continue
+		}
+		for cell, tile := range layer.Cells() {
+			if cell.Y < len(top) {
+				top[cell.Y][cell.X] = tile
+			}
+		}
+	}
+	return top
+}
+
+func isHazard(tile *tiled.LayerTile, class string) bool {
+	t := tile.Tileset.Tile(tile.ID)
+	return t != nil && (t.Class == class || t.Type == class)
+}
+
+func (r *Renderer) checkContrast(rep *AccessibilityReport, rules AccessibilityRules) error {
+	top := r.topTiles()
+	colors := tileColors{r: r, images: map[string]image.Image{}, luminances: map[tileKey]float64{}}
+
+	for y, row := range top {
+		for x, tile := range row {
+			if tile == nil || !isHazard(tile, rules.HazardClass) {
+				continue
+			}
+
+			hazard, err := colors.luminance(tile)
+			if err != nil {
+				return err
+			}
+
+			lowest := math.Inf(1)
+			for _, d := range [4]image.Point{{0, -1}, {1, 0}, {0, 1}, {-1, 0}} {
+				nx, ny := x+d.X, y+d.Y
+				if ny < 0 || ny >= len(top) || nx < 0 || nx >= len(row) {
+					continue
+				}
+				neighbour := top[ny][nx]
+				if neighbour == nil || isHazard(neighbour, rules.HazardClass) {
+					continue
+				}
+				l, err := colors.luminance(neighbour)
+				if err != nil {
+					return err
+				}
+				lowest = min(lowest, contrastRatio(hazard, l))
+			}
+
+			if lowest < rules.MinContrast {
+				rep.Issues = append(rep.Issues, AccessibilityIssue{
+					Rule:    "contrast",
+					X:       x,
+					Y:       y,
+					Message: fmt.Sprintf("hazard tile at %d,%d has a contrast ratio of %.2f with its surroundings, minimum is %.2f", x, y, lowest, rules.MinContrast),
+				})
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkObjectSizes(rep *AccessibilityReport, rules AccessibilityRules, objectGroup *tiled.ObjectGroup) {
+	for _, o := range objectGroup.Objects {
+		if !o.Visible || o.Width == 0 || o.Height == 0 {
+			continue
+		}
+		if len(rules.ObjectClasses) > 0 && !slices.Contains(rules.ObjectClasses, o.Class) && !slices.Contains(rules.ObjectClasses, o.Type) {
+			continue
+		}
+		if o.Width < rules.MinObjectSize || o.Height < rules.MinObjectSize {
+			rep.Issues = append(rep.Issues, AccessibilityIssue{
+				Rule:    "size",
+				Object:  o,
+				Message: fmt.Sprintf("object %d %q is %gx%g pixels, minimum is %g", o.ID, o.Name, o.Width, o.Height, rules.MinObjectSize),
+			})
+		}
+	}
+}
+
+// tileColors computes the relative luminance of the average color of tiles
+type tileColors struct {
+	r          *Renderer
+	images     map[string]image.Image
+	luminances map[tileKey]float64
+}
+
+func (c *tileColors) luminance(tile *tiled.LayerTile) (float64, error) {
+	key := tileKey{tile.Tileset, tile.ID}
+	if l, ok := c.luminances[key]; ok {
+		return l, nil
+	}
+
+	path, err := tileImagePath(tile)
+	if err != nil {
+		return 0, err
+	}
+	img, ok := c.images[path]
+	if !ok {
+		if img, err = c.r.decodeImage(tile); err != nil {
+			return 0, err
+		}
+		c.images[path] = img
+	}
+
+	rect := img.Bounds()
+	if tile.Tileset.Image != nil {
+		rect = tile.Tileset.GetTileRect(tile.ID).Add(rect.Min)
+	}
+
+	// Average of the opaque parts of the tile
+	var sr, sg, sb, sa float64
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			sr += float64(r)
+			sg += float64(g)
+			sb += float64(b)
+			sa += float64(a)
+		}
+	}
+	l := 0.0
+	if sa > 0 {
+		l = relativeLuminance(sr/sa, sg/sa, sb/sa)
+	}
+	c.luminances[key] = l
+	return l, nil
+}
+
+// relativeLuminance returns the WCAG relative luminance of a color with
+// components from 0 to 1
+func relativeLuminance(r, g, b float64) float64 {
+	linear := func(v float64) float64 {
+		if v <= 0.03928 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(r) + 0.7152*linear(g) + 0.0722*linear(b)
+}
+
+// contrastRatio returns the WCAG contrast ratio of two relative luminances
+func contrastRatio(l1, l2 float64) float64 {
+	return (max(l1, l2) + 0.05) / (min(l1, l2) + 0.05)
+}
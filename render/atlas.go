@@ -0,0 +1,230 @@
+package render
+
+import (
+	"image"
+	"image/draw"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// DefaultAtlasPageSize is the default width and height in pixels of the
+// textures of an Atlas
+const DefaultAtlasPageSize = 4096
+
+type atlasPage struct {
+	img *ebiten.Image
+	// Current shelf
+	x, y, rowHeight int
+}
+
+type atlasRegion struct {
+	page int
+	rect image.Rectangle
+}
+
+// Atlas packs tileset images into a few large textures, so the tiles of a
+// layer are drawn with a DrawTriangles call per texture instead of a
+// DrawImage call per tile. An Atlas can be shared by multiple renderers and
+// is safe for concurrent use.
+type Atlas struct {
+	// Width and height in pixels of the textures. Images larger than this
+	// get a texture of their own.
+	PageSize int
+
+	mu      sync.Mutex
+	pages   []*atlasPage
+	regions map[string]atlasRegion
+	fs      fs.FS
+}
+
+// NewAtlas creates an Atlas with an optional filesystem (pointing to an embedded tiled project)
+func NewAtlas(fs fs.FS) *Atlas {
+	return &Atlas{
+		PageSize: DefaultAtlasPageSize,
+		regions:  map[string]atlasRegion{},
+		fs:       fs,
+	}
+}
+
+func (a *Atlas) open(f string) (io.ReadCloser, error) {
+	if a.fs == nil {
+		return os.Open(filepath.FromSlash(f))
+	}
+	return a.fs.Open(filepath.ToSlash(f))
+}
+
+// place finds room for an image of the given size with shelf packing,
+// adding a page when no page has room left.
+func (a *Atlas) place(width, height int) atlasRegion {
+	if width > a.PageSize || height > a.PageSize {
+		a.pages = append(a.pages, &atlasPage{
+			img: ebiten.NewImage(width, height),
+			x:   a.PageSize, y: a.PageSize,
+		})
+		return atlasRegion{page: len(a.pages) - 1, rect: image.Rect(0, 0, width, height)}
+	}
+
+	for i, p := range a.pages {
+		if p.x+width > a.PageSize {
+			if p.y+p.rowHeight+height > a.PageSize {
+				continue
+			}
+			p.x, p.y, p.rowHeight = 0, p.y+p.rowHeight, 0
+		}
+		if p.y+height > a.PageSize {
+			continue
+		}
+
+		rect := image.Rect(p.x, p.y, p.x+width, p.y+height)
+		p.x += width
+		p.rowHeight = max(p.rowHeight, height)
+		return atlasRegion{page: i, rect: rect}
+	}
+
+	a.pages = append(a.pages, &atlasPage{
+		img:       ebiten.NewImage(a.PageSize, a.PageSize),
+		x:         width,
+		rowHeight: height,
+	})
+	return atlasRegion{page: len(a.pages) - 1, rect: image.Rect(0, 0, width, height)}
+}
+
+// has reports whether the image at path is packed
+func (a *Atlas) has(path string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, ok := a.regions[path]
+	return ok
+}
+
+// page returns the texture of a page
+func (a *Atlas) page(i int) *ebiten.Image {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.pages[i].img
+}
+
+// region returns where the image at path is packed, loading it if needed
+func (a *Atlas) region(path string) (atlasRegion, error) {
+	a.mu.Lock()
+	r, ok := a.regions[path]
+	a.mu.Unlock()
+	if ok {
+		return r, nil
+	}
+
+	sf, err := a.open(path)
+	if err != nil {
+		return atlasRegion{}, err
+	}
+	defer sf.Close()
+
+	img, _, err := image.Decode(sf)
+	if err != nil {
+		return atlasRegion{}, err
+	}
+	return a.insert(path, img), nil
+}
+
+// insert packs the decoded image file at path, unless another goroutine
+// already did
+func (a *Atlas) insert(path string, img image.Image) atlasRegion {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if r, ok := a.regions[path]; ok {
+		return r
+	}
+
+	// Pixels are written directly into the page, in premultiplied alpha
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	r := a.place(bounds.Dx(), bounds.Dy())
+	a.pages[r.page].img.SubImage(r.rect).(*ebiten.Image).WritePixels(rgba.Pix)
+	a.regions[path] = r
+	return r
+}
+
+// tileRegion returns the page and the area of the page holding the tile image
+func (a *Atlas) tileRegion(tile *tiled.LayerTile) (int, image.Rectangle, error) {
+	path, err := tileImagePath(tile)
+	if err != nil {
+		return 0, image.Rectangle{}, err
+	}
+
+	r, err := a.region(path)
+	if err != nil {
+		return 0, image.Rectangle{}, err
+	}
+
+	if tile.Tileset.Image != nil {
+		return r.page, tile.Tileset.GetTileRect(tile.ID).Add(r.rect.Min), nil
+	}
+	return r.page, r.rect, nil
+}
+
+// triangleBatch accumulates tile quads sharing an atlas page
+type triangleBatch struct {
+	atlas    *Atlas
+	page     int
+	vertices []ebiten.Vertex
+	indices  []uint32
+	options  ebiten.DrawTrianglesOptions
+}
+
+// reset empties the batch, keeping its buffers
+func (b *triangleBatch) reset(atlas *Atlas) {
+	b.atlas = atlas
+	b.page = 0
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+}
+
+// add queues a tile quad. The batch is drawn first if the tile is on
+// another page, so tiles are drawn in order.
+func (b *triangleBatch) add(dst *ebiten.Image, tile *tiled.LayerTile, geom ebiten.GeoM, alpha float32) error {
+	page, src, err := b.atlas.tileRegion(tile)
+	if err != nil {
+		return err
+	}
+	if page != b.page {
+		b.flush(dst)
+		b.page = page
+	}
+
+	w, h := float64(src.Dx()), float64(src.Dy())
+	base := uint32(len(b.vertices))
+	for _, c := range [4][2]float64{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		x, y := geom.Apply(c[0], c[1])
+		b.vertices = append(b.vertices, ebiten.Vertex{
+			DstX:   float32(x),
+			DstY:   float32(y),
+			SrcX:   float32(float64(src.Min.X) + c[0]),
+			SrcY:   float32(float64(src.Min.Y) + c[1]),
+			ColorR: 1,
+			ColorG: 1,
+			ColorB: 1,
+			ColorA: alpha,
+		})
+	}
+	b.indices = append(b.indices, base, base+1, base+2, base+1, base+3, base+2)
+	return nil
+}
+
+// flush draws the queued quads
+func (b *triangleBatch) flush(dst *ebiten.Image) {
+	if len(b.indices) == 0 {
+		return
+	}
+	dst.DrawTriangles32(b.vertices, b.indices, b.atlas.page(b.page), &b.options)
+	b.vertices = b.vertices[:0]
+	b.indices = b.indices[:0]
+}
@@ -0,0 +1,227 @@
+package render
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+)
+
+// bytesPerPixel is the size of a decoded pixel. Images are converted to
+// RGBA before being uploaded to the GPU.
+const bytesPerPixel = 4
+
+// Budget sets limits to the resources a map may require. Zero limits are not
+// checked.
+type Budget struct {
+	// Maximum bytes of GPU memory
+	GPUMemory int64
+	// Maximum bytes of decoded images
+	DecodedBytes int64
+	// Maximum number of draw calls
+	DrawCalls int
+}
+
+// ResourceReport estimates the resources needed to render a map
+type ResourceReport struct {
+	// Bytes of GPU memory used by the tileset images and the render target
+	GPUMemory int64
+	// Bytes of images decoded while loading the tilesets
+	DecodedBytes int64
+	// Number of images and shapes drawn when rendering all visible layers,
+	// image layers, object groups and groups
+	DrawCalls int
+	// Size in pixels of each image used, by path, embedded images being keyed
+	// by address
+	Images map[string]image.Point
+}
+
+// Check returns an error wrapping ErrBudgetExceeded for each limit of the
+// budget exceeded by the report.
+func (rep *ResourceReport) Check(b Budget) []error {
+	var res []error
+
+	if b.GPUMemory > 0 && rep.GPUMemory > b.GPUMemory {
+		res = append(res, fmt.Errorf("%w: %d bytes of GPU memory, budget is %d", ErrBudgetExceeded, rep.GPUMemory, b.GPUMemory))
+	}
+	if b.DecodedBytes > 0 && rep.DecodedBytes > b.DecodedBytes {
+		res = append(res, fmt.Errorf("%w: %d bytes of decoded images, budget is %d", ErrBudgetExceeded, rep.DecodedBytes, b.DecodedBytes))
+	}
+	if b.DrawCalls > 0 && rep.DrawCalls > b.DrawCalls {
+		res = append(res, fmt.Errorf("%w: %d draw calls, budget is %d", ErrBudgetExceeded, rep.DrawCalls, b.DrawCalls))
+	}
+
+	return res
+}
+
+// EstimateResources estimates the resources needed to render the visible
+// layers, image layers, object groups and groups of the map with the current renderer,
+// without decoding any image. Image sizes are read from the map when
+// available, and from the image headers otherwise.
+func (r *Renderer) EstimateResources() (*ResourceReport, error) {
+	rep := &ResourceReport{Images: map[string]image.Point{}}
+
+	for _, layer := range r.m.Layers {
+		if layer.Visible {
+			if err := r.estimateLayer(rep, layer); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, objectGroup := range r.m.ObjectGroups {
+		if objectGroup.Visible {
+			if err := r.estimateObjectGroup(rep, objectGroup); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := r.estimateImageLayers(rep, r.m.ImageLayers); err != nil {
+		return nil, err
+	}
+	for _, group := range r.m.Groups {
+		if !group.Visible {
+			continue
+		}
+		for _, layer := range group.Layers {
+			if layer.Visible {
+				if err := r.estimateLayer(rep, layer); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, objectGroup := range group.ObjectGroups {
+			if objectGroup.Visible {
+				if err := r.estimateObjectGroup(rep, objectGroup); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if err := r.estimateImageLayers(rep, group.ImageLayers); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, size := range rep.Images {
+		rep.DecodedBytes += int64(size.X) * int64(size.Y) * bytesPerPixel
+	}
+	target := r.Result.Bounds().Size()
+	rep.GPUMemory = rep.DecodedBytes + int64(target.X)*int64(target.Y)*bytesPerPixel
+
+	return rep, nil
+}
+
+func (r *Renderer) estimateLayer(rep *ResourceReport, layer *tiled.Layer) error {
+	tiles := 0
+	for _, tile := range layer.Tiles {
+		if tile == nil || tile.IsNil() {
+			continue
+		}
+		if err := r.addTileImages(rep, tile); err != nil {
+			return err
+		}
+		tiles++
+	}
+
+	// With an atlas, a layer is usually drawn in a single call
+	if r.atlas != nil && tiles > 0 {
+		tiles = 1
+	}
+	rep.DrawCalls += tiles
+	return nil
+}
+
+func (r *Renderer) estimateObjectGroup(rep *ResourceReport, objectGroup *tiled.ObjectGroup) error {
+	for _, o := range objectGroup.Objects {
+		if !o.Visible || o.Text != nil {
+			continue
+		}
+
+		if o.GID == 0 {
+			margin := float64(ObjectStrokeWidth)
+			if o.IsPoint() {
+				margin += float64(ObjectPointRadius)
+			}
+			if r.onCanvas(o.BoundingBox(), margin) {
+				rep.DrawCalls++
+			}
+			continue
+		}
+
+		tile, err := r.m.TileGIDToTile(o.GID)
+		if err != nil {
+			return err
+		}
+		if err := r.addTileImages(rep, tile); err != nil {
+			return err
+		}
+		rep.DrawCalls++
+	}
+	return nil
+}
+
+// estimateImageLayers adds the images of the visible image layers to the
+// report, each layer being drawn in a single call
+func (r *Renderer) estimateImageLayers(rep *ResourceReport, layers []*tiled.ImageLayer) error {
+	for _, l := range layers {
+		if !l.Visible || l.Image == nil || l.Image.Source == "" && !l.Image.Embedded() {
+			continue
+		}
+		if err := r.addImage(rep, r.m, l.Image); err != nil {
+			return err
+		}
+		rep.DrawCalls++
+	}
+	return nil
+}
+
+// addTileImages adds the images needed to draw the tile, including the
+// frames of its animation, to the report
+func (r *Renderer) addTileImages(rep *ResourceReport, tile *tiled.LayerTile) error {
+	ts := tile.Tileset
+	if ts.Image != nil {
+		return r.addImage(rep, ts, ts.Image)
+	}
+
+	ids := []uint32{tile.ID}
+	if tilesetTile := ts.Tile(tile.ID); tilesetTile != nil {
+		for _, frame := range tilesetTile.Animation {
+			ids = append(ids, frame.TileID)
+		}
+	}
+
+	for _, id := range ids {
+		tilesetTile := ts.Tile(id)
+		if tilesetTile == nil || tilesetTile.Image == nil {
+			continue
+		}
+		if err := r.addImage(rep, ts, tilesetTile.Image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Renderer) addImage(rep *ResourceReport, ts tiled.FileResolver, img *tiled.Image) error {
+	path := imageKey(ts, img)
+	if _, ok := rep.Images[path]; ok {
+		return nil
+	}
+
+	if img.Width > 0 && img.Height > 0 {
+		rep.Images[path] = image.Pt(img.Width, img.Height)
+		return nil
+	}
+
+	f, err := openImage(r.open, ts, img)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	config, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return err
+	}
+	rep.Images[path] = image.Pt(config.Width, config.Height)
+	return nil
+}
@@ -0,0 +1,30 @@
+package render
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateResourcesImageLayers(t *testing.T) {
+	m, err := tiled.LoadReader(".", strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="4" height="4" tilewidth="16" tileheight="16">
+<imagelayer id="1" name="Sky"><image source="sky.png" width="640" height="360"/></imagelayer>
+<group id="2" name="Foreground">
+<imagelayer id="3" name="Fog"><image source="fog.png" width="64" height="32"/></imagelayer>
+<imagelayer id="4" name="Rain" visible="0"><image source="rain.png" width="64" height="64"/></imagelayer>
+</group>
+</map>`))
+	assert.NoError(t, err)
+	r, err := NewRenderer(m)
+	assert.NoError(t, err)
+
+	rep, err := r.EstimateResources()
+	assert.NoError(t, err)
+	assert.Len(t, rep.Images, 2)
+	assert.Equal(t, int64(640*360+64*32)*bytesPerPixel, rep.DecodedBytes)
+	assert.Equal(t, rep.DecodedBytes+64*64*bytesPerPixel, rep.GPUMemory)
+	assert.Equal(t, 2, rep.DrawCalls)
+}
@@ -0,0 +1,147 @@
+package render
+
+import (
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"io"
+	"math"
+	"time"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// CaptureOptions configures the frames rendered along a camera path
+type CaptureOptions struct {
+	// Size of the frames in pixels
+	Width, Height int
+	// Length of the capture. The camera goes once along the path over this
+	// duration, whatever the speed of the path. Defaults to the time taken to
+	// go once along the path at its speed, following its mode.
+	Duration time.Duration
+	// Frames per second, defaults to 30
+	FPS int
+}
+
+func (o *CaptureOptions) fps() int {
+	if o.FPS <= 0 {
+		return 30
+	}
+	return o.FPS
+}
+
+// frames returns the number of frames captured along path, and the path the
+// camera follows. With a Duration, it is a copy of path with its speed set
+// so the last frame is at its end.
+func (o *CaptureOptions) frames(path *tiled.PathFollower) (int, *tiled.PathFollower, error) {
+	if o.Duration <= 0 {
+		if path.Duration() <= 0 && path.Length() > 0 {
+			return 0, nil, ErrNoCaptureDuration
+		}
+		return max(1, int(path.Duration().Seconds()*float64(o.fps()))), path, nil
+	}
+
+	frames := max(1, int(o.Duration.Seconds()*float64(o.fps())))
+	scaled := *path
+	scaled.Mode = tiled.PathOnce
+	scaled.Speed = 0
+	if frames > 1 {
+		scaled.Speed = path.Length() * float64(o.fps()) / float64(frames-1)
+	}
+	return frames, &scaled, nil
+}
+
+// CaptureCameraPath renders the visible layers, object groups and groups of
+// the map in frames of the given size centered on the positions of path over
+// time, and calls frame with each of them in order. Paths are created from
+// polyline or polygon objects with tiled.NewPathFollower, or from waypoints
+// with tiled.NewPathFollowerFromPoints. Paths without speed need a Duration
+// in options, ErrNoCaptureDuration is returned otherwise. The camera is kept
+// inside the map where it is larger than the frames. The animator set with
+// UseAnimator, if any, is advanced by the frame duration between frames. The
+// frame image is reused, frame must not keep it. Result is left untouched.
+func (r *Renderer) CaptureCameraPath(path *tiled.PathFollower, options CaptureOptions, frame func(i int, img *ebiten.Image) error) error {
+	frames, path, err := options.frames(path)
+	if err != nil {
+		return err
+	}
+
+	result, view := r.Result, r.view
+	defer func() {
+		r.Result, r.view = result, view
+	}()
+
+	width, height := r.engine.GetFinalImageSize()
+	r.Result = ebiten.NewImage(options.Width, options.Height)
+	defer r.Result.Deallocate()
+
+	step := time.Second / time.Duration(options.fps())
+	for i := 0; i < frames; i++ {
+		center, _ := path.At(time.Duration(i) * step)
+		x := cameraOrigin(center.X, options.Width, width)
+		y := cameraOrigin(center.Y, options.Height, height)
+
+		r.view = ebiten.GeoM{}
+		r.view.Translate(-x, -y)
+		r.Result.Clear()
+		if err := r.RenderVisibleLayersAndObjectGroups(); err != nil {
+			return err
+		}
+		if err := r.RenderVisibleGroups(); err != nil {
+			return err
+		}
+		if err := frame(i, r.Result); err != nil {
+			return err
+		}
+
+		if r.animator != nil {
+			r.animator.Update(step)
+		}
+	}
+	return nil
+}
+
+// cameraOrigin returns the left or top edge of a frame of the given size
+// centered on center, kept inside the map where it is larger than the frame.
+func cameraOrigin(center float64, frameSize, mapSize int) float64 {
+	origin := math.Round(center - float64(frameSize)/2)
+	if frameSize >= mapSize {
+		return origin
+	}
+	return min(float64(mapSize-frameSize), max(0, origin))
+}
+
+// SaveCameraPathGif writes the frames captured along the path as an animated
+// GIF, looping forever. Colors are reduced to the Plan 9 palette with
+// dithering.
+func (r *Renderer) SaveCameraPathGif(w io.Writer, path *tiled.PathFollower, options CaptureOptions) error {
+	delay := 100 / options.fps()
+	anim := &gif.GIF{}
+	err := r.CaptureCameraPath(path, options, func(_ int, img *ebiten.Image) error {
+		frame := image.NewPaletted(img.Bounds(), palette.Plan9)
+		draw.FloydSteinberg.Draw(frame, frame.Rect, img, image.Point{})
+		anim.Image = append(anim.Image, frame)
+		anim.Delay = append(anim.Delay, delay)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// WriteCameraPathFrames writes the frames captured along the path as raw
+// RGBA pixels, one frame after the other, to be piped to a video encoder.
+// For example with options of 640x360 at 30 FPS:
+//
+//	ffmpeg -f rawvideo -pix_fmt rgba -s 640x360 -r 30 -i - flyover.webm
+func (r *Renderer) WriteCameraPathFrames(w io.Writer, path *tiled.PathFollower, options CaptureOptions) error {
+	pixels := make([]byte, 4*options.Width*options.Height)
+	return r.CaptureCameraPath(path, options, func(_ int, img *ebiten.Image) error {
+		img.ReadPixels(pixels)
+		_, err := w.Write(pixels)
+		return err
+	})
+}
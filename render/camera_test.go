@@ -0,0 +1,45 @@
+package render
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaptureFrames(t *testing.T) {
+	// Polylines without a speed property
+	path, err := tiled.NewPathFollowerFromPoints([]tiled.Point{{X: 0, Y: 0}, {X: 300, Y: 0}}, 0, tiled.PathLoop)
+	assert.NoError(t, err)
+
+	_, _, err = (&CaptureOptions{}).frames(path)
+	assert.ErrorIs(t, err, ErrNoCaptureDuration)
+
+	options := &CaptureOptions{Duration: 5 * time.Second}
+	frames, followed, err := options.frames(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 150, frames)
+	step := time.Second / 30
+	start, _ := followed.At(0)
+	assert.Equal(t, 0.0, start.X)
+	middle, _ := followed.At(time.Duration(frames-1) / 2 * step)
+	assert.InDelta(t, 150, middle.X, 2)
+	end, _ := followed.At(time.Duration(frames-1) * step)
+	assert.InDelta(t, 300, end.X, 0.01)
+	assert.Equal(t, 0.0, path.Speed)
+
+	// Paths with a speed are followed at it without a duration
+	path.Speed = 100
+	frames, followed, err = (&CaptureOptions{FPS: 10}).frames(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, frames)
+	assert.Same(t, path, followed)
+}
+
+func TestCameraOrigin(t *testing.T) {
+	assert.Equal(t, 0.0, cameraOrigin(10, 100, 400))
+	assert.Equal(t, 150.0, cameraOrigin(200, 100, 400))
+	assert.Equal(t, 300.0, cameraOrigin(390, 100, 400))
+	assert.Equal(t, -50.0, cameraOrigin(50, 200, 100))
+}
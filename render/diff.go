@@ -0,0 +1,128 @@
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+)
+
+var (
+	// DiffChangedColor highlights changed pixels in diff images
+	DiffChangedColor = color.NRGBA{R: 0xff, G: 0x00, B: 0xff, A: 0xff}
+	// DiffTileColor tints the tiles holding changed pixels in diff images
+	DiffTileColor = color.NRGBA{R: 0xff, G: 0x00, B: 0xff, A: 0x40}
+)
+
+// MapDiff is the visual difference between two renders of a map
+type MapDiff struct {
+	// The render after the change faded to gray, changed tiles tinted and
+	// changed pixels highlighted
+	Image *image.NRGBA
+	// Number of pixels that differ
+	ChangedPixels int
+	// Cells holding changed pixels, row by row
+	ChangedTiles []image.Point
+}
+
+// Changed reports whether the renders differ
+func (d *MapDiff) Changed() bool {
+	return d.ChangedPixels > 0
+}
+
+// DiffMaps renders the visible layers, object groups and groups of two
+// versions of a map and compares them, tile sizes being taken from the
+// version after the change.
+func DiffMaps(before, after *tiled.Map) (*MapDiff, error) {
+	a, err := renderAll(before)
+	if err != nil {
+		return nil, err
+	}
+	b, err := renderAll(after)
+	if err != nil {
+		return nil, err
+	}
+	return DiffImages(a, b, after.TileWidth, after.TileHeight), nil
+}
+
+func renderAll(m *tiled.Map) (image.Image, error) {
+	r, err := NewRenderer(m)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.RenderVisibleLayersAndObjectGroups(); err != nil {
+		return nil, err
+	}
+	if err := r.RenderVisibleGroups(); err != nil {
+		return nil, err
+	}
+	return r.Result, nil
+}
+
+// DiffImages compares two images pixel by pixel, grouping changes in cells of
+// tileWidth by tileHeight pixels. Images of different sizes are compared over
+// the union of their bounds, missing pixels being transparent.
+func DiffImages(before, after image.Image, tileWidth, tileHeight int) *MapDiff {
+	ob, nb := before.Bounds(), after.Bounds()
+	width, height := max(ob.Dx(), nb.Dx()), max(ob.Dy(), nb.Dy())
+	at := func(img image.Image, x, y int) color.NRGBA {
+		b := img.Bounds()
+		if x >= b.Dx() || y >= b.Dy() {
+			return color.NRGBA{}
+		}
+		return color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+	}
+
+	d := &MapDiff{Image: image.NewNRGBA(image.Rect(0, 0, width, height))}
+	changedTiles := map[image.Point]bool{}
+	changed := make([]bool, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			a, b := at(before, x, y), at(after, x, y)
+			if a != b {
+				changed[y*width+x] = true
+				d.ChangedPixels++
+				if tileWidth > 0 && tileHeight > 0 {
+					changedTiles[image.Pt(x/tileWidth, y/tileHeight)] = true
+				}
+			}
+
+			// Unchanged content is faded so changes stand out
+			gray := uint8((299*uint32(b.R) + 587*uint32(b.G) + 114*uint32(b.B)) / 1000)
+			gray = 0xc0 + gray/4
+			d.Image.SetNRGBA(x, y, color.NRGBA{R: gray, G: gray, B: gray, A: 0xff})
+		}
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if changed[y*width+x] {
+				d.Image.SetNRGBA(x, y, DiffChangedColor)
+				continue
+			}
+			if tileWidth > 0 && tileHeight > 0 && changedTiles[image.Pt(x/tileWidth, y/tileHeight)] {
+				d.Image.SetNRGBA(x, y, blend(d.Image.NRGBAAt(x, y), DiffTileColor))
+			}
+		}
+	}
+
+	if tileWidth > 0 && tileHeight > 0 {
+		for ty := 0; ty*tileHeight < height; ty++ {
+			for tx := 0; tx*tileWidth < width; tx++ {
+				if changedTiles[image.Pt(tx, ty)] {
+					d.ChangedTiles = append(d.ChangedTiles, image.Pt(tx, ty))
+				}
+			}
+		}
+	}
+
+	return d
+}
+
+// blend draws c over the opaque color dst
+func blend(dst, c color.NRGBA) color.NRGBA {
+	mix := func(d, s uint8) uint8 {
+		return uint8((uint32(d)*(0xff-uint32(c.A)) + uint32(s)*uint32(c.A)) / 0xff)
+	}
+	return color.NRGBA{R: mix(dst.R, c.R), G: mix(dst.G, c.G), B: mix(dst.B, c.B), A: 0xff}
+}
@@ -1,3 +1,5 @@
 // Package render can be used to render parsed map to image.
-// Currently supports only orthogonal rendering out-of-the-box.
+// Currently supports only orthogonal rendering out-of-the-box, other
+// orientations can be supported by registering a RendererEngine with
+// RegisterEngine.
 package render
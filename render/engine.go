@@ -0,0 +1,69 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"io/fs"
+	"sync"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// EngineAPIVersion is the version of the RendererEngine contract. It only
+// changes, along with a major version of the module, when the contract
+// changes in a way breaking engines written against it.
+const EngineAPIVersion = 1
+
+var (
+	enginesMu sync.RWMutex
+	engines   = map[string]func() RendererEngine{
+		"orthogonal": func() RendererEngine { return &OrthogonalRendererEngine{} },
+	}
+)
+
+// RegisterEngine registers the engine rendering maps of the given
+// orientation, so NewRenderer supports them. newEngine is called for each
+// Renderer created. It is meant to be called from init functions of packages
+// providing engines, and panics if the orientation already has an engine or
+// newEngine is nil. Engines can be checked with the rendertest package.
+func RegisterEngine(orientation string, newEngine func() RendererEngine) {
+	enginesMu.Lock()
+	defer enginesMu.Unlock()
+	if newEngine == nil {
+		panic("tiled/render: RegisterEngine engine is nil")
+	}
+	if _, dup := engines[orientation]; dup {
+		panic("tiled/render: RegisterEngine called twice for orientation " + orientation)
+	}
+	engines[orientation] = newEngine
+}
+
+// newEngine returns a new engine for the orientation of m
+func newEngine(m *tiled.Map) (RendererEngine, error) {
+	enginesMu.RLock()
+	newEngine := engines[m.Orientation]
+	enginesMu.RUnlock()
+	if newEngine == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedOrientation, m.Orientation)
+	}
+	return newEngine(), nil
+}
+
+// hasEngine reports whether maps of the orientation can be rendered
+func hasEngine(orientation string) bool {
+	enginesMu.RLock()
+	defer enginesMu.RUnlock()
+	return engines[orientation] != nil
+}
+
+// NewRendererWithEngine creates new rendering engine instance computing tile
+// geometries with the given engine, whatever the orientation of the map.
+// fs may be nil to use the local file system.
+func NewRendererWithEngine(m *tiled.Map, fs fs.FS, engine RendererEngine) *Renderer {
+	r := &Renderer{m: m, tileCache: make(map[uint32]image.Image), fs: fs, engine: engine}
+	r.engine.Init(r.m)
+	width, height := r.engine.GetFinalImageSize()
+	r.Result = ebiten.NewImage(width, height)
+	return r
+}
@@ -0,0 +1,93 @@
+package render
+
+import (
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"io"
+	"math"
+)
+
+const (
+	// Fraction of the darkest and brightest pixels clipped by AutoExposure
+	exposureClip = 0.005
+	// Strongest gamma correction applied by AutoExposure to dark images
+	exposureMinGamma = 0.4
+)
+
+// AutoExposure returns a copy of img with its levels stretched over the
+// whole range of values, and dark images brightened so their mean luminance
+// gets close to mid gray. Transparent pixels are left out of the histogram.
+func AutoExposure(img image.Image) *image.NRGBA {
+	b := img.Bounds()
+	res := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(res, res.Rect, img, b.Min, draw.Src)
+
+	var hist [256]int
+	total := 0
+	for i := 0; i < len(res.Pix); i += 4 {
+		p := res.Pix[i : i+4 : i+4]
+		if p[3] == 0 {
+			continue
+		}
+		hist[luma(p[0], p[1], p[2])]++
+		total++
+	}
+	if total == 0 {
+		return res
+	}
+
+	lo, hi := percentile(&hist, total, exposureClip), percentile(&hist, total, 1-exposureClip)
+	if hi <= lo {
+		return res
+	}
+	stretch := func(v int) float64 {
+		return min(1, max(0, float64(v-lo)/float64(hi-lo)))
+	}
+
+	// Only dark images are brightened, bright ones keep their levels
+	mean := 0.0
+	for v, n := range hist {
+		mean += stretch(v) * float64(n)
+	}
+	mean /= float64(total)
+	gamma := 1.0
+	if mean > 0 && mean < 0.5 {
+		gamma = max(exposureMinGamma, math.Log(0.5)/math.Log(mean))
+	}
+
+	var lut [256]uint8
+	for v := range lut {
+		lut[v] = uint8(math.Round(255 * math.Pow(stretch(v), gamma)))
+	}
+	for i := 0; i < len(res.Pix); i += 4 {
+		p := res.Pix[i : i+3 : i+3]
+		p[0], p[1], p[2] = lut[p[0]], lut[p[1]], lut[p[2]]
+	}
+	return res
+}
+
+// luma returns the perceived brightness of a color
+func luma(r, g, b uint8) uint8 {
+	return uint8((299*uint32(r) + 587*uint32(g) + 114*uint32(b)) / 1000)
+}
+
+// percentile returns the smallest value reached by the fraction p of the
+// total count of the histogram
+func percentile(hist *[256]int, total int, p float64) int {
+	limit := int(math.Ceil(p * float64(total)))
+	count := 0
+	for v, n := range hist {
+		count += n
+		if count >= max(1, limit) {
+			return v
+		}
+	}
+	return len(hist) - 1
+}
+
+// SaveAsJpegAutoExposure writes rendered layers as JPEG image to provided
+// writer, adjusted with AutoExposure so previews of dark maps are legible.
+func (r *Renderer) SaveAsJpegAutoExposure(w io.Writer, options *jpeg.Options) error {
+	return jpeg.Encode(w, AutoExposure(r.Result), options)
+}
@@ -0,0 +1,121 @@
+package render
+
+import (
+	"image"
+	"math"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// RenderVisibleImageLayers renders all visible image layers of the map
+func (r *Renderer) RenderVisibleImageLayers() error {
+	return r.renderImageLayers(r.m.ImageLayers, ebiten.ColorScale{})
+}
+
+// RenderImageLayer renders a single image layer
+func (r *Renderer) RenderImageLayer(i int) error {
+	if i >= len(r.m.ImageLayers) {
+		return ErrOutOfBounds
+	}
+	return r._renderImageLayer(r.m.ImageLayers[i], ebiten.ColorScale{})
+}
+
+// renderImageLayers renders the visible image layers of a map or group,
+// scaling their colors by parent, the color scale of the group
+func (r *Renderer) renderImageLayers(layers []*tiled.ImageLayer, parent ebiten.ColorScale) error {
+	for _, l := range layers {
+		if !l.Visible {
+			continue
+		}
+		if err := r._renderImageLayer(l, parent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// _renderImageLayer draws the image of the layer at its offset, with its
+// opacity and tint color and the ones of its group in parent. Images repeated
+// along an axis are tiled across the whole render target on that axis,
+// wherever the view shows the map.
+func (r *Renderer) _renderImageLayer(l *tiled.ImageLayer, parent ebiten.ColorScale) error {
+	if l.Image == nil || l.Image.Source == "" && !l.Image.Embedded() {
+		return nil
+	}
+	img, err := r.layerImage(l.Image)
+	if err != nil {
+		return err
+	}
+	w, h := float64(img.Bounds().Dx()), float64(img.Bounds().Dy())
+	x0, y0 := float64(l.OffsetX+l.X), float64(l.OffsetY+l.Y)
+
+	// Area of the map covered by the render target
+	inv := r.view
+	inv.Invert()
+	size := r.Result.Bounds().Size()
+	minX, minY := inv.Apply(0, 0)
+	maxX, maxY := inv.Apply(float64(size.X), float64(size.Y))
+
+	xs, xe := x0, x0+w
+	if l.RepeatX && w > 0 {
+		xs = x0 + math.Floor((minX-x0)/w)*w
+		xe = maxX
+	}
+	ys, ye := y0, y0+h
+	if l.RepeatY && h > 0 {
+		ys = y0 + math.Floor((minY-y0)/h)*h
+		ye = maxY
+	}
+
+	op := ebiten.DrawImageOptions{Filter: r.filter}
+	op.ColorScale = layerColorScale(l.Opacity, l.TintColor)
+	op.ColorScale.ScaleWithColorScale(parent)
+	for y := ys; y < ye; y += h {
+		for x := xs; x < xe; x += w {
+			op.GeoM.Reset()
+			op.GeoM.Translate(x, y)
+			op.GeoM.Concat(r.view)
+			r.Result.DrawImage(img, &op)
+		}
+	}
+	return nil
+}
+
+// layerColorScale returns the color scale applying the opacity and tint
+// color of a layer or group, the tint multiplying colors like in Tiled
+func layerColorScale(opacity float32, tint *tiled.HexColor) ebiten.ColorScale {
+	var s ebiten.ColorScale
+	if tint != nil {
+		s.ScaleWithColor(tint)
+	}
+	s.ScaleAlpha(opacity)
+	return s
+}
+
+// layerImage returns the decoded image of an image layer, cached by path
+func (r *Renderer) layerImage(img *tiled.Image) (*ebiten.Image, error) {
+	key := imageKey(r.m, img)
+	if eimg, ok := r.layerImages[key]; ok {
+		return eimg, nil
+	}
+
+	f, err := openImage(r.open, r.m, img)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	decoded, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	r.stats.ImagesDecoded++
+
+	if r.layerImages == nil {
+		r.layerImages = map[string]*ebiten.Image{}
+	}
+	eimg := ebiten.NewImageFromImage(decoded)
+	r.layerImages[key] = eimg
+	return eimg, nil
+}
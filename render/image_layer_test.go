@@ -0,0 +1,25 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLayerColorScale(t *testing.T) {
+	s := layerColorScale(0.5, nil)
+	assert.Equal(t, float32(0.5), s.A())
+	assert.Equal(t, float32(0.5), s.R())
+
+	tint := tiled.NewHexColor(255, 0, 0, 255)
+	s = layerColorScale(1, &tint)
+	assert.Equal(t, float32(1), s.R())
+	assert.Equal(t, float32(0), s.G())
+	assert.Equal(t, float32(1), s.A())
+
+	// Group scales multiply the scale of their layers
+	s.ScaleWithColorScale(layerColorScale(0.5, nil))
+	assert.Equal(t, float32(0.5), s.R())
+	assert.Equal(t, float32(0.5), s.A())
+}
@@ -0,0 +1,35 @@
+package render
+
+import (
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// RenderMinimap renders the visible layers, object groups and groups of the
+// map scaled down by scale into a new image, drawing tiles at their final
+// size with the given filter instead of downscaling a full size render.
+// ebiten.FilterLinear gives smooth results. Result is left untouched.
+func (r *Renderer) RenderMinimap(scale float64, filter ebiten.Filter) (*ebiten.Image, error) {
+	result, view, prevFilter := r.Result, r.view, r.filter
+	defer func() {
+		r.Result, r.view, r.filter = result, view, prevFilter
+	}()
+
+	width, height := r.engine.GetFinalImageSize()
+	r.Result = ebiten.NewImage(
+		max(1, int(math.Ceil(float64(width)*scale))),
+		max(1, int(math.Ceil(float64(height)*scale))),
+	)
+	r.view = ebiten.GeoM{}
+	r.view.Scale(scale, scale)
+	r.filter = filter
+
+	if err := r.RenderVisibleLayersAndObjectGroups(); err != nil {
+		return nil, err
+	}
+	if err := r.RenderVisibleGroups(); err != nil {
+		return nil, err
+	}
+	return r.Result, nil
+}
@@ -0,0 +1,137 @@
+package render
+
+import (
+	"image"
+	"image/png"
+	"io"
+	"io/fs"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// MapPlacement positions a map inside a MultiMapRenderer
+type MapPlacement struct {
+	// Map to render
+	Map *tiled.Map
+	// Offset in pixels of the map top left corner
+	Offset image.Point
+}
+
+// MultiMapRenderer renders several maps into a single image. All maps share
+// the same TilesetCache so tilesets used by more than one map are decoded once.
+type MultiMapRenderer struct {
+	Result       *ebiten.Image // The image result after rendering using the Render functions.
+	renderers    []*Renderer
+	offsets      []image.Point
+	bounds       image.Rectangle
+	tilesetCache *TilesetCache
+}
+
+// NewMultiMapRenderer creates a renderer composing the given map placements
+// in order, later placements being drawn over earlier ones.
+func NewMultiMapRenderer(placements []MapPlacement) (*MultiMapRenderer, error) {
+	return NewMultiMapRendererWithFileSystem(placements, nil)
+}
+
+// NewMultiMapRendererWithFileSystem creates a renderer composing the given map
+// placements with a custom file system.
+func NewMultiMapRendererWithFileSystem(placements []MapPlacement, fs fs.FS) (*MultiMapRenderer, error) {
+	if len(placements) == 0 {
+		return nil, ErrNoMaps
+	}
+
+	mr := &MultiMapRenderer{
+		tilesetCache: NewTilesetCache(fs),
+	}
+
+	for i, p := range placements {
+		r, err := NewRendererWithCache(p.Map, mr.tilesetCache)
+		if err != nil {
+			return nil, err
+		}
+
+		width, height := r.engine.GetFinalImageSize()
+		rect := image.Rect(0, 0, width, height).Add(p.Offset)
+		if i == 0 {
+			mr.bounds = rect
+		} else {
+			mr.bounds = mr.bounds.Union(rect)
+		}
+
+		mr.renderers = append(mr.renderers, r)
+		mr.offsets = append(mr.offsets, p.Offset)
+	}
+
+	mr.Result = ebiten.NewImage(mr.bounds.Dx(), mr.bounds.Dy())
+	return mr, nil
+}
+
+// Bounds returns the area covered by all maps. The top left corner of Result
+// matches Bounds().Min.
+func (mr *MultiMapRenderer) Bounds() image.Rectangle {
+	return mr.bounds
+}
+
+// TilesetCache returns the TilesetCache shared by all maps
+func (mr *MultiMapRenderer) TilesetCache() *TilesetCache {
+	return mr.tilesetCache
+}
+
+// Preload decodes and caches the images of every tileset of all maps up front
+func (mr *MultiMapRenderer) Preload() error {
+	for _, r := range mr.renderers {
+		if err := r.Preload(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (mr *MultiMapRenderer) compose(render func(r *Renderer) error) error {
+	for i, r := range mr.renderers {
+		r.Clear()
+		if err := render(r); err != nil {
+			return err
+		}
+
+		geom := ebiten.GeoM{}
+		geom.Translate(
+			float64(mr.offsets[i].X-mr.bounds.Min.X),
+			float64(mr.offsets[i].Y-mr.bounds.Min.Y),
+		)
+		mr.Result.DrawImage(r.Result, &ebiten.DrawImageOptions{GeoM: geom})
+	}
+	return nil
+}
+
+// RenderVisibleLayers renders all visible layers of every map.
+func (mr *MultiMapRenderer) RenderVisibleLayers() error {
+	return mr.compose((*Renderer).RenderVisibleLayers)
+}
+
+// RenderVisibleObjectGroups renders all visible object groups of every map.
+func (mr *MultiMapRenderer) RenderVisibleObjectGroups() error {
+	return mr.compose((*Renderer).RenderVisibleObjectGroups)
+}
+
+// RenderVisibleLayersAndObjectGroups renders all visible layers and object
+// groups of every map, see Renderer.RenderVisibleLayersAndObjectGroups.
+func (mr *MultiMapRenderer) RenderVisibleLayersAndObjectGroups() error {
+	return mr.compose((*Renderer).RenderVisibleLayersAndObjectGroups)
+}
+
+// RenderVisibleGroups renders all visible groups of every map.
+func (mr *MultiMapRenderer) RenderVisibleGroups() error {
+	return mr.compose((*Renderer).RenderVisibleGroups)
+}
+
+// Clear clears the render result.
+func (mr *MultiMapRenderer) Clear() {
+	mr.Result.Clear()
+}
+
+// SaveAsPng writes the composed maps as PNG image to provided writer.
+func (mr *MultiMapRenderer) SaveAsPng(w io.Writer) error {
+	return png.Encode(w, mr.Result)
+}
@@ -0,0 +1,186 @@
+package render
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// oraThumbnailSize is the maximum width and height of OpenRaster thumbnails
+const oraThumbnailSize = 256
+
+// oraStack is a stack of the OpenRaster stack.xml file
+type oraStack struct {
+	XMLName xml.Name `xml:"stack"`
+	Name    string   `xml:"name,attr,omitempty"`
+	Items   []any
+}
+
+// oraLayer is a layer of the OpenRaster stack.xml file
+type oraLayer struct {
+	XMLName    xml.Name `xml:"layer"`
+	Name       string   `xml:"name,attr"`
+	Source     string   `xml:"src,attr"`
+	X          int      `xml:"x,attr"`
+	Y          int      `xml:"y,attr"`
+	Opacity    string   `xml:"opacity,attr"`
+	Visibility string   `xml:"visibility,attr"`
+}
+
+// oraImage is the root of the OpenRaster stack.xml file
+type oraImage struct {
+	XMLName xml.Name  `xml:"image"`
+	Version string    `xml:"version,attr"`
+	Width   int       `xml:"w,attr"`
+	Height  int       `xml:"h,attr"`
+	Stack   *oraStack `xml:"stack"`
+}
+
+// oraWriter renders the layers of a map into an OpenRaster archive
+type oraWriter struct {
+	r      *Renderer
+	z      *zip.Writer
+	merged *ebiten.Image
+	count  int
+}
+
+// SaveAsORA writes the visible image layers, tile layers, object groups and
+// groups of the map as an OpenRaster image to provided writer, one raster
+// layer per layer and a nested stack per group, so they can be edited
+// separately in painting programs such as Krita. Layer opacity is applied to
+// the pixels, and the opacity and tint of groups to their image layers.
+// Result is left untouched.
+func (r *Renderer) SaveAsORA(w io.Writer) error {
+	result := r.Result
+	defer func() {
+		r.Result = result
+	}()
+
+	width, height := r.engine.GetFinalImageSize()
+	ow := &oraWriter{r: r, z: zip.NewWriter(w), merged: ebiten.NewImage(width, height)}
+
+	// The mimetype must come first, uncompressed
+	f, err := ow.z.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, "image/openraster"); err != nil {
+		return err
+	}
+
+	stack, err := ow.stack("", r.m.ImageLayers, r.m.Layers, r.m.ObjectGroups, r.m.Groups, ebiten.ColorScale{})
+	if err != nil {
+		return err
+	}
+
+	f, err = ow.z.Create("stack.xml")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(f, xml.Header); err != nil {
+		return err
+	}
+	doc := oraImage{Version: "0.0.5", Width: width, Height: height, Stack: stack}
+	if err := xml.NewEncoder(f).Encode(doc); err != nil {
+		return err
+	}
+
+	if err := ow.png("mergedimage.png", ow.merged); err != nil {
+		return err
+	}
+
+	scale := min(1, float64(oraThumbnailSize)/float64(max(width, height)))
+	thumbnail := ebiten.NewImage(max(1, int(float64(width)*scale)), max(1, int(float64(height)*scale)))
+	op := &ebiten.DrawImageOptions{Filter: ebiten.FilterLinear}
+	op.GeoM.Scale(scale, scale)
+	thumbnail.DrawImage(ow.merged, op)
+	if err := ow.png("Thumbnails/thumbnail.png", thumbnail); err != nil {
+		return err
+	}
+
+	return ow.z.Close()
+}
+
+func (ow *oraWriter) png(name string, img image.Image) error {
+	f, err := ow.z.Create(name)
+	if err != nil {
+		return err
+	}
+	return png.Encode(f, img)
+}
+
+// layer renders a layer into an image of its own, added to the archive
+func (ow *oraWriter) layer(name string, render func() error) (*oraLayer, error) {
+	width, height := ow.r.engine.GetFinalImageSize()
+	ow.r.Result = ebiten.NewImage(width, height)
+	if err := render(); err != nil {
+		return nil, err
+	}
+	ow.merged.DrawImage(ow.r.Result, nil)
+
+	src := fmt.Sprintf("data/layer%d.png", ow.count)
+	ow.count++
+	if err := ow.png(src, ow.r.Result); err != nil {
+		return nil, err
+	}
+
+	return &oraLayer{Name: name, Source: src, Opacity: "1.0", Visibility: "visible"}, nil
+}
+
+// stack adds the visible layers to the archive, image layers first as they
+// are usually backgrounds, their colors scaled by the ones of the groups in
+// parent. OpenRaster stacks list the top layer first.
+func (ow *oraWriter) stack(name string, imageLayers []*tiled.ImageLayer, layers []*tiled.Layer, objectGroups []*tiled.ObjectGroup, groups []*tiled.Group, parent ebiten.ColorScale) (*oraStack, error) {
+	s := &oraStack{Name: name}
+
+	for _, l := range imageLayers {
+		if !l.Visible {
+			continue
+		}
+		item, err := ow.layer(l.Name, func() error { return ow.r._renderImageLayer(l, parent) })
+		if err != nil {
+			return nil, err
+		}
+		s.Items = append([]any{item}, s.Items...)
+	}
+	for _, l := range layers {
+		if !l.Visible {
+			continue
+		}
+		item, err := ow.layer(l.Name, func() error { return ow.r._renderLayer(l) })
+		if err != nil {
+			return nil, err
+		}
+		s.Items = append([]any{item}, s.Items...)
+	}
+	for _, g := range objectGroups {
+		if !g.Visible {
+			continue
+		}
+		item, err := ow.layer(g.Name, func() error { return ow.r._renderObjectGroup(g) })
+		if err != nil {
+			return nil, err
+		}
+		s.Items = append([]any{item}, s.Items...)
+	}
+	for _, g := range groups {
+		if !g.Visible {
+			continue
+		}
+		scale := layerColorScale(g.Opacity, g.TintColor)
+		scale.ScaleWithColorScale(parent)
+		item, err := ow.stack(g.Name, g.ImageLayers, g.Layers, g.ObjectGroups, g.Groups, scale)
+		if err != nil {
+			return nil, err
+		}
+		s.Items = append([]any{item}, s.Items...)
+	}
+
+	return s, nil
+}
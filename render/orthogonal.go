@@ -23,8 +23,6 @@ SOFTWARE.
 package render
 
 import (
-	"math"
-
 	tiled "github.com/Tsukumogami-Software/go-tiled"
 	"github.com/hajimehoshi/ebiten/v2"
 )
@@ -46,23 +44,33 @@ func (e *OrthogonalRendererEngine) GetFinalImageSize() (int, int) {
 
 // GetTileGeometry returns the geometry object used to render the tile at the correct position and orientation
 func (e *OrthogonalRendererEngine) GetTileGeometry(x, y int, tile *tiled.LayerTile) ebiten.GeoM {
-	res := ebiten.GeoM{}
+	res := tileFlipGeoM(tile, float64(tile.Tileset.TileWidth), float64(tile.Tileset.TileHeight))
 	res.Translate(
 		float64(x*e.m.TileWidth),
 		float64(y*e.m.TileHeight),
 	)
-	//res.Scale(
-	//	float64((x+1)*e.m.TileWidth),
-	//	float64((y+1)*e.m.TileHeight),
-	//)
+	return res
+}
 
+// tileFlipGeoM returns the geometry flipping a tile image of the given size
+// in place, like Tiled does: the diagonal flip swaps the x and y axis, and is
+// applied before the horizontal and vertical flips.
+func tileFlipGeoM(tile *tiled.LayerTile, width, height float64) ebiten.GeoM {
+	res := ebiten.GeoM{}
 	if tile.DiagonalFlip {
-		res.Rotate(-math.Pi / 2)
-		res.Scale(-1, 1)
-	} else if tile.HorizontalFlip {
+		res.SetElement(0, 0, 0)
+		res.SetElement(0, 1, 1)
+		res.SetElement(1, 0, 1)
+		res.SetElement(1, 1, 0)
+		width, height = height, width
+	}
+	if tile.HorizontalFlip {
 		res.Scale(-1, 1)
-	} else if tile.VerticalFlip {
+		res.Translate(width, 0)
+	}
+	if tile.VerticalFlip {
 		res.Scale(1, -1)
+		res.Translate(0, height)
 	}
 	return res
 }
@@ -0,0 +1,82 @@
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+)
+
+// RenderObjectOutline draws an outline of thickness pixels around the opaque
+// pixels of the tile object with the given ID, found in the object groups of
+// the map and its groups, for example to highlight a selection. Objects that
+// are not tile objects can't be outlined and return ErrUnsupportedFeature.
+func (r *Renderer) RenderObjectOutline(objectID uint32, c color.Color, thickness int) error {
+	o := r.m.ObjectByID(objectID)
+	if o == nil {
+		return fmt.Errorf("%w: %d", ErrObjectNotFound, objectID)
+	}
+	if o.GID == 0 {
+		return fmt.Errorf("%w: object %d is not a tile object and can't be outlined", ErrUnsupportedFeature, objectID)
+	}
+	if thickness <= 0 {
+		return nil
+	}
+
+	tile, err := r.m.TileGIDToTile(o.GID)
+	if err != nil {
+		return err
+	}
+	if r.animator != nil {
+		tile = r.animator.Frame(tile)
+	}
+	img, err := r.getTileImage(tile)
+	if err != nil {
+		return err
+	}
+	eimg := img.(*ebiten.Image)
+
+	geom, bounds := tileObjectGeoM(o, tile, img)
+	if !r.onCanvas(bounds, float64(thickness)) {
+		return nil
+	}
+
+	// The outline is drawn on an image covering the object and its outline,
+	// then copied to the result
+	geom.Concat(r.view)
+	minX, minY := r.view.Apply(bounds.Min.X, bounds.Min.Y)
+	maxX, maxY := r.view.Apply(bounds.Max.X, bounds.Max.Y)
+	left := int(math.Floor(minX)) - thickness - 1
+	top := int(math.Floor(minY)) - thickness - 1
+	width := int(math.Ceil(maxX)) + thickness + 1 - left
+	height := int(math.Ceil(maxY)) + thickness + 1 - top
+	geom.Translate(float64(-left), float64(-top))
+
+	outline := ebiten.NewImage(width, height)
+	defer outline.Deallocate()
+
+	// The silhouette is drawn in the outline color at every offset within
+	// the thickness, then cut out by the tile itself
+	nc := color.NRGBAModel.Convert(c).(color.NRGBA)
+	var cm colorm.ColorM
+	cm.Scale(0, 0, 0, float64(nc.A)/0xff)
+	cm.Translate(float64(nc.R)/0xff, float64(nc.G)/0xff, float64(nc.B)/0xff, 0)
+	for dy := -thickness; dy <= thickness; dy++ {
+		for dx := -thickness; dx <= thickness; dx++ {
+			if dx*dx+dy*dy > thickness*thickness {
+				continue
+			}
+			g := geom
+			g.Translate(float64(dx), float64(dy))
+			colorm.DrawImage(outline, eimg, cm, &colorm.DrawImageOptions{GeoM: g, Filter: r.filter})
+		}
+	}
+	outline.DrawImage(eimg, &ebiten.DrawImageOptions{GeoM: geom, Filter: r.filter, Blend: ebiten.BlendDestinationOut})
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(left), float64(top))
+	r.Result.DrawImage(outline, op)
+	return nil
+}
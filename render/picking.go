@@ -0,0 +1,31 @@
+package render
+
+import (
+	"github.com/Tsukumogami-Software/go-tiled"
+)
+
+// PickTile returns the top most tile of the layer visible at x, y in map
+// pixels, with its cell coordinates. Transparent pixels of tile images are
+// not hit, so clicks land on the tile seen under the cursor.
+func (r *Renderer) PickTile(layer *tiled.Layer, x, y float64) (*tiled.LayerTile, int, int, bool) {
+	return r.m.PickTile(layer, x, y, r.tileOpaque)
+}
+
+// tileOpaque reports whether the pixel of the tile image is opaque, tiles
+// whose image fails to load are never hit
+func (r *Renderer) tileOpaque(tile *tiled.LayerTile, x, y int) bool {
+	img, err := r.getTileImage(tile)
+	if err != nil {
+		return false
+	}
+	origin := img.Bounds().Min
+	_, _, _, a := img.At(origin.X+x, origin.Y+y).RGBA()
+	return a != 0
+}
+
+// ObjectAtPixelPrecise returns the top most visible object of the group at x,
+// y in map pixels, or nil. Tile objects are only hit on opaque pixels of
+// their tile image.
+func (r *Renderer) ObjectAtPixelPrecise(group *tiled.ObjectGroup, x, y float64) (*tiled.Object, error) {
+	return r.m.ObjectAtPixelPrecise(group, x, y, r.tileOpaque)
+}
@@ -0,0 +1,140 @@
+package render
+
+import (
+	"image"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// prefetch is an image file decoded in the background by a TilesetCache
+type prefetch struct {
+	path string
+	// Tiles using the image
+	tiles []*tiled.LayerTile
+	// Closed once img or err is set
+	done chan struct{}
+	img  image.Image
+	err  error
+}
+
+// Prefetch starts decoding the images of the tilesets of the map that are
+// not cached yet, in the background with up to one goroutine per CPU. Only
+// decoding happens in the background: images are uploaded to the GPU by
+// Upload, a few at a time from the game loop, so loading a level does not
+// stall frames. Tiles rendered before their image is uploaded wait for it to
+// be decoded instead of decoding it again.
+func (t *TilesetCache) Prefetch(m *tiled.Map) error {
+	jobs, err := decodeJobs(m)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.prefetches == nil {
+		t.prefetches = map[string]*prefetch{}
+		t.prefetchTokens = make(chan struct{}, runtime.NumCPU())
+	}
+
+	for _, job := range jobs {
+		if p, ok := t.prefetches[job.path]; ok {
+			p.tiles = append(p.tiles, job.tile)
+			continue
+		}
+		if elem, ok := t.entries[t.key(job.tile.Tileset)]; ok {
+			if _, ok := elem.Value.(*tilesetCacheEntry).tiles[job.tile.ID]; ok {
+				continue
+			}
+		}
+
+		p := &prefetch{path: job.path, tiles: []*tiled.LayerTile{job.tile}, done: make(chan struct{})}
+		t.prefetches[job.path] = p
+		t.prefetchQueue = append(t.prefetchQueue, p)
+		go func() {
+			t.prefetchTokens <- struct{}{}
+			defer func() { <-t.prefetchTokens }()
+			p.img, p.err = t.decodeImage(job.tile)
+			close(p.done)
+		}()
+	}
+	return nil
+}
+
+// Upload uploads the images decoded by Prefetch to the GPU and caches them,
+// in the order they were prefetched, until budget is spent. At least one
+// decoded image is uploaded per call, as a single image can't be split. It
+// is meant to be called every frame from the game loop with a budget of a
+// few milliseconds, and returns the number of prefetched images left, being
+// decoded or waiting for Upload. The first decoding error is returned, the
+// image being dropped.
+func (t *TilesetCache) Upload(budget time.Duration) (int, error) {
+	start := time.Now()
+	for {
+		t.mu.Lock()
+		i := slices.IndexFunc(t.prefetchQueue, func(p *prefetch) bool {
+			select {
+			case <-p.done:
+				return true
+			default:
+				return false
+			}
+		})
+		if i < 0 {
+			left := len(t.prefetchQueue)
+			t.mu.Unlock()
+			return left, nil
+		}
+		p := t.prefetchQueue[i]
+		t.removePrefetch(p)
+		t.mu.Unlock()
+
+		if p.err != nil {
+			return t.PendingUploads(), p.err
+		}
+		eimg := ebiten.NewImageFromImage(p.img)
+		for _, tile := range p.tiles {
+			t.store(tile, eimg)
+		}
+
+		if time.Since(start) >= budget {
+			return t.PendingUploads(), nil
+		}
+	}
+}
+
+// PendingUploads returns the number of images prefetched but not uploaded
+// yet, being decoded or waiting for Upload
+func (t *TilesetCache) PendingUploads() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.prefetchQueue)
+}
+
+// takePrefetch removes and returns the prefetch of the image of the tile, or
+// nil if it is not being prefetched. The prefetch may still be decoding.
+func (t *TilesetCache) takePrefetch(tile *tiled.LayerTile) *prefetch {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.prefetches) == 0 {
+		return nil
+	}
+	path, err := tileImagePath(tile)
+	if err != nil {
+		return nil
+	}
+	p := t.prefetches[path]
+	if p != nil {
+		t.removePrefetch(p)
+	}
+	return p
+}
+
+// removePrefetch forgets a prefetch. The lock must be held.
+func (t *TilesetCache) removePrefetch(p *prefetch) {
+	delete(t.prefetches, p.path)
+	t.prefetchQueue = slices.DeleteFunc(t.prefetchQueue, func(q *prefetch) bool { return q == p })
+}
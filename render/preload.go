@@ -0,0 +1,132 @@
+package render
+
+import (
+	"image"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// decodeJob is an image file to decode for a tile
+type decodeJob struct {
+	tile *tiled.LayerTile
+	path string
+	img  image.Image
+	err  error
+}
+
+// Preload decodes and caches the images of every tileset of the map up front,
+// so they are not loaded lazily in the middle of the first render. Tileset
+// images are also packed in the Atlas, if any. Images are decoded
+// concurrently by up to PreloadWorkers goroutines.
+func (r *Renderer) Preload() error {
+	jobs, err := decodeJobs(r.m)
+	if err != nil {
+		return err
+	}
+
+	jobs = slices.DeleteFunc(jobs, r.preloaded)
+	r.decodeAll(jobs)
+
+	// Caches are filled from this goroutine only
+	for _, job := range jobs {
+		if job.err != nil {
+			return job.err
+		}
+		if r.atlas != nil && !r.atlas.has(job.path) {
+			r.atlas.insert(job.path, job.img)
+		}
+		if r.tileCached(job.tile) {
+			continue
+		}
+
+		eimg := ebiten.NewImageFromImage(job.img)
+		if r.tilesetCache != nil {
+			r.tilesetCache.store(job.tile, eimg)
+		} else {
+			r.storeTileImage(job.tile, eimg)
+		}
+	}
+	return nil
+}
+
+// decodeJobs returns a job for each image of the tilesets of the map,
+// loading external tilesets
+func decodeJobs(m *tiled.Map) ([]*decodeJob, error) {
+	var jobs []*decodeJob
+	for _, ts := range m.Tilesets {
+		// Loads external tilesets
+		first, err := m.TileGIDToTile(ts.FirstGID)
+		if err != nil {
+			return nil, err
+		}
+
+		var tiles []*tiled.LayerTile
+		if ts.Image != nil {
+			tiles = append(tiles, first)
+		} else {
+			for _, t := range ts.Tiles {
+				if t != nil && t.Image != nil {
+					tiles = append(tiles, &tiled.LayerTile{ID: t.ID, Tileset: ts})
+				}
+			}
+		}
+
+		for _, tile := range tiles {
+			path, err := tileImagePath(tile)
+			if err != nil {
+				return nil, err
+			}
+			jobs = append(jobs, &decodeJob{tile: tile, path: path})
+		}
+	}
+	return jobs, nil
+}
+
+func (r *Renderer) tileCached(tile *tiled.LayerTile) bool {
+	if r.tilesetCache != nil {
+		return r.tilesetCache.has(tile)
+	}
+	_, ok := r.tileCache[tile.Tileset.FirstGID+tile.ID]
+	return ok
+}
+
+// preloaded reports whether the image of the job is already cached
+func (r *Renderer) preloaded(job *decodeJob) bool {
+	return r.tileCached(job.tile) && (r.atlas == nil || r.atlas.has(job.path))
+}
+
+// decodeAll decodes the images of the jobs with a bounded number of goroutines
+func (r *Renderer) decodeAll(jobs []*decodeJob) {
+	workers := r.PreloadWorkers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	// Files shared by several tiles are decoded once
+	byPath := map[string][]*decodeJob{}
+	for _, job := range jobs {
+		byPath[job.path] = append(byPath[job.path], job)
+	}
+
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for _, same := range byPath {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			img, err := r.decodeImage(same[0].tile)
+			for _, job := range same {
+				job.img, job.err = img, err
+			}
+		}()
+	}
+	wg.Wait()
+
+	r.stats.ImagesDecoded += len(byPath)
+}
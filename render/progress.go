@@ -0,0 +1,65 @@
+package render
+
+import (
+	"context"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+)
+
+// ProgressFunc is called while rendering with the number of tiles done out of
+// the total number of tiles to render, empty ones included.
+type ProgressFunc func(done, total int)
+
+// renderProgress tracks the progress of a render. A nil renderProgress
+// tracks nothing and is never cancelled.
+type renderProgress struct {
+	ctx         context.Context
+	fn          ProgressFunc
+	done, total int
+}
+
+func (p *renderProgress) err() error {
+	if p == nil {
+		return nil
+	}
+	return p.ctx.Err()
+}
+
+func (p *renderProgress) add(tiles int) {
+	if p == nil {
+		return
+	}
+	p.done += tiles
+	if p.fn != nil {
+		p.fn(p.done, p.total)
+	}
+}
+
+// RenderLayersContext renders the visible layers of the given set, in order,
+// calling progress, if not nil, after each row of tiles. It stops and returns
+// the context error once ctx is done, leaving the render partial.
+func (r *Renderer) RenderLayersContext(ctx context.Context, layers tiled.Layers, progress ProgressFunc) error {
+	layers = layers.Visible()
+
+	p := &renderProgress{ctx: ctx, fn: progress}
+	for _, layer := range layers {
+		p.total += len(layer.Tiles)
+	}
+
+	for _, layer := range layers {
+		if err := p.err(); err != nil {
+			return err
+		}
+		if err := r._renderLayerProgress(layer, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderVisibleLayersContext renders all visible map layers like
+// RenderVisibleLayers, with progress reporting and cancellation like
+// RenderLayersContext.
+func (r *Renderer) RenderVisibleLayersContext(ctx context.Context, progress ProgressFunc) error {
+	return r.RenderLayersContext(ctx, r.m.Layers, progress)
+}
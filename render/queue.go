@@ -0,0 +1,226 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"image"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// ErrSuperseded is given to requests of a RenderQueue replaced by a later
+// request with the same key, or dropped when the queue is closed
+var ErrSuperseded = errors.New("tiled/render: render request superseded")
+
+// RegionRequest asks a RenderQueue for the visible layers, object groups and
+// groups of a region of the map
+type RegionRequest struct {
+	// Region of the map rendered, in map pixels
+	Rect image.Rectangle
+	// Requests of higher priority are rendered first, requests of the same
+	// priority in order
+	Priority int
+	// A request supersedes the pending or running request with the same
+	// key, such as "camera" for the view of an editor being panned. Requests
+	// without key are never superseded.
+	Key string
+	// Called from the queue goroutine with the region rendered, of the size
+	// of Rect, or with the error that stopped it, ErrSuperseded for
+	// superseded requests
+	Done func(img *ebiten.Image, err error)
+}
+
+type queuedRequest struct {
+	RegionRequest
+	seq uint64
+	// Set once the request is running
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// RenderQueue renders region requests one at a time in a goroutine, by
+// priority, so an application stays responsive while requests pile up. The
+// running request is stopped between layers, rows of tiles and objects when
+// superseded.
+type RenderQueue struct {
+	r *Renderer
+
+	mu      sync.Mutex
+	wake    chan struct{}
+	pending []*queuedRequest
+	running *queuedRequest
+	seq     uint64
+	closed  bool
+	stopped chan struct{}
+}
+
+// NewRenderQueue creates a RenderQueue rendering with r, which must not be
+// used otherwise until the queue is closed.
+func NewRenderQueue(r *Renderer) *RenderQueue {
+	q := &RenderQueue{
+		r:       r,
+		wake:    make(chan struct{}, 1),
+		stopped: make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Submit queues a request, superseding the request with the same key if any
+func (q *RenderQueue) Submit(req RegionRequest) {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		req.done(nil, ErrSuperseded)
+		return
+	}
+
+	var superseded []*queuedRequest
+	if req.Key != "" {
+		pending := q.pending[:0]
+		for _, p := range q.pending {
+			if p.Key == req.Key {
+				superseded = append(superseded, p)
+			} else {
+				pending = append(pending, p)
+			}
+		}
+		clear(q.pending[len(pending):])
+		q.pending = pending
+		if q.running != nil && q.running.Key == req.Key {
+			q.running.cancel()
+		}
+	}
+
+	q.seq++
+	q.pending = append(q.pending, &queuedRequest{RegionRequest: req, seq: q.seq})
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	q.mu.Unlock()
+
+	for _, p := range superseded {
+		p.done(nil, ErrSuperseded)
+	}
+}
+
+// Pending returns the number of requests waiting to be rendered
+func (q *RenderQueue) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Close stops the queue once the running request is done, giving
+// ErrSuperseded to pending ones, and waits for it to stop.
+func (q *RenderQueue) Close() {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		<-q.stopped
+		return
+	}
+	q.closed = true
+	pending := q.pending
+	q.pending = nil
+	close(q.wake)
+	q.mu.Unlock()
+
+	for _, p := range pending {
+		p.done(nil, ErrSuperseded)
+	}
+	<-q.stopped
+}
+
+// next removes the request to render next from the queue, or returns nil
+// when none is pending
+func (q *RenderQueue) next() *queuedRequest {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return nil
+	}
+	best := 0
+	for i, p := range q.pending {
+		b := q.pending[best]
+		if p.Priority > b.Priority || p.Priority == b.Priority && p.seq < b.seq {
+			best = i
+		}
+	}
+	req := q.pending[best]
+	q.pending = append(q.pending[:best], q.pending[best+1:]...)
+
+	req.ctx, req.cancel = context.WithCancel(context.Background())
+	q.running = req
+	return req
+}
+
+func (q *RenderQueue) run() {
+	defer close(q.stopped)
+	for range q.wake {
+		for req := q.next(); req != nil; req = q.next() {
+			q.render(req)
+		}
+	}
+}
+
+func (q *RenderQueue) render(req *queuedRequest) {
+	img, err := q.r.renderRegion(req.ctx, req.Rect)
+	if req.ctx.Err() != nil {
+		img, err = nil, ErrSuperseded
+	}
+
+	q.mu.Lock()
+	q.running = nil
+	q.mu.Unlock()
+	req.cancel()
+	req.done(img, err)
+}
+
+func (req *RegionRequest) done(img *ebiten.Image, err error) {
+	if req.Done != nil {
+		req.Done(img, err)
+	}
+}
+
+// renderRegion renders the visible layers, object groups and groups of a
+// region of the map into a new image, stopping between layers, rows of tiles
+// and objects once ctx is done. Result is left untouched.
+func (r *Renderer) renderRegion(ctx context.Context, rect image.Rectangle) (*ebiten.Image, error) {
+	result, view := r.Result, r.view
+	defer func() {
+		r.Result, r.view = result, view
+	}()
+
+	r.Result = ebiten.NewImage(max(1, rect.Dx()), max(1, rect.Dy()))
+	r.view = ebiten.GeoM{}
+	r.view.Translate(-float64(rect.Min.X), -float64(rect.Min.Y))
+
+	if err := r.RenderVisibleLayersContext(ctx, nil); err != nil {
+		return nil, err
+	}
+
+	p := &renderProgress{ctx: ctx}
+	for _, objectGroup := range r.m.ObjectGroups {
+		if !objectGroup.Visible {
+			continue
+		}
+		if err := r._renderObjectGroupProgress(objectGroup, p); err != nil {
+			return nil, err
+		}
+	}
+	for _, group := range r.m.Groups {
+		if !group.Visible {
+			continue
+		}
+		if err := p.err(); err != nil {
+			return nil, err
+		}
+		if err := r._renderGroupProgress(group, p); err != nil {
+			return nil, err
+		}
+	}
+	return r.Result, nil
+}
@@ -0,0 +1,136 @@
+package render
+
+import (
+	"context"
+	"image"
+	"path/filepath"
+	"testing"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestQueue(t *testing.T) *RenderQueue {
+	m, err := tiled.LoadFile(filepath.Join("..", "assets", "test2.tmx"))
+	assert.NoError(t, err)
+	r, err := NewRenderer(m)
+	assert.NoError(t, err)
+	return NewRenderQueue(r)
+}
+
+// blockQueue submits a request holding the queue goroutine in its callback
+// until the returned function is called
+func blockQueue(q *RenderQueue) func() {
+	started, release := make(chan struct{}), make(chan struct{})
+	q.Submit(RegionRequest{
+		Rect: image.Rect(0, 0, 32, 32),
+		Done: func(*ebiten.Image, error) {
+			close(started)
+			<-release
+		},
+	})
+	<-started
+	return func() { close(release) }
+}
+
+func TestRenderQueuePriority(t *testing.T) {
+	q := newTestQueue(t)
+	defer q.Close()
+
+	release := blockQueue(q)
+	order := make(chan string, 3)
+	submit := func(name string, priority int) {
+		q.Submit(RegionRequest{
+			Rect:     image.Rect(0, 0, 32, 32),
+			Priority: priority,
+			Done: func(img *ebiten.Image, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, image.Pt(32, 32), img.Bounds().Size())
+				order <- name
+			},
+		})
+	}
+	submit("low", 0)
+	submit("high", 2)
+	submit("middle", 1)
+	submit("low again", 0)
+	assert.Equal(t, 4, q.Pending())
+	release()
+
+	for _, name := range []string{"high", "middle", "low", "low again"} {
+		assert.Equal(t, name, <-order)
+	}
+}
+
+func TestRenderQueueSupersede(t *testing.T) {
+	q := newTestQueue(t)
+	defer q.Close()
+
+	release := blockQueue(q)
+	errs := make(chan error, 3)
+	for range 3 {
+		q.Submit(RegionRequest{
+			Rect: image.Rect(0, 0, 32, 32),
+			Key:  "camera",
+			Done: func(_ *ebiten.Image, err error) { errs <- err },
+		})
+	}
+	// Pending requests are superseded as soon as the next one is submitted
+	assert.ErrorIs(t, <-errs, ErrSuperseded)
+	assert.ErrorIs(t, <-errs, ErrSuperseded)
+	assert.Equal(t, 1, q.Pending())
+	release()
+	assert.NoError(t, <-errs)
+
+	// The running request is stopped once superseded
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var got error
+	q.render(&queuedRequest{
+		RegionRequest: RegionRequest{
+			Rect: image.Rect(0, 0, 32, 32),
+			Done: func(_ *ebiten.Image, err error) { got = err },
+		},
+		ctx:    ctx,
+		cancel: cancel,
+	})
+	assert.ErrorIs(t, got, ErrSuperseded)
+
+	_, err := q.r.renderRegion(ctx, image.Rect(0, 0, 32, 32))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRenderQueueClose(t *testing.T) {
+	q := newTestQueue(t)
+
+	release := blockQueue(q)
+	errs := make(chan error, 2)
+	for range 2 {
+		q.Submit(RegionRequest{
+			Rect: image.Rect(0, 0, 32, 32),
+			Done: func(_ *ebiten.Image, err error) { errs <- err },
+		})
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		q.Close()
+		close(closed)
+	}()
+	// Pending requests are dropped without waiting for the running one
+	assert.ErrorIs(t, <-errs, ErrSuperseded)
+	assert.ErrorIs(t, <-errs, ErrSuperseded)
+	select {
+	case <-closed:
+		t.Fatal("Close returned before the running request was done")
+	default:
+	}
+	release()
+	<-closed
+	assert.Equal(t, 0, q.Pending())
+
+	q.Submit(RegionRequest{Done: func(_ *ebiten.Image, err error) { errs <- err }})
+	assert.ErrorIs(t, <-errs, ErrSuperseded)
+	q.Close()
+}
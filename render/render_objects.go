@@ -25,6 +25,7 @@ package render
 import (
 	"image"
 	"math"
+	"slices"
 
 	"github.com/Tsukumogami-Software/go-tiled"
 	"github.com/Tsukumogami-Software/go-tiled/internal"
@@ -55,11 +56,23 @@ func (r *Renderer) RenderGroup(groupID int) error {
 }
 
 func (r *Renderer) _renderGroup(group *tiled.Group) error {
+	return r._renderGroupProgress(group, nil)
+}
+
+func (r *Renderer) _renderGroupProgress(group *tiled.Group, progress *renderProgress) error {
+	// Image layers are usually backgrounds
+	if err := r.renderImageLayers(group.ImageLayers, layerColorScale(group.Opacity, group.TintColor)); err != nil {
+		return err
+	}
+
 	for _, layer := range group.Layers {
 		if !layer.Visible {
 			continue
 		}
-		if err := r._renderLayer(layer); err != nil {
+		if err := progress.err(); err != nil {
+			return err
+		}
+		if err := r._renderLayerProgress(layer, progress); err != nil {
 			return err
 		}
 	}
@@ -68,7 +81,7 @@ func (r *Renderer) _renderGroup(group *tiled.Group) error {
 		if !objectGroup.Visible {
 			continue
 		}
-		if err := r._renderObjectGroup(objectGroup); err != nil {
+		if err := r._renderObjectGroupProgress(objectGroup, progress); err != nil {
 			return err
 		}
 	}
@@ -76,12 +89,15 @@ func (r *Renderer) _renderGroup(group *tiled.Group) error {
 	return nil
 }
 
-// RenderVisibleLayersAndObjectGroups render all layers and object groups, layer first, objectGroup second
-// so the order may be incorrect,
+// RenderVisibleLayersAndObjectGroups render all image layers, layers and object groups, image layers first,
+// layers second, objectGroup third so the order may be incorrect,
 // you may put them into different groups, then call RenderVisibleGroups
 func (r *Renderer) RenderVisibleLayersAndObjectGroups() error {
 	// TODO: The order maybe incorrect
 
+	if err := r.RenderVisibleImageLayers(); err != nil {
+		return err
+	}
 	if err := r.RenderVisibleLayers(); err != nil {
 		return err
 	}
@@ -112,18 +128,28 @@ func (r *Renderer) RenderObjectGroup(i int) error {
 }
 
 func (r *Renderer) _renderObjectGroup(objectGroup *tiled.ObjectGroup) error {
+	return r._renderObjectGroupProgress(objectGroup, nil)
+}
+
+func (r *Renderer) _renderObjectGroupProgress(objectGroup *tiled.ObjectGroup, progress *renderProgress) error {
 	objs := objectGroup.Objects
 
-	// sort objects from left top to right down
-	objs = internal.SortAnySlice(objs, func(a, b *tiled.Object) bool {
-		if a.Y != b.Y {
+	if objectGroup.DrawOrder != tiled.DrawOrderIndex {
+		// sort a copy by y-coordinate, objects on the same row keep the file order
+		objs = internal.SortStableAnySlice(slices.Clone(objs), func(a, b *tiled.Object) bool {
 			return a.Y < b.Y
-		}
+		})
+	}
 
-		return a.X < b.X
-	})
+	if err := r.renderShadows(objectGroup, objs); err != nil {
+		return err
+	}
 
+	defer r.flushDraws()
 	for _, obj := range objs {
+		if err := progress.err(); err != nil {
+			return err
+		}
 		if err := r.renderOneObject(objectGroup, obj); err != nil {
 			return err
 		}
@@ -147,13 +173,27 @@ func (r *Renderer) RenderGroupObjectGroup(groupID, objectGroupID int) error {
 	return r._renderObjectGroup(layer)
 }
 
+// onCanvas reports whether the bounds, grown by margin pixels, intersect the
+// render target.
+func (r *Renderer) onCanvas(bounds tiled.Rectangle, margin float64) bool {
+	// The view only scales and translates, corners stay corners
+	bounds.Min.X, bounds.Min.Y = r.view.Apply(bounds.Min.X, bounds.Min.Y)
+	bounds.Max.X, bounds.Max.Y = r.view.Apply(bounds.Max.X, bounds.Max.Y)
+	size := r.Result.Bounds().Size()
+	canvas := tiled.Rectangle{
+		Min: tiled.Point{X: -margin, Y: -margin},
+		Max: tiled.Point{X: float64(size.X) + margin, Y: float64(size.Y) + margin},
+	}
+	return bounds.Intersects(canvas)
+}
+
 func (r *Renderer) renderOneObject(layer *tiled.ObjectGroup, o *tiled.Object) error {
 	if !o.Visible {
 		return nil
 	}
 
 	if o.GID == 0 {
-		// TODO: o.GID == 0
+		r.renderShapeObject(layer, o)
 		return nil
 	}
 
@@ -161,28 +201,21 @@ func (r *Renderer) renderOneObject(layer *tiled.ObjectGroup, o *tiled.Object) er
 	if err != nil {
 		return err
 	}
+	if r.animator != nil {
+		tile = r.animator.Frame(tile)
+	}
 
 	img, err := r.getTileImage(tile)
 	if err != nil {
 		return err
 	}
 
-	geom := ebiten.GeoM{}
-
-	bounds := img.Bounds()
-	srcSize := bounds.Size()
-	dstSize := image.Pt(int(o.Width), int(o.Height))
-
-	if !srcSize.Eq(dstSize) {
-		geom.Scale(
-			float64(dstSize.X)/float64(srcSize.X),
-			float64(dstSize.Y)/float64(srcSize.Y),
-		)
-	}
-
-	if o.Rotation != 0 {
-		geom.Rotate(o.Rotation * math.Pi / 180.0)
+	geom, objBounds := tileObjectGeoM(o, tile, img)
+	if !r.onCanvas(objBounds, 0) {
+		return nil
 	}
+	r.countDraw(tile)
+	geom.Concat(r.view)
 
 	colorScale := ebiten.ColorScale{}
 	colorScale.SetA(layer.Opacity)
@@ -192,7 +225,47 @@ func (r *Renderer) renderOneObject(layer *tiled.ObjectGroup, o *tiled.Object) er
 		&ebiten.DrawImageOptions{
 			GeoM:       geom,
 			ColorScale: colorScale,
+			Filter:     r.filter,
 		})
 
 	return nil
 }
+
+// tileObjectGeoM returns the geometry drawing the tile image of a tile
+// object in map pixels, and the bounds of the object
+func tileObjectGeoM(o *tiled.Object, tile *tiled.LayerTile, img image.Image) (ebiten.GeoM, tiled.Rectangle) {
+	bounds := img.Bounds()
+	srcWidth, srcHeight := float64(bounds.Dx()), float64(bounds.Dy())
+	geom := tileFlipGeoM(tile, srcWidth, srcHeight)
+
+	// Objects without size are drawn at the natural size of their tile
+	dstWidth, dstHeight := o.Width, o.Height
+	if dstWidth == 0 {
+		dstWidth = srcWidth
+	}
+	if dstHeight == 0 {
+		dstHeight = srcHeight
+	}
+
+	objBounds := o.BoundingBox()
+	if dstWidth != o.Width || dstHeight != o.Height {
+		sized := *o
+		sized.Width, sized.Height = dstWidth, dstHeight
+		objBounds = sized.BoundingBox()
+	}
+
+	if dstWidth != srcWidth || dstHeight != srcHeight {
+		geom.Scale(dstWidth/srcWidth, dstHeight/srcHeight)
+	}
+
+	// Tile objects are anchored at their bottom left corner
+	geom.Translate(0, -dstHeight)
+	translateTileOffset(&geom, tile.Tileset)
+
+	if o.Rotation != 0 {
+		geom.Rotate(o.Rotation * math.Pi / 180.0)
+	}
+
+	geom.Translate(o.X, o.Y)
+	return geom, objBounds
+}
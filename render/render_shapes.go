@@ -0,0 +1,128 @@
+package render
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+var (
+	// DefaultObjectColor is used to draw objects of object groups without a color, like Tiled does.
+	DefaultObjectColor color.Color = color.RGBA{0xa0, 0xa0, 0xa4, 0xff}
+
+	// ObjectStrokeWidth is the width in pixels of object outlines.
+	ObjectStrokeWidth float32 = 1
+
+	// ObjectPointRadius is the radius in pixels of the circle drawn for point objects.
+	ObjectPointRadius float32 = 4
+)
+
+// ellipseKappa is the distance of the control points used to approximate a
+// quarter of ellipse with a cubic Bézier curve.
+const ellipseKappa = 0.5522847498
+
+// objectShapePath returns the outline of a non-tile object, relative to the
+// object position. Text objects have no outline and return nil.
+func objectShapePath(o *tiled.Object) *vector.Path {
+	path := &vector.Path{}
+	w, h := float32(o.Width), float32(o.Height)
+
+	switch {
+	case o.Text != nil:
+		return nil
+	case len(o.Ellipses) > 0:
+		rx, ry := w/2, h/2
+		kx, ky := rx*ellipseKappa, ry*ellipseKappa
+		path.MoveTo(rx, 0)
+		path.CubicTo(rx+kx, 0, w, ry-ky, w, ry)
+		path.CubicTo(w, ry+ky, rx+kx, h, rx, h)
+		path.CubicTo(rx-kx, h, 0, ry+ky, 0, ry)
+		path.CubicTo(0, ry-ky, rx-kx, 0, rx, 0)
+		path.Close()
+	case len(o.Polygons) > 0:
+		for _, polygon := range o.Polygons {
+			appendPoints(path, polygon.Points)
+			path.Close()
+		}
+	case len(o.PolyLines) > 0:
+		for _, polyline := range o.PolyLines {
+			appendPoints(path, polyline.Points)
+		}
+	case w == 0 && h == 0:
+		path.Arc(0, 0, ObjectPointRadius, 0, 2*math.Pi, vector.Clockwise)
+		path.Close()
+	default:
+		path.MoveTo(0, 0)
+		path.LineTo(w, 0)
+		path.LineTo(w, h)
+		path.LineTo(0, h)
+		path.Close()
+	}
+
+	return path
+}
+
+func appendPoints(path *vector.Path, points *tiled.Points) {
+	if points == nil {
+		return
+	}
+	for i, p := range *points {
+		if i == 0 {
+			path.MoveTo(float32(p.X), float32(p.Y))
+		} else {
+			path.LineTo(float32(p.X), float32(p.Y))
+		}
+	}
+}
+
+// objectColor returns the color used to outline objects of the group
+func objectColor(layer *tiled.ObjectGroup) color.Color {
+	if layer.Color != nil {
+		return layer.Color
+	}
+	return DefaultObjectColor
+}
+
+// renderShapeObject strokes the outline of a non-tile object with the color
+// of its object group.
+func (r *Renderer) renderShapeObject(layer *tiled.ObjectGroup, o *tiled.Object) {
+	margin := float64(ObjectStrokeWidth)
+	if o.IsPoint() {
+		margin += float64(ObjectPointRadius)
+	}
+	if !r.onCanvas(o.BoundingBox(), margin) {
+		return
+	}
+
+	shape := objectShapePath(o)
+	if shape == nil {
+		return
+	}
+
+	geom := ebiten.GeoM{}
+	if o.Rotation != 0 {
+		geom.Rotate(o.Rotation * math.Pi / 180.0)
+	}
+	geom.Translate(o.X, o.Y)
+	geom.Concat(r.view)
+
+	path := &vector.Path{}
+	path.AddPath(shape, &vector.AddPathOptions{GeoM: geom})
+
+	colorScale := ebiten.ColorScale{}
+	colorScale.ScaleWithColor(objectColor(layer))
+	colorScale.ScaleAlpha(layer.Opacity)
+
+	vector.StrokePath(r.Result, path,
+		&vector.StrokeOptions{
+			Width:    ObjectStrokeWidth,
+			LineJoin: vector.LineJoinRound,
+		},
+		&vector.DrawPathOptions{
+			AntiAlias:  true,
+			ColorScale: colorScale,
+		})
+}
@@ -23,6 +23,8 @@ SOFTWARE.
 package render
 
 import (
+	"bytes"
+	"cmp"
 	"errors"
 	"fmt"
 	"image"
@@ -31,11 +33,17 @@ import (
 	"image/png"
 	"io"
 	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/Tsukumogami-Software/go-tiled/internal"
 	"github.com/hajimehoshi/ebiten/v2"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
 )
 
 var (
@@ -43,26 +51,68 @@ var (
 	ErrUnsupportedOrientation = errors.New("tiled/render: unsupported orientation")
 	// ErrUnsupportedRenderOrder represents an error in the unsupported order for rendering.
 	ErrUnsupportedRenderOrder = errors.New("tiled/render: unsupported render order")
+	// ErrUnsupportedFeature represents a map feature that is not rendered.
+	ErrUnsupportedFeature = errors.New("tiled/render: unsupported feature")
 
 	// ErrOutOfBounds represents an error that the index is out of bounds
 	ErrOutOfBounds = errors.New("tiled/render: index out of bounds")
+
+	// ErrNoMaps represents an error that no map was given to a MultiMapRenderer
+	ErrNoMaps = errors.New("tiled/render: no maps to render")
+
+	// ErrObjectNotFound represents an error that no object has the given ID
+	ErrObjectNotFound = errors.New("tiled/render: object not found")
+
+	// ErrBudgetExceeded represents a map requiring more resources than its budget
+	ErrBudgetExceeded = errors.New("tiled/render: resource budget exceeded")
+
+	// ErrTileImageNotFound represents a tile without an image in its tileset
+	ErrTileImageNotFound = errors.New("tiled/render: tile image not found")
+
+	// ErrNoCaptureDuration represents a capture along a path without speed
+	// and without a duration
+	ErrNoCaptureDuration = errors.New("tiled/render: camera path capture has no duration")
 )
 
-// RendererEngine helps compute the tile geometries
+// RendererEngine computes where the tiles of a map are drawn, making the
+// orientation specific part of a Renderer. Engines are registered for an
+// orientation with RegisterEngine. The contract below is stable, see
+// EngineAPIVersion.
 type RendererEngine interface {
+	// Init is called with the map to render before any other method, and
+	// again when the engine is reused for another map.
 	Init(m *tiled.Map)
+	// GetFinalImageSize returns the size in pixels of the image holding
+	// the whole map.
 	GetFinalImageSize() (int, int)
+	// GetTileGeometry returns the geometry drawing the image of tile, of the
+	// tile size of its tileset, at the cell x, y of a layer. Flip flags of
+	// the tile are applied by the geometry, keeping the tile in place. Tiles
+	// of the map tile size are drawn inside the final image.
 	GetTileGeometry(x, y int, tile *tiled.LayerTile) ebiten.GeoM
 }
 
-// Renderer represents an rendering engine.
+// Renderer represents an rendering engine. A Renderer is not safe for
+// concurrent use, see Fork to render layers of a map concurrently.
 type Renderer struct {
-	m            *tiled.Map
-	Result       *ebiten.Image // The image result after rendering using the Render functions.
-	tileCache    map[uint32]image.Image
-	engine       RendererEngine
-	fs           fs.FS
-	tilesetCache *TilesetCache
+	m              *tiled.Map
+	Result         *ebiten.Image // The image result after rendering using the Render functions.
+	PreloadWorkers int           // Number of images decoded concurrently by Preload, defaults to the number of CPUs.
+	tileCache      map[uint32]image.Image
+	engine         RendererEngine
+	fs             fs.FS
+	tilesetCache   *TilesetCache
+	animator       *tiled.Animator
+	atlas          *Atlas
+	stats          RenderStats
+	batch          triangleBatch // Reused between renders to keep its buffers
+	draws          map[tileKey]int
+	view           ebiten.GeoM   // Applied to everything drawn, see RenderMinimap
+	filter         ebiten.Filter // Filter tiles are drawn with
+	shadows        *ShadowOptions
+	layerImages    map[string]*ebiten.Image          // Images of image layers, by path
+	paintersOrder  bool                              // Whether tiles are sorted by bottom edge, see UsePaintersOrder
+	gridTilesets   map[*tiled.Tileset]*tiled.Tileset // Copies of tilesets rendered at the map tile size, with that tile size
 }
 
 // NewRenderer creates new rendering engine instance.
@@ -72,25 +122,95 @@ func NewRenderer(m *tiled.Map) (*Renderer, error) {
 
 // NewRendererWithFileSystem creates new rendering engine instance with a custom file system.
 func NewRendererWithFileSystem(m *tiled.Map, fs fs.FS) (*Renderer, error) {
-	r := &Renderer{m: m, tileCache: make(map[uint32]image.Image), fs: fs}
-	if r.m.Orientation == "orthogonal" {
-		r.engine = &OrthogonalRendererEngine{}
-	} else {
-		return nil, ErrUnsupportedOrientation
+	engine, err := newEngine(m)
+	if err != nil {
+		return nil, err
 	}
+	return NewRendererWithEngine(m, fs, engine), nil
+}
 
-	r.engine.Init(r.m)
-	width, height := r.engine.GetFinalImageSize()
-	r.Result = ebiten.NewImage(width, height)
-
+// NewRendererWithCache creates new rendering engine instance sharing decoded
+// tileset images with other renderers through the given TilesetCache. Images
+// are loaded with the file system of the cache.
+func NewRendererWithCache(m *tiled.Map, tilesetCache *TilesetCache) (*Renderer, error) {
+	r, err := NewRendererWithFileSystem(m, tilesetCache.fs)
+	if err != nil {
+		return nil, err
+	}
+	r.UseTilesetCache(tilesetCache)
 	return r, nil
 }
 
-// UseTilesetCache is used to set a shared TilesetCache
+// UseTilesetCache is used to set a shared TilesetCache. Once set, tile images
+// are only cached in the shared TilesetCache.
 func (r *Renderer) UseTilesetCache(tilesetCache *TilesetCache) {
 	r.tilesetCache = tilesetCache
 }
 
+// Fork creates a renderer of the same map with a Result of its own, sharing
+// the Atlas and tile images of r through a TilesetCache, created for r if
+// needed. The view, filter and other settings of r are copied. The tilesets
+// of the map are initialised first, so r and its forks can render
+// concurrently, for example different layers, once forked.
+func (r *Renderer) Fork() (*Renderer, error) {
+	if err := r.m.InitTilesets(); err != nil {
+		return nil, err
+	}
+
+	if r.tilesetCache == nil {
+		r.UseTilesetCache(NewTilesetCache(r.fs))
+
+		// Tiles decoded so far are moved to the shared cache
+		for gid, img := range r.tileCache {
+			tile, err := r.m.TileGIDToTile(gid)
+			if err != nil {
+				continue
+			}
+			r.tilesetCache.put(tilesetKey(tile.Tileset), map[uint32]image.Image{tile.ID: img})
+		}
+		r.tileCache = make(map[uint32]image.Image)
+	}
+
+	width, height := r.engine.GetFinalImageSize()
+	return &Renderer{
+		m:              r.m,
+		Result:         ebiten.NewImage(width, height),
+		PreloadWorkers: r.PreloadWorkers,
+		tileCache:      make(map[uint32]image.Image),
+		engine:         r.engine,
+		fs:             r.fs,
+		tilesetCache:   r.tilesetCache,
+		animator:       r.animator,
+		atlas:          r.atlas,
+		view:           r.view,
+		filter:         r.filter,
+		shadows:        r.shadows,
+		paintersOrder:  r.paintersOrder,
+	}, nil
+}
+
+// UsePaintersOrder sets whether the tiles of each layer are drawn by the
+// bottom edge of where they land rather than cell by cell, ties keeping the
+// render order. Tiles taller than the map tiles then overlap the tiles above
+// them the same way whatever the orientation, so translucent overlaps
+// composite deterministically, at the cost of sorting each layer.
+func (r *Renderer) UsePaintersOrder(enabled bool) {
+	r.paintersOrder = enabled
+}
+
+// UseAnimator is used to render animated tiles at the current frame of the
+// given Animator. A nil Animator renders the first frame.
+func (r *Renderer) UseAnimator(animator *tiled.Animator) {
+	r.animator = animator
+}
+
+// UseAtlas is used to draw tile layers from the textures of the given Atlas,
+// with a DrawTriangles call per texture instead of a DrawImage call per tile.
+// A nil Atlas goes back to drawing tiles one by one.
+func (r *Renderer) UseAtlas(atlas *Atlas) {
+	r.atlas = atlas
+}
+
 func (r *Renderer) open(f string) (io.ReadCloser, error) {
 	if r.fs == nil {
 		return os.Open(filepath.FromSlash(f))
@@ -98,75 +218,207 @@ func (r *Renderer) open(f string) (io.ReadCloser, error) {
 	return r.fs.Open(filepath.ToSlash(f))
 }
 
-func (r *Renderer) getTileImageFromTile(tile *tiled.LayerTile) (image.Image, error) {
-	tilesetTile, err := tile.Tileset.GetTilesetTile(tile.ID)
+// tileImage returns the image of a tile of an image collection tileset
+func tileImage(tile *tiled.LayerTile) (*tiled.Image, error) {
+	tilesetTile := tile.Tileset.Tile(tile.ID)
+	if tilesetTile == nil || tilesetTile.Image == nil {
+		return nil, fmt.Errorf("%w: tile %d of tileset %q", ErrTileImageNotFound, tile.ID, tile.Tileset.Name)
+	}
+	return tilesetTile.Image, nil
+}
+
+// decodeImage decodes the image holding the tile
+func (r *Renderer) decodeImage(tile *tiled.LayerTile) (image.Image, error) {
+	timg, err := tiledImage(tile)
 	if err != nil {
 		return nil, err
 	}
-
-	sf, err := r.open(tile.Tileset.GetFileFullPath(tilesetTile.Image.Source))
+	sf, err := openImage(r.open, tile.Tileset, timg)
 	if err != nil {
 		return nil, err
 	}
 	defer sf.Close()
 
 	img, _, err := image.Decode(sf)
-	if err != nil {
-		return nil, err
+	return img, err
+}
+
+// tileImageSize returns the size of the image of a tile: the tile size of its
+// tileset for tilesets made of a single image, the size of its own image for
+// image collections, or the tile size of its tileset when that is unknown
+func tileImageSize(tile *tiled.LayerTile) (float64, float64) {
+	ts := tile.Tileset
+	if ts.Image == nil {
+		if img, err := tileImage(tile); err == nil && img.Width > 0 && img.Height > 0 {
+			return float64(img.Width), float64(img.Height)
+		}
 	}
+	return float64(ts.TileWidth), float64(ts.TileHeight)
+}
 
-	res := ebiten.NewImageFromImage(img)
-	r.tileCache[tile.Tileset.FirstGID+tile.ID] = res
-	return res, nil
+// tiledImage returns the image holding the tile, of its tileset or its own
+func tiledImage(tile *tiled.LayerTile) (*tiled.Image, error) {
+	if tile.Tileset.Image != nil {
+		return tile.Tileset.Image, nil
+	}
+	return tileImage(tile)
 }
 
-func (r *Renderer) getTileImageFromTileset(tile *tiled.LayerTile) (image.Image, error) {
-	sf, err := r.open(tile.Tileset.GetFileFullPath(tile.Tileset.Image.Source))
-	if err != nil {
-		return nil, err
+// imageKey identifies an image of a tileset or map: the path of its file, or
+// its address for embedded images
+func imageKey(ts tiled.FileResolver, img *tiled.Image) string {
+	if img.Embedded() {
+		return fmt.Sprintf("embedded:%p", img)
 	}
-	defer sf.Close()
+	return ts.GetFileFullPath(img.Source)
+}
 
-	img, _, err := image.Decode(sf)
+// openImage opens an image of a tileset or map, reading embedded images from
+// the file and image files with open
+func openImage(open func(string) (io.ReadCloser, error), ts tiled.FileResolver, img *tiled.Image) (io.ReadCloser, error) {
+	if img.Embedded() {
+		data, err := img.EmbeddedData()
+		if err != nil {
+			return nil, err
+		}
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return open(ts.GetFileFullPath(img.Source))
+}
+
+// tileImagePath returns the path of the image file holding the tile, or the
+// key of its embedded image
+func tileImagePath(tile *tiled.LayerTile) (string, error) {
+	timg, err := tiledImage(tile)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	return imageKey(tile.Tileset, timg), nil
+}
+
+// storeTileImage caches the decoded image file of a tile, and of all tiles
+// of its tileset for tilesets made of a single image
+func (r *Renderer) storeTileImage(tile *tiled.LayerTile, eimg *ebiten.Image) {
+	if tile.Tileset.Image == nil {
+		r.tileCache[tile.Tileset.FirstGID+tile.ID] = eimg
+		return
 	}
-	eimg := ebiten.NewImageFromImage(img)
 
-	// Precache all tiles in tileset
-	var res image.Image
 	for i := uint32(0); i < uint32(tile.Tileset.TileCount); i++ {
 		rect := tile.Tileset.GetTileRect(i)
 		r.tileCache[i+tile.Tileset.FirstGID] = eimg.SubImage(rect)
-		if tile.ID == i {
-			res = r.tileCache[i+tile.Tileset.FirstGID]
-		}
 	}
-
-	if res != nil {
-		return res, nil
-	}
-	return nil, fmt.Errorf("Tile image not found in tileset: %d", tile.ID)
 }
 
 func (r *Renderer) getTileImage(tile *tiled.LayerTile) (image.Image, error) {
+	if r.tilesetCache != nil {
+		img, hit, err := r.tilesetCache.tileImage(tile)
+		if hit {
+			r.stats.CacheHits++
+		} else {
+			r.stats.CacheMisses++
+			if err == nil {
+				r.stats.ImagesDecoded++
+			}
+		}
+		return img, err
+	}
+
 	timg, ok := r.tileCache[tile.Tileset.FirstGID+tile.ID]
 	if ok {
+		r.stats.CacheHits++
 		return timg, nil
 	}
+	r.stats.CacheMisses++
 
-	if tile.Tileset.Image == nil {
-		return r.getTileImageFromTile(tile)
+	img, err := r.decodeImage(tile)
+	if err != nil {
+		return nil, err
 	}
+	r.stats.ImagesDecoded++
+	r.storeTileImage(tile, ebiten.NewImageFromImage(img))
 
-	if r.tilesetCache != nil {
-		return r.tilesetCache.GetTileImage(tile)
+	if timg, ok := r.tileCache[tile.Tileset.FirstGID+tile.ID]; ok {
+		return timg, nil
+	}
+	return nil, fmt.Errorf("%w: tile %d of tileset %q", ErrTileImageNotFound, tile.ID, tile.Tileset.Name)
+}
+
+// tileGeometry returns the geometry drawing the image of a tile of a layer at
+// the cell x, y in map pixels. Tiles of tilesets rendered at the grid size are
+// scaled from the size of their image to the map tile size following the fill
+// mode of their tileset, and the tile offset of the tileset is applied.
+func (r *Renderer) tileGeometry(x, y int, tile *tiled.LayerTile) ebiten.GeoM {
+	ts := tile.Tileset
+	tw, th := tileImageSize(tile)
+	gw, gh := float64(r.m.TileWidth), float64(r.m.TileHeight)
+	if ts.TileRenderSize != tiled.TileRenderSizeGrid || tw == 0 || th == 0 || tw == gw && th == gh {
+		geom := r.engine.GetTileGeometry(x, y, tile)
+		translateTileOffset(&geom, ts)
+		return geom
 	}
 
-	return r.getTileImageFromTileset(tile)
+	// The engine places a tile of the map tile size, the image is scaled to it
+	geom := ebiten.GeoM{}
+	sx, sy := gw/tw, gh/th
+	if ts.FillMode == tiled.FillModePreserveAspectFit {
+		scale := min(sx, sy)
+		geom.Scale(scale, scale)
+		geom.Translate((gw-tw*scale)/2, (gh-th*scale)/2)
+	} else {
+		geom.Scale(sx, sy)
+	}
+	cell := *tile
+	cell.Tileset = r.gridTileset(ts)
+	geom.Concat(r.engine.GetTileGeometry(x, y, &cell))
+	translateTileOffset(&geom, ts)
+	return geom
+}
+
+// gridTileset returns a copy of ts with the map tile size, cached
+func (r *Renderer) gridTileset(ts *tiled.Tileset) *tiled.Tileset {
+	if grid, ok := r.gridTilesets[ts]; ok {
+		return grid
+	}
+	grid := *ts
+	grid.TileWidth, grid.TileHeight = r.m.TileWidth, r.m.TileHeight
+	if r.gridTilesets == nil {
+		r.gridTilesets = map[*tiled.Tileset]*tiled.Tileset{}
+	}
+	r.gridTilesets[ts] = &grid
+	return &grid
+}
+
+// translateTileOffset moves geom by the tile offset of ts, if any
+func translateTileOffset(geom *ebiten.GeoM, ts *tiled.Tileset) {
+	if off := ts.TileOffset; off != nil {
+		geom.Translate(float64(off.X), float64(off.Y))
+	}
+}
+
+// paintedTile is a tile waiting to be drawn in painter's order
+type paintedTile struct {
+	tile   *tiled.LayerTile
+	geom   ebiten.GeoM
+	bottom float64
+}
+
+// tileBottom returns the lowest y of the image of a tile drawn with geom
+func tileBottom(tile *tiled.LayerTile, geom ebiten.GeoM) float64 {
+	w, h := tileImageSize(tile)
+	bottom := math.Inf(-1)
+	for _, p := range [4][2]float64{{0, 0}, {w, 0}, {0, h}, {w, h}} {
+		_, y := geom.Apply(p[0], p[1])
+		bottom = max(bottom, y)
+	}
+	return bottom
 }
 
 func (r *Renderer) _renderLayer(layer *tiled.Layer) error {
+	return r._renderLayerProgress(layer, nil)
+}
+
+func (r *Renderer) _renderLayerProgress(layer *tiled.Layer, progress *renderProgress) error {
 	var xs, xe, xi, ys, ye, yi int
 	if r.m.RenderOrder == "" || r.m.RenderOrder == "right-down" {
 		xs = 0
@@ -179,36 +431,91 @@ func (r *Renderer) _renderLayer(layer *tiled.Layer) error {
 		return ErrUnsupportedRenderOrder
 	}
 
+	var batch *triangleBatch
+	if r.atlas != nil {
+		batch = &r.batch
+		batch.reset(r.atlas)
+		batch.options.Filter = r.filter
+	}
+
+	// Options are shared by all tiles of the layer to avoid allocations
+	op := ebiten.DrawImageOptions{}
+	op.ColorScale.SetA(layer.Opacity)
+	op.Filter = r.filter
+
+	start := time.Now()
+	drawn, skipped := 0, 0
+	defer func() {
+		r.addLayerStats(layer, drawn, skipped, start)
+		r.flushDraws()
+	}()
+
+	draw := func(tile *tiled.LayerTile, geom ebiten.GeoM) error {
+		if batch != nil {
+			return batch.add(r.Result, tile, geom, layer.Opacity)
+		}
+		img, err := r.getTileImage(tile)
+		if err != nil {
+			return err
+		}
+		op.GeoM = geom
+		r.Result.DrawImage(img.(*ebiten.Image), &op)
+		return nil
+	}
+
+	// Tiles drawn once sorted, in painter's order
+	var sorted []paintedTile
+
 	i := 0
 	for y := ys; y*yi < ye; y = y + yi {
+		if err := progress.err(); err != nil {
+			return err
+		}
+
 		for x := xs; x*xi < xe; x = x + xi {
 			tile := layer.Tiles[i]
+			i++
 			if tile == nil || tile.IsNil() {
-				i++
+				skipped++
 				continue
 			}
+			drawn++
 
-			img, err := r.getTileImage(tile)
-			if err != nil {
-				return err
+			if r.animator != nil {
+				tile = r.animator.Frame(tile)
 			}
+			r.countDraw(tile)
 
-			geom := r.engine.GetTileGeometry(x, y, tile)
-
-			colorScale := ebiten.ColorScale{}
-			colorScale.SetA(layer.Opacity)
+			geom := r.tileGeometry(x, y, tile)
+			geom.Concat(r.view)
+			if r.paintersOrder {
+				sorted = append(sorted, paintedTile{tile: tile, geom: geom, bottom: tileBottom(tile, geom)})
+				continue
+			}
+			if err := draw(tile, geom); err != nil {
+				return err
+			}
+		}
 
-			r.Result.DrawImage(
-				img.(*ebiten.Image),
-				&ebiten.DrawImageOptions{
-					GeoM:       geom,
-					ColorScale: colorScale,
-				})
+		progress.add(r.m.Width)
+	}
 
-			i++
+	if r.paintersOrder {
+		// Stable, so tiles with the same bottom edge keep the render order
+		slices.SortStableFunc(sorted, func(a, b paintedTile) int {
+			return cmp.Compare(a.bottom, b.bottom)
+		})
+		for _, t := range sorted {
+			if err := draw(t.tile, t.geom); err != nil {
+				return err
+			}
 		}
 	}
 
+	if batch != nil {
+		batch.flush(r.Result)
+	}
+
 	return nil
 }
 
@@ -248,6 +555,90 @@ func (r *Renderer) RenderVisibleLayers() error {
 	return nil
 }
 
+// RenderLayers renders the visible layers of the given set, in order.
+func (r *Renderer) RenderLayers(layers tiled.Layers) error {
+	for _, layer := range layers.Visible() {
+		if err := r._renderLayer(layer); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenderTileLayersToImages renders each visible tile layer of the map into
+// an image of its own, returned in order. Only tile layers outside groups are
+// rendered: image layers, object groups and groups are not, see SaveAsORA to
+// export every layer. Result is left untouched, and tile images are decoded
+// once for all layers.
+func (r *Renderer) RenderTileLayersToImages() ([]*ebiten.Image, error) {
+	result := r.Result
+	defer func() {
+		r.Result = result
+	}()
+
+	width, height := r.engine.GetFinalImageSize()
+	var res []*ebiten.Image
+	for _, layer := range tiled.Layers(r.m.Layers).Visible() {
+		r.Result = ebiten.NewImage(width, height)
+		if err := r._renderLayer(layer); err != nil {
+			return nil, err
+		}
+		res = append(res, r.Result)
+	}
+
+	return res, nil
+}
+
+// RenderLayersByClass renders all visible layers with the given class,
+// including the ones nested in groups.
+func (r *Renderer) RenderLayersByClass(class string) error {
+	return r.RenderLayers(r.m.LayersByClass(class))
+}
+
+// ExportLayers renders each visible layer of the given set into an image of
+// its own and writes it as PNG to the writer create returns for the layer,
+// closing it afterwards. Result is left untouched.
+func (r *Renderer) ExportLayers(layers tiled.Layers, create func(*tiled.Layer) (io.WriteCloser, error)) error {
+	result := r.Result
+	defer func() {
+		r.Result = result
+	}()
+
+	width, height := r.engine.GetFinalImageSize()
+	for _, layer := range layers.Visible() {
+		r.Result = ebiten.NewImage(width, height)
+		err := r._renderLayer(layer)
+		if err == nil {
+			err = r.exportLayer(layer, create)
+		}
+		r.Result.Deallocate()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Renderer) exportLayer(layer *tiled.Layer, create func(*tiled.Layer) (io.WriteCloser, error)) error {
+	w, err := create(layer)
+	if err != nil {
+		return err
+	}
+	if err := r.SaveAsPng(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// ExportLayersByClass exports all visible layers with the given class,
+// including the ones nested in groups, like ExportLayers does.
+func (r *Renderer) ExportLayersByClass(class string, create func(*tiled.Layer) (io.WriteCloser, error)) error {
+	return r.ExportLayers(r.m.LayersByClass(class), create)
+}
+
 // Clear clears the render result to allow for separation of layers. For example, you can
 // render a layer, make a copy of the render, clear the renderer, and repeat for each
 // layer in the Map.
@@ -269,3 +660,20 @@ func (r *Renderer) SaveAsJpeg(w io.Writer, options *jpeg.Options) error {
 func (r *Renderer) SaveAsGif(w io.Writer, options *gif.Options) error {
 	return gif.Encode(w, r.Result, options)
 }
+
+// SaveAsBMP writes rendered layers as BMP image to provided writer.
+func (r *Renderer) SaveAsBMP(w io.Writer) error {
+	return bmp.Encode(w, r.Result)
+}
+
+// SaveAsTIFF writes rendered layers as TIFF image to provided writer.
+func (r *Renderer) SaveAsTIFF(w io.Writer, options *tiff.Options) error {
+	return tiff.Encode(w, r.Result, options)
+}
+
+// SaveAsWebP writes rendered layers as lossless WebP image to provided writer.
+// Quality goes from 0 to 100, lower qualities reducing color precision for
+// smaller files.
+func (r *Renderer) SaveAsWebP(w io.Writer, quality int) error {
+	return internal.EncodeWebP(w, r.Result, quality)
+}
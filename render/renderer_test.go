@@ -0,0 +1,123 @@
+package render
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForkConcurrent(t *testing.T) {
+	m, err := tiled.LoadFile(filepath.Join("..", "assets", "test2.tmx"))
+	assert.NoError(t, err)
+
+	r, err := NewRenderer(m)
+	assert.NoError(t, err)
+	r.UseAnimator(tiled.NewAnimator(tiled.SyncGroup))
+	r.view.Scale(2, 2)
+	r.filter = ebiten.FilterLinear
+
+	forks := make([]*Renderer, 2)
+	for i := range forks {
+		forks[i], err = r.Fork()
+		assert.NoError(t, err)
+		assert.Equal(t, r.view, forks[i].view)
+		assert.Equal(t, r.filter, forks[i].filter)
+		assert.NotSame(t, r.Result, forks[i].Result)
+	}
+
+	// Run with -race: forks share the map, its tilesets and their images
+	var wg sync.WaitGroup
+	errs := make([]error, len(forks))
+	for i, fork := range forks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fork.RenderVisibleLayers()
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+}
+
+// collectionTile returns a tile of an image collection whose tiles are at
+// most 64x64 pixels, with an image of the given size
+func collectionTile(width, height int) *tiled.LayerTile {
+	ts := &tiled.Tileset{
+		Name: "props", TileWidth: 64, TileHeight: 64, TileCount: 1,
+		Tiles: []*tiled.TilesetTile{{ID: 0, Image: &tiled.Image{Source: "prop.png", Width: width, Height: height}}},
+	}
+	return &tiled.LayerTile{Tileset: ts}
+}
+
+func TestTileBottom(t *testing.T) {
+	tile := collectionTile(16, 48)
+	geom := ebiten.GeoM{}
+	geom.Translate(0, 10)
+	assert.Equal(t, 58.0, tileBottom(tile, geom))
+
+	// Tilesets made of a single image use their tile size
+	tile.Tileset.Image = &tiled.Image{Source: "sheet.png", Width: 128, Height: 128}
+	assert.Equal(t, 74.0, tileBottom(tile, geom))
+}
+
+func TestTileGeometryGridSize(t *testing.T) {
+	m := &tiled.Map{Width: 2, Height: 2, TileWidth: 32, TileHeight: 32}
+	engine := &OrthogonalRendererEngine{}
+	engine.Init(m)
+	r := &Renderer{m: m, engine: engine}
+
+	// Images are scaled from their own size, not the tile size of the tileset
+	tile := collectionTile(16, 32)
+	tile.Tileset.TileRenderSize = tiled.TileRenderSizeGrid
+	tile.Tileset.FillMode = tiled.FillModePreserveAspectFit
+	geom := r.tileGeometry(1, 0, tile)
+	x, y := geom.Apply(0, 0)
+	assert.Equal(t, [2]float64{40, 0}, [2]float64{x, y})
+	x, y = geom.Apply(16, 32)
+	assert.Equal(t, [2]float64{56, 32}, [2]float64{x, y})
+
+	tile.Tileset.FillMode = tiled.FillModeStretch
+	geom = r.tileGeometry(0, 0, tile)
+	x, y = geom.Apply(16, 32)
+	assert.Equal(t, [2]float64{32, 32}, [2]float64{x, y})
+}
+
+func TestExportLayersByClass(t *testing.T) {
+	m, err := tiled.LoadReader(".", strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="2" tilewidth="16" tileheight="16">
+<layer id="1" name="Ground" class="floor" width="2" height="2"><data encoding="csv">0,0,0,0</data></layer>
+<layer id="2" name="Walls" class="collision" width="2" height="2"><data encoding="csv">0,0,0,0</data></layer>
+<group id="3" name="Interior">
+<layer id="4" name="Furniture" class="collision" width="2" height="2"><data encoding="csv">0,0,0,0</data></layer>
+<layer id="5" name="Hidden" class="collision" visible="0" width="2" height="2"><data encoding="csv">0,0,0,0</data></layer>
+</group>
+</map>`))
+	assert.NoError(t, err)
+	r, err := NewRenderer(m)
+	assert.NoError(t, err)
+	result := r.Result
+
+	// Writers are created once their layer is rendered, failing the first
+	// one stops before encoding, which reads the image back from the GPU
+	errStop := errors.New("stop")
+	var names []string
+	create := func(l *tiled.Layer) (io.WriteCloser, error) {
+		names = append(names, l.Name)
+		return nil, errStop
+	}
+	assert.ErrorIs(t, r.ExportLayersByClass("collision", create), errStop)
+	assert.Equal(t, []string{"Walls"}, names)
+
+	assert.NoError(t, r.ExportLayersByClass("missing", create))
+	assert.Len(t, names, 1)
+	assert.Same(t, result, r.Result)
+}
@@ -0,0 +1,152 @@
+// Package rendertest checks RendererEngine implementations against the
+// contract documented by the render package, so engines maintained outside
+// of it can be tested the same way as the built-in ones.
+package rendertest
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/Tsukumogami-Software/go-tiled/render"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+const epsilon = 1e-6
+
+// TestEngine checks the engines returned by newEngine against the
+// RendererEngine contract, with copies of m of several sizes. m sets the
+// orientation and its parameters, such as the stagger axis or the hex side
+// length; its size, tile size and tilesets are replaced.
+func TestEngine(t *testing.T, m *tiled.Map, newEngine func() render.RendererEngine) {
+	t.Run("Size", func(t *testing.T) {
+		e := newEngine()
+		var prevW, prevH int
+		for _, size := range [][2]int{{1, 1}, {3, 2}, {8, 5}} {
+			e.Init(mapOf(m, size[0], size[1], 16, 8))
+			w, h := e.GetFinalImageSize()
+			if w <= 0 || h <= 0 {
+				t.Errorf("%dx%d map: image size %dx%d is empty", size[0], size[1], w, h)
+			}
+			if w < prevW || h < prevH {
+				t.Errorf("%dx%d map: image size %dx%d smaller than %dx%d of a smaller map", size[0], size[1], w, h, prevW, prevH)
+			}
+			prevW, prevH = w, h
+		}
+
+		// Engines are reused for other maps after Init
+		e.Init(mapOf(m, 1, 1, 16, 8))
+		if w, h := e.GetFinalImageSize(); w >= prevW && h >= prevH {
+			t.Errorf("image size %dx%d not updated by Init", w, h)
+		}
+	})
+
+	t.Run("Cells", func(t *testing.T) {
+		e := newEngine()
+		mm := mapOf(m, 5, 4, 16, 8)
+		e.Init(mm)
+		w, h := e.GetFinalImageSize()
+		tile := layerTile(mm)
+
+		seen := map[[2]float64][2]int{}
+		for y := 0; y < mm.Height; y++ {
+			for x := 0; x < mm.Width; x++ {
+				geo := e.GetTileGeometry(x, y, tile)
+				if again := e.GetTileGeometry(x, y, tile); again != geo {
+					t.Errorf("cell %d,%d: geometry changed between calls", x, y)
+				}
+
+				minX, minY, maxX, maxY := bounds(geo, 16, 8)
+				if minX < -epsilon || minY < -epsilon || maxX > float64(w)+epsilon || maxY > float64(h)+epsilon {
+					t.Errorf("cell %d,%d: tile drawn at %g,%g-%g,%g, outside of the %dx%d image", x, y, minX, minY, maxX, maxY, w, h)
+				}
+
+				pos := [2]float64{math.Round(minX), math.Round(minY)}
+				if other, ok := seen[pos]; ok {
+					t.Errorf("cells %d,%d and %d,%d drawn at the same position", other[0], other[1], x, y)
+				}
+				seen[pos] = [2]int{x, y}
+			}
+		}
+	})
+
+	t.Run("Flips", func(t *testing.T) {
+		e := newEngine()
+		mm := mapOf(m, 3, 3, 16, 16)
+		e.Init(mm)
+		tile := layerTile(mm)
+		geo := e.GetTileGeometry(1, 1, tile)
+		minX, minY, maxX, maxY := bounds(geo, 16, 16)
+
+		for flags := 1; flags < 8; flags++ {
+			flipped := *tile
+			flipped.HorizontalFlip = flags&1 != 0
+			flipped.VerticalFlip = flags&2 != 0
+			flipped.DiagonalFlip = flags&4 != 0
+			fgeo := e.GetTileGeometry(1, 1, &flipped)
+
+			fminX, fminY, fmaxX, fmaxY := bounds(fgeo, 16, 16)
+			if !near(fminX, minX) || !near(fminY, minY) || !near(fmaxX, maxX) || !near(fmaxY, maxY) {
+				t.Errorf("flags h=%t v=%t d=%t: tile moved from %g,%g-%g,%g to %g,%g-%g,%g", flipped.HorizontalFlip, flipped.VerticalFlip, flipped.DiagonalFlip,
+					minX, minY, maxX, maxY, fminX, fminY, fmaxX, fmaxY)
+			}
+		}
+
+		// Single flags move the corners of the image like Tiled does
+		for _, c := range []struct {
+			name     string
+			set      func(*tiled.LayerTile)
+			from, to [2]float64
+		}{
+			{"horizontal", func(t *tiled.LayerTile) { t.HorizontalFlip = true }, [2]float64{0, 0}, [2]float64{16, 0}},
+			{"vertical", func(t *tiled.LayerTile) { t.VerticalFlip = true }, [2]float64{0, 0}, [2]float64{0, 16}},
+			{"diagonal", func(t *tiled.LayerTile) { t.DiagonalFlip = true }, [2]float64{16, 0}, [2]float64{0, 16}},
+		} {
+			flipped := *tile
+			c.set(&flipped)
+			fgeo := e.GetTileGeometry(1, 1, &flipped)
+			fx, fy := fgeo.Apply(c.from[0], c.from[1])
+			x, y := geo.Apply(c.to[0], c.to[1])
+			if !near(fx, x) || !near(fy, y) {
+				t.Errorf("%s flip: image point %g,%g drawn at %g,%g instead of %g,%g", c.name, c.from[0], c.from[1], fx, fy, x, y)
+			}
+		}
+	})
+}
+
+// mapOf returns a copy of m with the given size, and a tileset of the tile
+// size
+func mapOf(m *tiled.Map, width, height, tileWidth, tileHeight int) *tiled.Map {
+	res := *m
+	res.Width, res.Height = width, height
+	res.TileWidth, res.TileHeight = tileWidth, tileHeight
+	res.Tilesets = []*tiled.Tileset{{
+		FirstGID:   1,
+		Name:       "rendertest",
+		TileWidth:  tileWidth,
+		TileHeight: tileHeight,
+		TileCount:  1,
+		Columns:    1,
+	}}
+	return &res
+}
+
+func layerTile(m *tiled.Map) *tiled.LayerTile {
+	return &tiled.LayerTile{ID: 0, Tileset: m.Tilesets[0]}
+}
+
+// bounds returns the bounds of a width by height image drawn with geo
+func bounds(geo ebiten.GeoM, width, height float64) (minX, minY, maxX, maxY float64) {
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+	for _, c := range [][2]float64{{0, 0}, {width, 0}, {0, height}, {width, height}} {
+		x, y := geo.Apply(c[0], c[1])
+		minX, minY = min(minX, x), min(minY, y)
+		maxX, maxY = max(maxX, x), max(maxY, y)
+	}
+	return minX, minY, maxX, maxY
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < epsilon
+}
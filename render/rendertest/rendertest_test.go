@@ -0,0 +1,13 @@
+package rendertest
+
+import (
+	"testing"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/Tsukumogami-Software/go-tiled/render"
+)
+
+func TestOrthogonalEngine(t *testing.T) {
+	m := &tiled.Map{Orientation: "orthogonal"}
+	TestEngine(t, m, func() render.RendererEngine { return &render.OrthogonalRendererEngine{} })
+}
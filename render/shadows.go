@@ -0,0 +1,115 @@
+package render
+
+import (
+	"image/color"
+	"math"
+	"slices"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/colorm"
+)
+
+// ShadowOptions configures the drop shadows drawn under tile objects
+type ShadowOptions struct {
+	// Classes of the objects casting shadows, all tile objects when empty
+	Classes []string
+	// Offset of the shadows from their objects, in pixels
+	OffsetX, OffsetY float64
+	// Radius of the blur of the shadow edges in pixels, 0 for hard shadows
+	Blur float64
+	// Opacity of the shadows from 0 to 1
+	Opacity float32
+	// Color of the shadows, black when nil
+	Color color.Color
+}
+
+// shadowBlurSteps is the number of copies drawn on each side of a shadow to
+// approximate the blur
+const shadowBlurSteps = 2
+
+// UseShadows is used to draw drop shadows under tile objects, in a pass
+// before the objects of each object group. A nil ShadowOptions goes back to
+// drawing no shadows.
+func (r *Renderer) UseShadows(options *ShadowOptions) {
+	r.shadows = options
+}
+
+func (s *ShadowOptions) casts(o *tiled.Object) bool {
+	if len(s.Classes) == 0 {
+		return true
+	}
+	return slices.Contains(s.Classes, o.Class) || (o.Type != "" && slices.Contains(s.Classes, o.Type))
+}
+
+// renderShadows draws the shadows of the tile objects of a group
+func (r *Renderer) renderShadows(layer *tiled.ObjectGroup, objs []*tiled.Object) error {
+	s := r.shadows
+	if s == nil || s.Opacity <= 0 {
+		return nil
+	}
+
+	// The silhouettes of the objects are drawn in the shadow color, several
+	// times around the shadow position when blurred
+	var offsets [][2]float64
+	if s.Blur > 0 {
+		step := s.Blur / shadowBlurSteps
+		for y := -shadowBlurSteps; y <= shadowBlurSteps; y++ {
+			for x := -shadowBlurSteps; x <= shadowBlurSteps; x++ {
+				offsets = append(offsets, [2]float64{float64(x) * step, float64(y) * step})
+			}
+		}
+	} else {
+		offsets = [][2]float64{{0, 0}}
+	}
+
+	// Copies add up, each is faded so the center of the shadow reaches the
+	// opacity
+	opacity := min(1, float64(s.Opacity*layer.Opacity))
+	alpha := 1 - math.Pow(1-opacity, 1/float64(len(offsets)))
+	c := color.NRGBA{A: 0xff}
+	if s.Color != nil {
+		c = color.NRGBAModel.Convert(s.Color).(color.NRGBA)
+	}
+	var cm colorm.ColorM
+	cm.Scale(0, 0, 0, float64(c.A)/0xff*alpha)
+	cm.Translate(float64(c.R)/0xff, float64(c.G)/0xff, float64(c.B)/0xff, 0)
+
+	for _, o := range objs {
+		if !o.Visible || o.GID == 0 || !s.casts(o) {
+			continue
+		}
+
+		tile, err := r.m.TileGIDToTile(o.GID)
+		if err != nil {
+			return err
+		}
+		if r.animator != nil {
+			tile = r.animator.Frame(tile)
+		}
+		img, err := r.getTileImage(tile)
+		if err != nil {
+			return err
+		}
+
+		geom, bounds := tileObjectGeoM(o, tile, img)
+		bounds.Min.X += s.OffsetX
+		bounds.Max.X += s.OffsetX
+		bounds.Min.Y += s.OffsetY
+		bounds.Max.Y += s.OffsetY
+		if !r.onCanvas(bounds, s.Blur) {
+			continue
+		}
+
+		for _, off := range offsets {
+			g := geom
+			g.Translate(s.OffsetX+off[0], s.OffsetY+off[1])
+			g.Concat(r.view)
+			colorm.DrawImage(r.Result, img.(*ebiten.Image), cm, &colorm.DrawImageOptions{
+				GeoM:   g,
+				Filter: r.filter,
+			})
+		}
+	}
+	return nil
+}
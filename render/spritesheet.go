@@ -0,0 +1,198 @@
+package render
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Spritesheet is an image holding the tiles used by a map
+type Spritesheet struct {
+	Image *ebiten.Image
+	// Area of the image holding each tile, by GID without flip flags
+	Regions map[uint32]image.Rectangle
+
+	tiles map[uint32]*tiled.LayerTile
+}
+
+// UsedTilesSpritesheet packs every distinct tile referenced by the tile
+// layers and tile objects of the map, including the frames of animated
+// tiles, into a single image, leaving padding pixels between tiles.
+func (r *Renderer) UsedTilesSpritesheet(padding int) (*Spritesheet, error) {
+	tiles := map[uint32]*tiled.LayerTile{}
+	add := func(tile *tiled.LayerTile) {
+		if tile == nil || tile.IsNil() {
+			return
+		}
+		gid := tile.Tileset.FirstGID + tile.ID
+		if _, ok := tiles[gid]; ok {
+			return
+		}
+		tiles[gid] = &tiled.LayerTile{ID: tile.ID, Tileset: tile.Tileset}
+		if t := tile.Tileset.Tile(tile.ID); t != nil {
+			for _, f := range t.Animation {
+				gid := tile.Tileset.FirstGID + f.TileID
+				if _, ok := tiles[gid]; !ok {
+					tiles[gid] = &tiled.LayerTile{ID: f.TileID, Tileset: tile.Tileset}
+				}
+			}
+		}
+	}
+
+	var walk func(layers []*tiled.Layer, objectGroups []*tiled.ObjectGroup, groups []*tiled.Group) error
+	walk = func(layers []*tiled.Layer, objectGroups []*tiled.ObjectGroup, groups []*tiled.Group) error {
+		for _, l := range layers {
+			for _, tile := range l.Tiles {
+				add(tile)
+			}
+		}
+		for _, g := range objectGroups {
+			for _, o := range g.Objects {
+				if o.GID == 0 {
+					continue
+				}
+				tile, err := r.m.TileGIDToTile(o.GID)
+				if err != nil {
+					return err
+				}
+				add(tile)
+			}
+		}
+		for _, g := range groups {
+			if err := walk(g.Layers, g.ObjectGroups, g.Groups); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(r.m.Layers, r.m.ObjectGroups, r.m.Groups); err != nil {
+		return nil, err
+	}
+
+	gids := make([]uint32, 0, len(tiles))
+	images := map[uint32]image.Image{}
+	area, widest := 0, 0
+	for gid, tile := range tiles {
+		img, err := r.getTileImage(tile)
+		if err != nil {
+			return nil, err
+		}
+		size := img.Bounds().Size()
+		area += (size.X + padding) * (size.Y + padding)
+		widest = max(widest, size.X)
+		gids = append(gids, gid)
+		images[gid] = img
+	}
+
+	// Tallest tiles first, so shelves waste little room
+	sort.Slice(gids, func(i, j int) bool {
+		hi, hj := images[gids[i]].Bounds().Dy(), images[gids[j]].Bounds().Dy()
+		if hi != hj {
+			return hi > hj
+		}
+		return gids[i] < gids[j]
+	})
+
+	sheet := &Spritesheet{Regions: make(map[uint32]image.Rectangle, len(gids)), tiles: tiles}
+	width := max(widest, int(math.Ceil(math.Sqrt(float64(area)))))
+	x, y, rowHeight := 0, 0, 0
+	for _, gid := range gids {
+		size := images[gid].Bounds().Size()
+		if x > 0 && x+size.X > width {
+			x, y, rowHeight = 0, y+rowHeight+padding, 0
+		}
+		sheet.Regions[gid] = image.Rect(x, y, x+size.X, y+size.Y)
+		x += size.X + padding
+		rowHeight = max(rowHeight, size.Y)
+	}
+
+	sheet.Image = ebiten.NewImage(max(1, width), max(1, y+rowHeight))
+	for gid, rect := range sheet.Regions {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Translate(float64(rect.Min.X), float64(rect.Min.Y))
+		sheet.Image.DrawImage(images[gid].(*ebiten.Image), op)
+	}
+
+	return sheet, nil
+}
+
+type texturePackerRect struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type texturePackerSize struct {
+	W int `json:"w"`
+	H int `json:"h"`
+}
+
+type texturePackerFrame struct {
+	Frame            texturePackerRect `json:"frame"`
+	Rotated          bool              `json:"rotated"`
+	Trimmed          bool              `json:"trimmed"`
+	SpriteSourceSize texturePackerRect `json:"spriteSourceSize"`
+	SourceSize       texturePackerSize `json:"sourceSize"`
+}
+
+type texturePackerMeta struct {
+	App     string            `json:"app"`
+	Version string            `json:"version"`
+	Image   string            `json:"image"`
+	Format  string            `json:"format"`
+	Size    texturePackerSize `json:"size"`
+	Scale   string            `json:"scale"`
+}
+
+type texturePackerAtlas struct {
+	Frames map[string]texturePackerFrame `json:"frames"`
+	Meta   texturePackerMeta             `json:"meta"`
+}
+
+// FrameName returns the name of the frame of a tile in TexturePacker JSON
+// files, "tileset_name@firstgid/id". The first GID of the tileset keeps the
+// names of tilesets sharing a name apart.
+func (s *Spritesheet) FrameName(gid uint32) string {
+	tile, ok := s.tiles[gid]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s@%d/%d", tile.Tileset.Name, tile.Tileset.FirstGID, tile.ID)
+}
+
+// WriteTexturePackerJSON writes the regions of the spritesheet in the JSON
+// hash format of TexturePacker, frames being keyed by FrameName. imageName is
+// the file name the spritesheet image is saved as.
+func (s *Spritesheet) WriteTexturePackerJSON(w io.Writer, imageName string) error {
+	size := s.Image.Bounds().Size()
+	atlas := texturePackerAtlas{
+		Frames: make(map[string]texturePackerFrame, len(s.Regions)),
+		Meta: texturePackerMeta{
+			App:     "https://github.com/Tsukumogami-Software/go-tiled",
+			Version: "1.0",
+			Image:   imageName,
+			Format:  "RGBA8888",
+			Size:    texturePackerSize{W: size.X, H: size.Y},
+			Scale:   "1",
+		},
+	}
+	for gid, rect := range s.Regions {
+		width, height := rect.Dx(), rect.Dy()
+		atlas.Frames[s.FrameName(gid)] = texturePackerFrame{
+			Frame:            texturePackerRect{X: rect.Min.X, Y: rect.Min.Y, W: width, H: height},
+			SpriteSourceSize: texturePackerRect{W: width, H: height},
+			SourceSize:       texturePackerSize{W: width, H: height},
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(atlas)
+}
@@ -0,0 +1,22 @@
+package render
+
+import (
+	"testing"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpritesheetFrameName(t *testing.T) {
+	// Tilesets of two maps merged into one share their name
+	a := &tiled.Tileset{Name: "terrain", FirstGID: 1}
+	b := &tiled.Tileset{Name: "terrain", FirstGID: 65}
+	s := &Spritesheet{tiles: map[uint32]*tiled.LayerTile{
+		4:  {ID: 3, Tileset: a},
+		68: {ID: 3, Tileset: b},
+	}}
+
+	assert.Equal(t, "terrain@1/3", s.FrameName(4))
+	assert.Equal(t, "terrain@65/3", s.FrameName(68))
+	assert.Equal(t, "", s.FrameName(5))
+}
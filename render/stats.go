@@ -0,0 +1,54 @@
+package render
+
+import (
+	"time"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+)
+
+// LayerStats holds the counters of a tile layer render
+type LayerStats struct {
+	Name         string
+	TilesDrawn   int
+	TilesSkipped int
+	Duration     time.Duration
+}
+
+// RenderStats holds the counters of the renders done since the Renderer was
+// created or its stats were reset.
+type RenderStats struct {
+	// Number of tiles drawn
+	TilesDrawn int
+	// Number of empty cells skipped
+	TilesSkipped int
+	// Number of tile images found in or missing from the cache
+	CacheHits, CacheMisses int
+	// Number of image files decoded
+	ImagesDecoded int
+	// Counters of each tile layer render, in order
+	Layers []LayerStats
+}
+
+// Stats returns the counters of the renders done so far
+func (r *Renderer) Stats() RenderStats {
+	stats := r.stats
+	stats.Layers = append([]LayerStats(nil), r.stats.Layers...)
+	return stats
+}
+
+// ResetStats sets all render counters back to zero
+func (r *Renderer) ResetStats() {
+	r.stats = RenderStats{}
+}
+
+// addLayerStats adds the counters of a tile layer render
+func (r *Renderer) addLayerStats(layer *tiled.Layer, drawn, skipped int, start time.Time) {
+	r.stats.TilesDrawn += drawn
+	r.stats.TilesSkipped += skipped
+	r.stats.Layers = append(r.stats.Layers, LayerStats{
+		Name:         layer.Name,
+		TilesDrawn:   drawn,
+		TilesSkipped: skipped,
+		Duration:     time.Since(start),
+	})
+}
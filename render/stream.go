@@ -0,0 +1,225 @@
+package render
+
+import (
+	"image"
+	"io/fs"
+	"math"
+	"slices"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+)
+
+// StreamedRegion is a region map loaded by a StreamManager
+type StreamedRegion struct {
+	FileName string
+	// Area covered by the region in the world, in pixels
+	Bounds   image.Rectangle
+	Map      *tiled.Map
+	Renderer *Renderer
+	// Colliders of all tile layers of the region, in world coordinates
+	Colliders []*tiled.Collider
+	lastUsed  int
+}
+
+// StreamManager loads and unloads the renderers of the region maps of a world
+// file, such as the ones written by tiled.WriteRegions, around a moving
+// position. Regions are the maps of the world loaded by tiled.LoadWorld,
+// listed or matched by its patterns. The renderers of all regions share a
+// TilesetCache.
+type StreamManager struct {
+	// Regions closer than Radius pixels to the position are loaded
+	Radius float64
+	// Regions farther than UnloadRadius pixels from the position are
+	// unloaded. Defaults to Radius when lower.
+	UnloadRadius float64
+	// Maximum number of regions kept loaded, regions between Radius and
+	// UnloadRadius being evicted least recently needed first. Zero keeps
+	// them all.
+	MaxLoaded int
+	// OnLoad and OnUnload are called when a region is loaded or unloaded
+	OnLoad, OnUnload func(*StreamedRegion)
+
+	world        *tiled.World
+	loaded       map[*tiled.WorldMap]*StreamedRegion
+	tilesetCache *TilesetCache
+	updates      int
+}
+
+// NewStreamManager creates a manager streaming the regions of the given
+// world file. The maps of the world are loaded right away, their renderers
+// being created and released as the position moves.
+func NewStreamManager(worldFile string, radius float64) (*StreamManager, error) {
+	return NewStreamManagerWithFileSystem(worldFile, radius, nil)
+}
+
+// NewStreamManagerWithFileSystem creates a manager streaming the regions of
+// the given world file with a custom file system.
+func NewStreamManagerWithFileSystem(worldFile string, radius float64, fs fs.FS) (*StreamManager, error) {
+	var options []tiled.LoaderOption
+	if fs != nil {
+		options = append(options, tiled.WithFileSystem(fs))
+	}
+	world, err := tiled.LoadWorld(worldFile, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StreamManager{
+		Radius:       radius,
+		world:        world,
+		loaded:       map[*tiled.WorldMap]*StreamedRegion{},
+		tilesetCache: NewTilesetCache(fs),
+	}, nil
+}
+
+// World returns the world whose regions are streamed
+func (sm *StreamManager) World() *tiled.World {
+	return sm.world
+}
+
+// TilesetCache returns the TilesetCache shared by all regions
+func (sm *StreamManager) TilesetCache() *TilesetCache {
+	return sm.tilesetCache
+}
+
+// distance returns the distance in pixels between a point and a rectangle
+func distance(x, y float64, rect image.Rectangle) float64 {
+	dx := max(float64(rect.Min.X)-x, 0, x-float64(rect.Max.X))
+	dy := max(float64(rect.Min.Y)-y, 0, y-float64(rect.Max.Y))
+	return math.Hypot(dx, dy)
+}
+
+// Update loads the regions around the position x, y in pixels and unloads the
+// ones that went out of range.
+func (sm *StreamManager) Update(x, y float64) error {
+	sm.updates++
+
+	unloadRadius := max(sm.UnloadRadius, sm.Radius)
+
+	for _, e := range sm.world.Maps {
+		d := distance(x, y, e.Bounds())
+		region, loaded := sm.loaded[e]
+
+		switch {
+		case d <= sm.Radius && !loaded:
+			var err error
+			if region, err = sm.load(e); err != nil {
+				return err
+			}
+			region.lastUsed = sm.updates
+		case d <= sm.Radius:
+			region.lastUsed = sm.updates
+		case d > unloadRadius && loaded:
+			sm.unload(e)
+		}
+	}
+
+	if sm.MaxLoaded <= 0 || len(sm.loaded) <= sm.MaxLoaded {
+		return nil
+	}
+
+	// Evicts the regions out of Radius needed the longest time ago
+	var stale []*tiled.WorldMap
+	for e, region := range sm.loaded {
+		if region.lastUsed != sm.updates {
+			stale = append(stale, e)
+		}
+	}
+	slices.SortFunc(stale, func(a, b *tiled.WorldMap) int {
+		return sm.loaded[a].lastUsed - sm.loaded[b].lastUsed
+	})
+	for _, e := range stale {
+		if len(sm.loaded) <= sm.MaxLoaded {
+			break
+		}
+		sm.unload(e)
+	}
+
+	return nil
+}
+
+func (sm *StreamManager) load(e *tiled.WorldMap) (*StreamedRegion, error) {
+	m := e.Map
+	r, err := NewRendererWithCache(m, sm.tilesetCache)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.Preload(); err != nil {
+		return nil, err
+	}
+
+	region := &StreamedRegion{
+		FileName: e.FileName,
+		Bounds:   e.Bounds(),
+		Map:      m,
+		Renderer: r,
+	}
+
+	for _, l := range m.Layers {
+		for _, c := range l.Colliders() {
+			c.Bounds.Min.X += float64(e.X)
+			c.Bounds.Min.Y += float64(e.Y)
+			c.Bounds.Max.X += float64(e.X)
+			c.Bounds.Max.Y += float64(e.Y)
+			for _, o := range c.Objects {
+				o.X += float64(e.X)
+				o.Y += float64(e.Y)
+			}
+			region.Colliders = append(region.Colliders, c)
+		}
+	}
+
+	sm.loaded[e] = region
+	if sm.OnLoad != nil {
+		sm.OnLoad(region)
+	}
+	return region, nil
+}
+
+func (sm *StreamManager) unload(e *tiled.WorldMap) {
+	region := sm.loaded[e]
+	delete(sm.loaded, e)
+
+	if sm.OnUnload != nil {
+		sm.OnUnload(region)
+	}
+	region.Renderer.Result.Deallocate()
+}
+
+// Loaded returns the loaded regions, in the order of the world file
+func (sm *StreamManager) Loaded() []*StreamedRegion {
+	var res []*StreamedRegion
+	for _, e := range sm.world.Maps {
+		if region, ok := sm.loaded[e]; ok {
+			res = append(res, region)
+		}
+	}
+	return res
+}
+
+// RegionAt returns the loaded region covering the position x, y in pixels,
+// or nil if there is none.
+func (sm *StreamManager) RegionAt(x, y float64) *StreamedRegion {
+	for _, region := range sm.Loaded() {
+		if distance(x, y, region.Bounds) == 0 {
+			return region
+		}
+	}
+	return nil
+}
+
+// Colliders returns the colliders of all loaded regions
+func (sm *StreamManager) Colliders() []*tiled.Collider {
+	var res []*tiled.Collider
+	for _, region := range sm.Loaded() {
+		res = append(res, region.Colliders...)
+	}
+	return res
+}
+
+// Close unloads all regions
+func (sm *StreamManager) Close() {
+	for e := range sm.loaded {
+		sm.unload(e)
+	}
+}
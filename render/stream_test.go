@@ -0,0 +1,41 @@
+package render
+
+import (
+	"image"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamManagerPatterns(t *testing.T) {
+	tmx := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="4" height="4" tilewidth="8" tileheight="8">
+</map>`)
+	fsys := fstest.MapFS{
+		"world/islands.world": {Data: []byte(`{
+  "maps": [{"fileName": "home.tmx", "x": -32, "y": 0}],
+  "patterns": [{"regexp": "island_(\\d+)_(\\d+)\\.tmx", "multiplierX": 32, "multiplierY": 32}],
+  "type": "world"
+}`)},
+		"world/home.tmx":       {Data: tmx},
+		"world/island_1_0.tmx": {Data: tmx},
+	}
+
+	sm, err := NewStreamManagerWithFileSystem("world/islands.world", 0, fsys)
+	assert.NoError(t, err)
+	defer sm.Close()
+	assert.Len(t, sm.World().Maps, 2)
+
+	// Regions matched by patterns are streamed like listed ones
+	assert.NoError(t, sm.Update(40, 8))
+	if region := sm.RegionAt(40, 8); assert.NotNil(t, region) {
+		assert.Equal(t, "island_1_0.tmx", region.FileName)
+		assert.Equal(t, image.Rect(32, 0, 64, 32), region.Bounds)
+	}
+	assert.Len(t, sm.Loaded(), 1)
+
+	assert.NoError(t, sm.Update(-16, 8))
+	assert.Equal(t, "home.tmx", sm.Loaded()[0].FileName)
+	assert.Len(t, sm.Loaded(), 1)
+}
@@ -0,0 +1,55 @@
+package render
+
+import (
+	"fmt"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+)
+
+// UnsupportedFeatures lists the parts of the map the renderer can't draw, so
+// they can be reported instead of being silently dropped. Returned errors wrap
+// ErrUnsupportedOrientation, ErrUnsupportedRenderOrder or ErrUnsupportedFeature.
+func UnsupportedFeatures(m *tiled.Map) []error {
+	var res []error
+
+	if !hasEngine(m.Orientation) {
+		res = append(res, fmt.Errorf("%w: %q", ErrUnsupportedOrientation, m.Orientation))
+	}
+	if m.RenderOrder != "" && m.RenderOrder != "right-down" {
+		res = append(res, fmt.Errorf("%w: %q", ErrUnsupportedRenderOrder, m.RenderOrder))
+	}
+
+	res = append(res, unsupportedLayerFeatures(m.Layers, m.ObjectGroups, m.Groups)...)
+	return res
+}
+
+func unsupportedLayerFeatures(layers []*tiled.Layer, objectGroups []*tiled.ObjectGroup, groups []*tiled.Group) []error {
+	var res []error
+
+	for _, l := range layers {
+		if l.OffsetX != 0 || l.OffsetY != 0 {
+			res = append(res, fmt.Errorf("%w: offset of layer %q is ignored", ErrUnsupportedFeature, l.Name))
+		}
+		if l.TintColor != nil {
+			res = append(res, fmt.Errorf("%w: tint color of layer %q is ignored", ErrUnsupportedFeature, l.Name))
+		}
+	}
+	for _, g := range objectGroups {
+		if g.TintColor != nil {
+			res = append(res, fmt.Errorf("%w: tint color of %q is ignored", ErrUnsupportedFeature, g.Name))
+		}
+		for _, o := range g.Objects {
+			if o.Text != nil {
+				res = append(res, fmt.Errorf("%w: text object %d of %q is not rendered", ErrUnsupportedFeature, o.ID, g.Name))
+			}
+		}
+	}
+	for _, g := range groups {
+		if g.TintColor != nil && (len(g.Layers) > 0 || len(g.ObjectGroups) > 0 || len(g.Groups) > 0) {
+			res = append(res, fmt.Errorf("%w: tint color of group %q is only applied to its image layers", ErrUnsupportedFeature, g.Name))
+		}
+		res = append(res, unsupportedLayerFeatures(g.Layers, g.ObjectGroups, g.Groups)...)
+	}
+
+	return res
+}
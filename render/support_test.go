@@ -0,0 +1,40 @@
+package render
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExamplesUnsupportedFeatures(t *testing.T) {
+	for file, unsupported := range map[string]bool{
+		"desert.tmx":                    false,
+		"sewers.tmx":                    false,
+		"isometric_grass_and_water.tmx": true,
+		"hexagonal-mini.tmx":            true,
+	} {
+		t.Run(file, func(t *testing.T) {
+			m, err := tiled.LoadFile(filepath.Join("..", "assets", "examples", file))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			// Orientations without engine are reported, not drawn wrong
+			errs := UnsupportedFeatures(m)
+			if !unsupported {
+				assert.Empty(t, errs)
+				r, err := NewRenderer(m)
+				assert.NoError(t, err)
+				assert.NoError(t, r.RenderVisibleLayers())
+				return
+			}
+			if assert.Len(t, errs, 1) {
+				assert.ErrorIs(t, errs[0], ErrUnsupportedOrientation)
+			}
+			_, err = NewRenderer(m)
+			assert.ErrorIs(t, err, ErrUnsupportedOrientation)
+		})
+	}
+}
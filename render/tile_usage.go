@@ -0,0 +1,102 @@
+package render
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+)
+
+// TileUsage is the number of times a tile was drawn
+type TileUsage struct {
+	// Tileset image, or tileset file for image collection tilesets
+	Tileset string
+	ID      uint32
+	Draws   int
+}
+
+// addDraws adds draw counts to the tile usage
+func (t *TilesetCache) addDraws(draws map[tileKey]int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for k, n := range draws {
+		key := t.key(k.tileset)
+		counts, ok := t.draws[key]
+		if !ok {
+			counts = map[uint32]int{}
+			t.draws[key] = counts
+		}
+		counts[k.id] += n
+	}
+}
+
+// TileUsage returns how many times each tile was drawn by the renderers using
+// the cache, most drawn first, so tilesets can be reordered or packed by
+// hotness. Tiles never drawn are left out.
+func (t *TilesetCache) TileUsage() []TileUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var res []TileUsage
+	for ts, counts := range t.draws {
+		for id, n := range counts {
+			res = append(res, TileUsage{Tileset: ts, ID: id, Draws: n})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Draws != res[j].Draws {
+			return res[i].Draws > res[j].Draws
+		}
+		if res[i].Tileset != res[j].Tileset {
+			return res[i].Tileset < res[j].Tileset
+		}
+		return res[i].ID < res[j].ID
+	})
+	return res
+}
+
+// ResetTileUsage sets all draw counts back to zero
+func (t *TilesetCache) ResetTileUsage() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.draws = map[string]map[uint32]int{}
+}
+
+// WriteTileUsageCSV writes tile usage as CSV, with a tileset, id, draws
+// header.
+func WriteTileUsageCSV(w io.Writer, usage []TileUsage) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"tileset", "id", "draws"}); err != nil {
+		return err
+	}
+	for _, u := range usage {
+		if err := cw.Write([]string{u.Tileset, strconv.FormatUint(uint64(u.ID), 10), strconv.Itoa(u.Draws)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// countDraw counts a tile drawn, for renderers using a TilesetCache
+func (r *Renderer) countDraw(tile *tiled.LayerTile) {
+	if r.tilesetCache == nil {
+		return
+	}
+	if r.draws == nil {
+		r.draws = map[tileKey]int{}
+	}
+	r.draws[tileKey{tile.Tileset, tile.ID}]++
+}
+
+// flushDraws adds the tiles drawn to the usage of the TilesetCache
+func (r *Renderer) flushDraws() {
+	if len(r.draws) == 0 {
+		return
+	}
+	r.tilesetCache.addDraws(r.draws)
+	clear(r.draws)
+}
@@ -1,30 +1,74 @@
 package render
 
 import (
+	"container/list"
+	"fmt"
 	"image"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/Tsukumogami-Software/go-tiled"
 	"github.com/hajimehoshi/ebiten/v2"
 )
 
-// TilesetCache is used to share tileset images between multiple renderers
+// TilesetCache is used to share tileset images between multiple renderers.
+// It is safe for concurrent use, and can be limited to a number of tilesets,
+// evicting the least recently used ones.
 type TilesetCache struct {
-	cache map[string]map[uint32]image.Image
-	fs    fs.FS
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	keys       map[*tiled.Tileset]string
+	draws      map[string]map[uint32]int
+	lru        *list.List
+	maxEntries int
+	fs         fs.FS
+
+	// Images decoded in the background by Prefetch, by path, and in order
+	prefetches     map[string]*prefetch
+	prefetchQueue  []*prefetch
+	prefetchTokens chan struct{}
+}
+
+// tileKey identifies a tile of a loaded tileset
+type tileKey struct {
+	tileset *tiled.Tileset
+	id      uint32
+}
+
+// tilesetCacheEntry holds the tile images of a tileset
+type tilesetCacheEntry struct {
+	key   string
+	tiles map[uint32]image.Image
 }
 
 // NewTilesetCache creates a TilesetCache with an optional filesystem (pointing to an embedded tiled project)
 func NewTilesetCache(fs fs.FS) *TilesetCache {
+	return NewTilesetCacheWithLimit(fs, 0)
+}
+
+// NewTilesetCacheWithLimit creates a TilesetCache keeping at most maxEntries
+// tilesets, evicting the least recently used ones. Zero means no limit.
+func NewTilesetCacheWithLimit(fs fs.FS, maxEntries int) *TilesetCache {
 	return &TilesetCache{
-		cache: map[string]map[uint32]image.Image{},
-		fs:    fs,
+		entries:    map[string]*list.Element{},
+		keys:       map[*tiled.Tileset]string{},
+		draws:      map[string]map[uint32]int{},
+		lru:        list.New(),
+		maxEntries: maxEntries,
+		fs:         fs,
 	}
 }
 
+// Len returns the number of tilesets in the cache
+func (t *TilesetCache) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lru.Len()
+}
+
 func (t *TilesetCache) open(f string) (io.ReadCloser, error) {
 	if t.fs == nil {
 		return os.Open(filepath.FromSlash(f))
@@ -32,39 +76,169 @@ func (t *TilesetCache) open(f string) (io.ReadCloser, error) {
 	return t.fs.Open(filepath.ToSlash(f))
 }
 
-func (t *TilesetCache) cacheTileset(tileset *tiled.Tileset) error {
-	sf, err := t.open(tileset.GetFileFullPath(tileset.Image.Source))
+// tilesetKey identifies a tileset across maps. Tilesets made of a single
+// image are identified by that image and the way it is cut into tiles, so
+// maps embedding the same tileset share it, other tilesets by their location
+// and name.
+func tilesetKey(tileset *tiled.Tileset) string {
+	if tileset.Image != nil {
+		return fmt.Sprintf("%s#%dx%d,spacing=%d,margin=%d,columns=%d,count=%d", imageKey(tileset, tileset.Image),
+			tileset.TileWidth, tileset.TileHeight, tileset.Spacing, tileset.Margin, tileset.Columns, tileset.TileCount)
+	}
+	return tileset.GetFileFullPath(tileset.Name)
+}
+
+func (t *TilesetCache) loadImage(tile *tiled.LayerTile) (*ebiten.Image, error) {
+	img, err := t.decodeImage(tile)
+	if err != nil {
+		return nil, err
+	}
+	return ebiten.NewImageFromImage(img), nil
+}
+
+// decodeImage decodes the image holding the tile, without uploading it
+func (t *TilesetCache) decodeImage(tile *tiled.LayerTile) (image.Image, error) {
+	timg, err := tiledImage(tile)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	sf, err := openImage(t.open, tile.Tileset, timg)
+	if err != nil {
+		return nil, err
 	}
 	defer sf.Close()
 
 	img, _, err := image.Decode(sf)
-	if err != nil {
-		return err
+	return img, err
+}
+
+// tileImages returns the tiles cut from the image of a tileset made of a
+// single image, or the image of a single tile otherwise
+func tileImages(tile *tiled.LayerTile, eimg *ebiten.Image) map[uint32]image.Image {
+	tileset := tile.Tileset
+	if tileset.Image == nil {
+		return map[uint32]image.Image{tile.ID: eimg}
 	}
-	eimg := ebiten.NewImageFromImage(img)
 
 	cache := make(map[uint32]image.Image, tileset.TileCount)
 	for i := uint32(0); i < uint32(tileset.TileCount); i++ {
 		rect := tileset.GetTileRect(i)
 		cache[i] = eimg.SubImage(rect)
 	}
+	return cache
+}
 
-	t.cache[tileset.Name] = cache
-	return nil
+// has reports whether the tile image is cached
+func (t *TilesetCache) has(tile *tiled.LayerTile) bool {
+	_, found, _ := t.get(tile.Tileset, tile.ID)
+	return found
 }
 
-// GetTileImage finds a SubImage from cache
-func (t *TilesetCache) GetTileImage(tile *tiled.LayerTile) (image.Image, error) {
-	cached, ok := t.cache[tile.Tileset.Name]
+// store caches the decoded image file of a tile
+func (t *TilesetCache) store(tile *tiled.LayerTile, eimg *ebiten.Image) {
+	t.put(tilesetKey(tile.Tileset), tileImages(tile, eimg))
+}
+
+// key returns the cache key of a tileset. The lock must be held.
+func (t *TilesetCache) key(ts *tiled.Tileset) string {
+	key, ok := t.keys[ts]
+	if !ok {
+		key = tilesetKey(ts)
+		t.keys[ts] = key
+	}
+	return key
+}
+
+// get finds a tile image, and reports whether its tileset is cached. Keys
+// are remembered per tileset to avoid building paths for every tile.
+func (t *TilesetCache) get(ts *tiled.Tileset, id uint32) (image.Image, bool, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := t.key(ts)
+	elem, ok := t.entries[key]
 	if !ok {
-		err := t.cacheTileset(tile.Tileset)
-		if err != nil {
-			return nil, err
+		return nil, false, false
+	}
+	t.lru.MoveToFront(elem)
+	img, ok := elem.Value.(*tilesetCacheEntry).tiles[id]
+	return img, ok, true
+}
+
+// put adds tile images to the entry of a tileset, evicting the least
+// recently used tilesets if needed
+func (t *TilesetCache) put(key string, tiles map[uint32]image.Image) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	elem, ok := t.entries[key]
+	if ok {
+		t.lru.MoveToFront(elem)
+	} else {
+		elem = t.lru.PushFront(&tilesetCacheEntry{key: key, tiles: map[uint32]image.Image{}})
+		t.entries[key] = elem
+	}
+
+	entry := elem.Value.(*tilesetCacheEntry)
+	for id, img := range tiles {
+		entry.tiles[id] = img
+	}
+
+	for t.maxEntries > 0 && t.lru.Len() > t.maxEntries {
+		oldest := t.lru.Back()
+		t.lru.Remove(oldest)
+		key := oldest.Value.(*tilesetCacheEntry).key
+		delete(t.entries, key)
+		for ts, k := range t.keys {
+			if k == key {
+				delete(t.keys, ts)
+			}
+		}
+	}
+}
+
+// GetTileImage finds a SubImage from cache. Images are decoded outside of
+// the lock, so concurrent misses on the same tileset may decode it twice.
+func (t *TilesetCache) GetTileImage(tile *tiled.LayerTile) (image.Image, error) {
+	img, _, err := t.tileImage(tile)
+	return img, err
+}
+
+// tileImage finds a SubImage from cache, and reports whether it was cached
+func (t *TilesetCache) tileImage(tile *tiled.LayerTile) (image.Image, bool, error) {
+	img, found, cached := t.get(tile.Tileset, tile.ID)
+	if found {
+		return img, true, nil
+	}
+	if cached && tile.Tileset.Image != nil {
+		return nil, false, fmt.Errorf("%w: tile %d of tileset %q", ErrTileImageNotFound, tile.ID, tile.Tileset.Name)
+	}
+
+	var eimg *ebiten.Image
+	if p := t.takePrefetch(tile); p != nil {
+		// Decoded in the background, or being decoded
+		<-p.done
+		if p.err != nil {
+			return nil, false, p.err
+		}
+		eimg = ebiten.NewImageFromImage(p.img)
+		for _, other := range p.tiles {
+			if other.Tileset != tile.Tileset || other.ID != tile.ID {
+				t.store(other, eimg)
+			}
+		}
+	} else {
+		var err error
+		if eimg, err = t.loadImage(tile); err != nil {
+			return nil, false, err
 		}
-		cached = t.cache[tile.Tileset.Name]
 	}
 
-	return cached[tile.ID], nil
+	tiles := tileImages(tile, eimg)
+	t.put(tilesetKey(tile.Tileset), tiles)
+
+	if img, ok := tiles[tile.ID]; ok {
+		return img, false, nil
+	}
+	return nil, false, fmt.Errorf("%w: tile %d of tileset %q", ErrTileImageNotFound, tile.ID, tile.Tileset.Name)
 }
@@ -0,0 +1,34 @@
+package render
+
+import (
+	"image"
+	"testing"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTilesetKey(t *testing.T) {
+	sheet := func(tileWidth, spacing int) *tiled.Tileset {
+		return &tiled.Tileset{
+			Name: "sheet", TileWidth: tileWidth, TileHeight: 16, Spacing: spacing, TileCount: 4, Columns: 2,
+			Image: &tiled.Image{Source: "sheet.png", Width: 32, Height: 32},
+		}
+	}
+	assert.Equal(t, tilesetKey(sheet(16, 0)), tilesetKey(sheet(16, 0)))
+	assert.NotEqual(t, tilesetKey(sheet(16, 0)), tilesetKey(sheet(8, 0)))
+	assert.NotEqual(t, tilesetKey(sheet(16, 0)), tilesetKey(sheet(16, 1)))
+
+	// Tilesets cutting the same sheet differently don't share tiles
+	cache := NewTilesetCache(nil)
+	small, large := sheet(8, 0), sheet(16, 0)
+	tile := image.NewRGBA(image.Rect(0, 0, 8, 16))
+	cache.put(tilesetKey(small), map[uint32]image.Image{0: tile})
+	img, found, _ := cache.get(small, 0)
+	assert.True(t, found)
+	assert.Same(t, tile, img)
+	_, found, cached := cache.get(large, 0)
+	assert.False(t, found)
+	assert.False(t, cached)
+	assert.Equal(t, 1, cache.Len())
+}
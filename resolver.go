@@ -0,0 +1,59 @@
+package tiled
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"time"
+)
+
+// Resolver opens the files referenced by a map, such as external tilesets,
+// templates and images, from the slash separated path they are referenced
+// with. It can read them from encrypted archives, databases or virtual asset
+// systems.
+//
+// Resolver implements fs.FS, so it can also be given to WithFileSystem and to
+// the renderer. Paths are passed as they are, without fs.ValidPath checks.
+type Resolver func(path string) (io.ReadCloser, error)
+
+// Open implements fs.FS
+func (r Resolver) Open(name string) (fs.File, error) {
+	rc, err := r(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return &resolvedFile{ReadCloser: rc, name: path.Base(name)}, nil
+}
+
+// resolvedFile is a file opened by a Resolver, of unknown size
+type resolvedFile struct {
+	io.ReadCloser
+	name string
+}
+
+func (f *resolvedFile) Stat() (fs.FileInfo, error) { return f, nil }
+
+func (f *resolvedFile) Name() string       { return f.name }
+func (f *resolvedFile) Size() int64        { return 0 }
+func (f *resolvedFile) Mode() fs.FileMode  { return 0o444 }
+func (f *resolvedFile) ModTime() time.Time { return time.Time{} }
+func (f *resolvedFile) IsDir() bool        { return false }
+func (f *resolvedFile) Sys() any           { return nil }
+
+// LoadFromBytes loads a map in TMX or JSON format from data, opening the files
+// it references with resolve, which replaces any file system set with
+// WithFileSystem.
+func LoadFromBytes(data []byte, resolve Resolver, options ...LoaderOption) (*Map, error) {
+	l := newLoader(append(options, WithFileSystem(resolve))...)
+
+	json, err := isJSON(bufio.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+	if json {
+		return l.LoadJSONReader("", bytes.NewReader(data))
+	}
+	return l.LoadReader("", bytes.NewReader(data))
+}
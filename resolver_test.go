@@ -0,0 +1,55 @@
+package tiled
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFromBytes(t *testing.T) {
+	files := map[string]string{
+		"tilesets/ground.tsx": `<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="ground" tilewidth="8" tileheight="8" tilecount="2" columns="2">
+<image source="ground.png" width="16" height="8"/>
+</tileset>`,
+	}
+	var resolved []string
+	resolve := Resolver(func(path string) (io.ReadCloser, error) {
+		resolved = append(resolved, path)
+		content, ok := files[path]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+		return io.NopCloser(strings.NewReader(content)), nil
+	})
+
+	m, err := LoadFromBytes([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="1" tilewidth="8" tileheight="8">
+<tileset firstgid="1" source="tilesets/ground.tsx"/>
+<layer id="1" name="Ground" width="2" height="1"><data encoding="csv">1,2</data></layer>
+</map>`), resolve)
+	assert.NoError(t, err)
+	assert.Equal(t, "ground", m.Tilesets[0].Name)
+	assert.Equal(t, []string{"tilesets/ground.tsx"}, resolved)
+
+	data, err := fs.ReadFile(resolve, "tilesets/ground.tsx")
+	assert.NoError(t, err)
+	assert.Equal(t, files["tilesets/ground.tsx"], string(data))
+
+	m, err = LoadFromBytes([]byte(`{
+  "orientation": "orthogonal", "width": 2, "height": 1, "tilewidth": 8, "tileheight": 8,
+  "tilesets": [{"firstgid": 1, "source": "tilesets/ground.tsx"}],
+  "layers": [{"type": "tilelayer", "name": "Ground", "width": 2, "height": 1, "data": [2, 1]}]
+}`), resolve)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), m.Layers[0].Tiles[0].ID)
+
+	_, err = LoadFromBytes([]byte(`<map version="1.10" width="1" height="1" tilewidth="8" tileheight="8">
+<tileset firstgid="1" source="missing.tsx"/>
+<layer id="1" name="Ground" width="1" height="1"><data encoding="csv">1</data></layer>
+</map>`), resolve)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
@@ -0,0 +1,287 @@
+package tiled
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidTerrain error is returned for terrains of tiles not matching the
+// terrain types of their tileset
+var ErrInvalidTerrain = errors.New("tiled: invalid terrain")
+
+// Seam is a mismatch between the Wang colors or terrains of two adjacent
+// tiles, such as grass right against water without a transition tile
+type Seam struct {
+	Layer *Layer
+	// Cell of the tile left of or above the seam
+	X, Y int
+	// Whether the other tile is below the cell rather than right of it
+	Vertical bool
+	// Names of the Wang colors or terrains meeting at the seam, on the side
+	// of the cell and on the other side
+	Label, OtherLabel string
+}
+
+// String describes the seam, with its layer, cell and the labels meeting
+func (s Seam) String() string {
+	dir := "right of"
+	if s.Vertical {
+		dir = "below"
+	}
+	return fmt.Sprintf("layer %q: %s at %d,%d against %s %s it", s.Layer.Name, s.Label, s.X, s.Y, s.OtherLabel, dir)
+}
+
+// FindSeams returns the seams of all tile layers of the map, including the
+// layers of groups. Wang colors are compared within each Wang set both
+// tiles belong to, terrains within the tileset of both tiles. Parts without
+// color or terrain match anything. Neighbours are taken in the tile grid, so
+// seams are only meaningful for orthogonal and isometric maps.
+func (m *Map) FindSeams() ([]Seam, error) {
+	f := &seamFinder{labels: map[seamTile]*tileLabels{}}
+	var res []Seam
+	var walk func(layers []*Layer, groups []*Group) error
+	walk = func(layers []*Layer, groups []*Group) error {
+		for _, l := range layers {
+			seams, err := f.layerSeams(m, l)
+			if err != nil {
+				return err
+			}
+			res = append(res, seams...)
+		}
+		for _, g := range groups {
+			if err := walk(g.Layers, g.Groups); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(m.Layers, m.Groups); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Seams returns the seams between the tiles of the layer, see Map.FindSeams
+func (l *Layer) Seams() ([]Seam, error) {
+	if l._map == nil {
+		return nil, ErrInvalidDecodedTileCount
+	}
+	f := &seamFinder{labels: map[seamTile]*tileLabels{}}
+	return f.layerSeams(l._map, l)
+}
+
+// wangLabels are the names of the colors or terrains at each WangPosition
+// of a tile, empty where unset
+type wangLabels [8]string
+
+// tileLabels holds the labels of a tile, by Wang set or tileset for terrains
+type tileLabels struct {
+	sets   []any
+	labels []wangLabels
+}
+
+// seamTile identifies a tile of a tileset with its flips
+type seamTile struct {
+	ts                             *Tileset
+	id                             uint32
+	diagonal, horizontal, vertical bool
+}
+
+// seamFinder caches the labels of the tiles met
+type seamFinder struct {
+	labels map[seamTile]*tileLabels
+}
+
+func (f *seamFinder) layerSeams(m *Map, l *Layer) ([]Seam, error) {
+	if len(l.Tiles) != m.Width*m.Height {
+		return nil, ErrInvalidDecodedTileCount
+	}
+
+	var res []Seam
+	for y := 0; y < m.Height; y++ {
+		for x := 0; x < m.Width; x++ {
+			a, err := f.tileLabels(l.Tiles[y*m.Width+x])
+			if err != nil {
+				return nil, err
+			}
+			if a == nil {
+				continue
+			}
+			if x+1 < m.Width {
+				b, err := f.tileLabels(l.Tiles[y*m.Width+x+1])
+				if err != nil {
+					return nil, err
+				}
+				if label, other, ok := mismatch(a, b, horizontalSeam); !ok {
+					res = append(res, Seam{Layer: l, X: x, Y: y, Label: label, OtherLabel: other})
+				}
+			}
+			if y+1 < m.Height {
+				b, err := f.tileLabels(l.Tiles[(y+1)*m.Width+x])
+				if err != nil {
+					return nil, err
+				}
+				if label, other, ok := mismatch(a, b, verticalSeam); !ok {
+					res = append(res, Seam{Layer: l, X: x, Y: y, Vertical: true, Label: label, OtherLabel: other})
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// Positions facing each other across a seam, on the side of the first tile
+// and the side of the second
+var (
+	horizontalSeam = [3][2]WangPosition{{TopRight, TopLeft}, {Right, Left}, {BottomRight, BottomLeft}}
+	verticalSeam   = [3][2]WangPosition{{BottomLeft, TopLeft}, {Bottom, Top}, {BottomRight, TopRight}}
+)
+
+// mismatch compares the labels of two tiles facing each other, and returns
+// the first pair differing
+func mismatch(a, b *tileLabels, facing [3][2]WangPosition) (string, string, bool) {
+	if b == nil {
+		return "", "", true
+	}
+	for i, set := range a.sets {
+		for j, other := range b.sets {
+			if set != other {
+				continue
+			}
+			for _, pos := range facing {
+				la, lb := a.labels[i][pos[0]], b.labels[j][pos[1]]
+				if la != "" && lb != "" && la != lb {
+					return la, lb, false
+				}
+			}
+		}
+	}
+	return "", "", true
+}
+
+// tileLabels returns the labels of a layer tile with its flips applied, nil
+// for tiles without any
+func (f *seamFinder) tileLabels(tile *LayerTile) (*tileLabels, error) {
+	if tile.IsNil() {
+		return nil, nil
+	}
+	key := seamTile{tile.Tileset, tile.ID, tile.DiagonalFlip, tile.HorizontalFlip, tile.VerticalFlip}
+	if labels, ok := f.labels[key]; ok {
+		return labels, nil
+	}
+
+	var labels *tileLabels
+	if key.diagonal || key.horizontal || key.vertical {
+		base, err := f.tileLabels(&LayerTile{ID: tile.ID, Tileset: tile.Tileset})
+		if err != nil {
+			return nil, err
+		}
+		if base != nil {
+			labels = &tileLabels{sets: base.sets, labels: make([]wangLabels, len(base.labels))}
+			for i, l := range base.labels {
+				labels.labels[i] = flipLabels(l, tile)
+			}
+		}
+	} else {
+		var err error
+		if labels, err = baseTileLabels(tile.Tileset, tile.ID); err != nil {
+			return nil, err
+		}
+	}
+	f.labels[key] = labels
+	return labels, nil
+}
+
+// Where the part at each WangPosition goes when a tile is flipped
+var (
+	diagonalFlip   = [8]WangPosition{Left, BottomLeft, Bottom, BottomRight, Right, TopRight, Top, TopLeft}
+	horizontalFlip = [8]WangPosition{Top, TopLeft, Left, BottomLeft, Bottom, BottomRight, Right, TopRight}
+	verticalFlip   = [8]WangPosition{Bottom, BottomRight, Right, TopRight, Top, TopLeft, Left, BottomLeft}
+)
+
+// flipLabels moves labels like the flips of the tile do, the diagonal flip
+// being applied first like Tiled does
+func flipLabels(l wangLabels, tile *LayerTile) wangLabels {
+	apply := func(l wangLabels, to [8]WangPosition) wangLabels {
+		var res wangLabels
+		for i, label := range l {
+			res[to[i]] = label
+		}
+		return res
+	}
+	if tile.DiagonalFlip {
+		l = apply(l, diagonalFlip)
+	}
+	if tile.HorizontalFlip {
+		l = apply(l, horizontalFlip)
+	}
+	if tile.VerticalFlip {
+		l = apply(l, verticalFlip)
+	}
+	return l
+}
+
+// baseTileLabels returns the labels of a tile of a tileset, from its Wang
+// sets and terrains
+func baseTileLabels(ts *Tileset, id uint32) (*tileLabels, error) {
+	res := &tileLabels{}
+	for _, ws := range ts.WangSets {
+		for _, wt := range ws.WangTiles {
+			if wt.TileID != id {
+				continue
+			}
+			labels, err := wangTileLabels(ws, wt)
+			if err != nil {
+				return nil, err
+			}
+			res.sets = append(res.sets, ws)
+			res.labels = append(res.labels, labels)
+			break
+		}
+	}
+
+	if t := ts.Tile(id); t != nil && t.Terrain != "" {
+		// Terrains are given for the corners top left, top right, bottom
+		// left and bottom right
+		var labels wangLabels
+		for i, s := range strings.Split(t.Terrain, ",") {
+			if s == "" || i >= 4 {
+				continue
+			}
+			n, err := strconv.Atoi(s)
+			if err != nil || n < 0 || n >= len(ts.TerrainTypes) {
+				return nil, fmt.Errorf("%w %q of tile %d of tileset %q", ErrInvalidTerrain, t.Terrain, id, ts.Name)
+			}
+			labels[[4]WangPosition{TopLeft, TopRight, BottomLeft, BottomRight}[i]] = ts.TerrainTypes[n].Name
+		}
+		res.sets = append(res.sets, ts)
+		res.labels = append(res.labels, labels)
+	}
+
+	if len(res.sets) == 0 {
+		return nil, nil
+	}
+	return res, nil
+}
+
+// wangTileLabels returns the names of the colors of a Wang tile, colors
+// without a name being named after their index
+func wangTileLabels(ws *WangSet, wt *WangTile) (wangLabels, error) {
+	var labels wangLabels
+	id, err := ws.wangID(wt)
+	if err != nil {
+		return labels, err
+	}
+	for i, n := range id {
+		if n == 0 {
+			continue
+		}
+		labels[i] = ws.WangColors[n-1].Name
+		if labels[i] == "" {
+			labels[i] = "#" + strconv.Itoa(n)
+		}
+	}
+	return labels, nil
+}
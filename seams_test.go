@@ -0,0 +1,47 @@
+package tiled
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindSeams(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="3" height="2" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="terrain" tilewidth="16" tileheight="16" tilecount="3" columns="3">
+<wangsets>
+<wangset name="ground" type="corner" tile="-1">
+<wangcolor name="grass" color="#00ff00" tile="0" probability="1"/>
+<wangcolor name="water" color="#0000ff" tile="1" probability="1"/>
+<wangtile tileid="0" wangid="0,1,0,1,0,1,0,1"/>
+<wangtile tileid="1" wangid="0,2,0,2,0,2,0,2"/>
+<wangtile tileid="2" wangid="0,2,0,2,0,1,0,1"/>
+</wangset>
+</wangsets>
+</tileset>
+<layer id="1" name="Ground" width="3" height="2">
+<data encoding="csv">1,3,2,2,2147483651,1</data>
+</layer>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+
+	seams, err := m.FindSeams()
+	assert.NoError(t, err)
+	l := m.Layers[0]
+	assert.Equal(t, []Seam{
+		{Layer: l, X: 0, Y: 0, Vertical: true, Label: "grass", OtherLabel: "water"},
+		{Layer: l, X: 1, Y: 0, Vertical: true, Label: "grass", OtherLabel: "water"},
+		{Layer: l, X: 2, Y: 0, Vertical: true, Label: "water", OtherLabel: "grass"},
+	}, seams)
+	assert.Equal(t, `layer "Ground": grass at 0,0 against water below it`, seams[0].String())
+
+	// Without the flip, water ends up right against grass
+	l.Tiles[4].HorizontalFlip = false
+	seams, err = l.Seams()
+	assert.NoError(t, err)
+	assert.Contains(t, seams, Seam{Layer: l, X: 0, Y: 1, Label: "water", OtherLabel: "grass"})
+}
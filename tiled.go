@@ -23,6 +23,8 @@ SOFTWARE.
 package tiled
 
 import (
+	"bufio"
+	"encoding/json"
 	"encoding/xml"
 	"io"
 	"io/fs"
@@ -31,18 +33,36 @@ import (
 )
 
 // LoadReader function loads tiled map in TMX format from io.Reader
-// baseDir is used for loading additional tile data, current directory is used if empty
+// baseDir is used for loading additional tile data, current directory is used if empty.
+// Maps in the JSON format are recognized and loaded like LoadJSONReader does.
+// Maps received over the network or generated in memory resolve their relative
+// tileset, template and image paths against baseDir, opened from the file system
+// set with WithFileSystem if any.
 func LoadReader(baseDir string, r io.Reader, options ...LoaderOption) (*Map, error) {
 	l := newLoader(options...)
 	return l.LoadReader(baseDir, r)
 }
 
-// LoadFile function loads tiled map in TMX format from file
+// LoadFile function loads tiled map in TMX format from file, or in the JSON
+// format like LoadJSONFile
 func LoadFile(fileName string, options ...LoaderOption) (*Map, error) {
 	l := newLoader(options...)
 	return l.LoadFile(fileName)
 }
 
+// LoadJSONReader function loads tiled map in the JSON format (.tmj) from io.Reader
+// baseDir is used for loading additional tile data, current directory is used if empty
+func LoadJSONReader(baseDir string, r io.Reader, options ...LoaderOption) (*Map, error) {
+	l := newLoader(options...)
+	return l.LoadJSONReader(baseDir, r)
+}
+
+// LoadJSONFile function loads tiled map in the JSON format (.tmj) from file
+func LoadJSONFile(fileName string, options ...LoaderOption) (*Map, error) {
+	l := newLoader(options...)
+	return l.LoadJSONFile(fileName)
+}
+
 // LoadTilesetReader loads a tileset from an io.Reader.
 // baseDir is used to locate relative paths to additional tileset data; default
 // is currend directory if empty.
@@ -64,6 +84,38 @@ type loader struct {
 	//
 	// A nil FileSystem uses the local file system.
 	FileSystem fs.FS
+
+	// Layers and objects with any of these bool properties set are removed
+	// from loaded maps.
+	excludedProperties []string
+
+	// Bits of tile GIDs kept, all when zero
+	gidMask uint32
+
+	// Called for each problem tolerated while loading
+	warn func(error)
+
+	// How unknown and inconsistent data is handled
+	mode ParseMode
+
+	// Values of ${VAR} placeholders, also substituted in paths when
+	// pathVariables is set
+	variables     map[string]string
+	pathVariables bool
+
+	// Applied in order to loaded maps
+	transforms []namedTransform
+
+	// Custom types of class properties
+	propertyTypes PropertyTypes
+
+	// External tilesets shared with other loads
+	tilesets *TilesetRegistry
+
+	// File being loaded, and problems tolerated while loading it, see
+	// forMap
+	file     string
+	warnings []ParseWarning
 }
 
 // LoaderOption is used with LoadReader and LoadFile functions to pass additional options
@@ -96,6 +148,23 @@ func WithFileSystem(fileSystem fs.FS) LoaderOption {
 // LoadReader function loads tiled map in TMX format from io.Reader
 // baseDir is used for loading additional tile data, current directory is used if empty
 func (l *loader) LoadReader(baseDir string, r io.Reader) (*Map, error) {
+	return l.forMap("").loadReader(baseDir, r)
+}
+
+func (l *loader) loadReader(baseDir string, r io.Reader) (*Map, error) {
+	br := bufio.NewReader(r)
+	isJSON, err := isJSON(br)
+	if err != nil {
+		return nil, err
+	}
+	if isJSON {
+		return l.loadJSONReader(baseDir, br)
+	}
+
+	r, err = checkDocument[Map](l, br)
+	if err != nil {
+		return nil, err
+	}
 	d := xml.NewDecoder(r)
 
 	m := &Map{
@@ -106,6 +175,13 @@ func (l *loader) LoadReader(baseDir string, r io.Reader) (*Map, error) {
 		return nil, err
 	}
 
+	if len(l.excludedProperties) > 0 {
+		m.Exclude(l.excludedProperties...)
+	}
+	if err := l.transform(m); err != nil {
+		return nil, err
+	}
+
 	return m, nil
 }
 
@@ -118,7 +194,55 @@ func (l *loader) LoadFile(fileName string) (*Map, error) {
 	defer f.Close()
 
 	dir := filepath.Dir(fileName)
-	return l.LoadReader(dir, f)
+	m, err := l.forMap(fileName).loadReader(dir, f)
+	return m, fileError(fileName, err)
+}
+
+// LoadJSONReader function loads tiled map in the JSON format (.tmj) from io.Reader
+// baseDir is used for loading additional tile data, current directory is used if empty
+func (l *loader) LoadJSONReader(baseDir string, r io.Reader) (*Map, error) {
+	return l.forMap("").loadJSONReader(baseDir, r)
+}
+
+func (l *loader) loadJSONReader(baseDir string, r io.Reader) (*Map, error) {
+	r, err := checkDocument[Map](l, r)
+	if err != nil {
+		return nil, err
+	}
+	var jm jsonMap
+	if err := json.NewDecoder(r).Decode(&jm); err != nil {
+		return nil, err
+	}
+
+	m, err := jm.toMap(l, baseDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.decode(); err != nil {
+		return nil, err
+	}
+
+	if len(l.excludedProperties) > 0 {
+		m.Exclude(l.excludedProperties...)
+	}
+	if err := l.transform(m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// LoadJSONFile function loads tiled map in the JSON format (.tmj) from file
+func (l *loader) LoadJSONFile(fileName string) (*Map, error) {
+	f, err := l.open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	dir := filepath.Dir(fileName)
+	m, err := l.forMap(fileName).loadJSONReader(dir, f)
+	return m, fileError(fileName, err)
 }
 
 // LoadTilesetFile loads a tileset in TSX format from a file.
@@ -130,19 +254,27 @@ func (l *loader) LoadTilesetFile(fileName string) (*Tileset, error) {
 	defer f.Close()
 
 	dir := filepath.Dir(fileName)
-	return l.LoadTilesetReader(dir, f)
+	t, err := l.LoadTilesetReader(dir, f)
+	return t, fileError(fileName, err)
 }
 
-// LoadTilesetReader loads a .tsx file into a Tileset structure
+// LoadTilesetReader loads a .tsx or .tsj file into a Tileset structure
 func (l *loader) LoadTilesetReader(baseDir string, r io.Reader) (*Tileset, error) {
-	d := xml.NewDecoder(r)
-
 	t := &Tileset{
 		baseDir: baseDir,
 	}
-	if err := d.Decode(t); err != nil {
+	r, err := checkDocument[Tileset](l, r)
+	if err != nil {
+		return nil, err
+	}
+	if err := decodeTileset(r, t); err != nil {
+		return nil, err
+	}
+	if err := l.checkVersion("tileset "+t.Name, t.Version, t.TiledVersion); err != nil {
 		return nil, err
 	}
+	l.prepareTileset(t)
+	l.checkDeprecated(t)
 
 	t.SourceLoaded = true
 	return t, nil
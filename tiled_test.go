@@ -26,6 +26,8 @@ import (
 	"bytes"
 	"embed"
 	"encoding/xml"
+	"errors"
+	"image"
 	"image/color"
 	"io/fs"
 	"os"
@@ -71,6 +73,17 @@ func TestLoadReader(t *testing.T) {
 	assert.Len(t, m.Layers, 1)
 }
 
+func TestLoadReaderJSON(t *testing.T) {
+	r := bytes.NewBufferString(` {"type": "map", "orientation": "orthogonal", "width": 1, "height": 1, "tilewidth": 16, "tileheight": 16,
+"layers": [{"type": "tilelayer", "id": 1, "name": "Ground", "width": 1, "height": 1, "data": [0]}]}`)
+	m, err := LoadReader(GetAssetsDirectory(), r)
+
+	assert.NoError(t, err)
+	if assert.NotNil(t, m) {
+		assert.Len(t, m.Layers, 1)
+	}
+}
+
 func TestLoadReaderError(t *testing.T) {
 	r := bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
 <map version="1.2" tiledversion="1.2.1" orientation="orthogonal" renderorder="right-down" width="4" height="4" tilewidth="16" tileheight="16" infinite="0" nextlayerid="2" nextobjectid="2">
@@ -104,6 +117,98 @@ func TestLoadFile(t *testing.T) {
 	assert.Equal(t, true, m.ObjectGroups[0].Objects[0].Visible)
 }
 
+func TestTiledExamples(t *testing.T) {
+	tcs := []struct {
+		file        string
+		orientation string
+		tileWidth   int
+		tileHeight  int
+		layers      int
+		lastTileID  uint32
+		// Checks the features the example shows
+		check func(t *testing.T, m *Map)
+	}{
+		{file: "desert.tmx", orientation: "orthogonal", tileWidth: 32, tileHeight: 32, layers: 1, lastTileID: 2,
+			check: func(t *testing.T, m *Map) {
+				// Tiles are cut from an image with margin and spacing
+				ts := m.Tilesets[0]
+				assert.Equal(t, 1, ts.Margin)
+				assert.Equal(t, 1, ts.Spacing)
+				assert.Equal(t, image.Rect(34, 34, 66, 66), ts.GetTileRect(9))
+				assert.Equal(t, image.Rect(232, 166, 264, 198), ts.GetTileRect(47))
+				if assert.Len(t, ts.TerrainTypes, 4) {
+					assert.Equal(t, "Brick", ts.TerrainTypes[1].Name)
+					assert.Equal(t, uint32(9), ts.TerrainTypes[1].Tile)
+				}
+			}},
+		{file: "isometric_grass_and_water.tmx", orientation: "isometric", tileWidth: 64, tileHeight: 32, layers: 1, lastTileID: 10,
+			check: func(t *testing.T, m *Map) {
+				// Tiles are twice as high as the grid and drawn lower
+				ts := m.Tilesets[0]
+				assert.Equal(t, 64, ts.TileHeight)
+				assert.Equal(t, &TilesetTileOffset{X: 0, Y: 16}, ts.TileOffset)
+			}},
+		{file: "hexagonal-mini.tmx", orientation: "hexagonal", tileWidth: 14, tileHeight: 12, layers: 1, lastTileID: 4,
+			check: func(t *testing.T, m *Map) {
+				assert.Equal(t, 6, m.HexSideLength)
+				assert.Equal(t, AxisY, m.StaggerAxis)
+				assert.Equal(t, StaggerIndexOdd, m.StaggerIndex)
+				// Rows cycle through all tiles of the tileset
+				for i, id := range []uint32{0, 3, 6, 1, 4, 7} {
+					assert.Equal(t, id, m.Layers[0].Tiles[i].ID)
+				}
+			}},
+		{file: "sewers.tmx", orientation: "orthogonal", tileWidth: 24, tileHeight: 24, layers: 2, lastTileID: 7,
+			check: func(t *testing.T, m *Map) {
+				// Magenta is the transparent color of the tileset image
+				assert.Equal(t, NewHexColor(255, 0, 255, 255), *m.Tilesets[0].Image.Trans)
+
+				// Walls, stored uncompressed, surround the gzip compressed floor
+				walls := m.Layers[1]
+				assert.Equal(t, "Walls", walls.Name)
+				assert.Equal(t, uint32(8), walls.Tiles[0].ID)
+				assert.True(t, walls.Tiles[m.Width+1].IsNil())
+				assert.False(t, m.Layers[0].Tiles[m.Width+1].IsNil())
+
+				if assert.Len(t, m.ObjectGroups, 1) && assert.Len(t, m.ObjectGroups[0].Objects, 2) {
+					objects := m.ObjectGroups[0].Objects
+					assert.Equal(t, "Entrance", objects[0].Name)
+					assert.Equal(t, "spawn", objects[0].Type)
+					assert.Equal(t, 24.0, objects[0].Width)
+					if assert.Len(t, objects[1].Polygons, 1) {
+						assert.Len(t, *objects[1].Polygons[0].Points, 4)
+					}
+				}
+			}},
+	}
+
+	for _, tc := range tcs {
+		t.Run(tc.file, func(t *testing.T) {
+			m, err := LoadFile(filepath.Join(GetAssetsDirectory(), "examples", tc.file))
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			assert.Equal(t, tc.orientation, m.Orientation)
+			assert.Equal(t, tc.tileWidth, m.TileWidth)
+			assert.Equal(t, tc.tileHeight, m.TileHeight)
+			assert.Len(t, m.Layers, tc.layers)
+			assert.Len(t, m.Tilesets, 1)
+			assert.NotNil(t, m.Tilesets[0].Image)
+
+			for _, l := range m.Layers {
+				assert.Len(t, l.Tiles, m.Width*m.Height)
+			}
+			tile := m.Layers[0].Tiles[len(m.Layers[0].Tiles)-1]
+			assert.False(t, tile.IsNil())
+			assert.Equal(t, tc.lastTileID, tile.ID)
+			assert.Same(t, m.Tilesets[0], tile.Tileset)
+
+			tc.check(t, m)
+		})
+	}
+}
+
 func TestLoadFileError(t *testing.T) {
 	m, err := LoadFile(filepath.Join(GetAssetsDirectory(), "invalid.tmx"))
 
@@ -133,6 +238,17 @@ func TestExternalTilesetImageLoaded(t *testing.T) {
 	}
 }
 
+func TestInitTilesets(t *testing.T) {
+	m, err := LoadFile(filepath.Join(GetAssetsDirectory(), "test_tileobject.tmx"))
+	assert.NoError(t, err)
+
+	assert.NoError(t, m.InitTilesets())
+	for _, ts := range m.Tilesets {
+		assert.True(t, ts.SourceLoaded)
+		assert.NotNil(t, ts.tiles)
+	}
+}
+
 func TestImageLayer(t *testing.T) {
 	m, err := LoadFile(filepath.Join(GetAssetsDirectory(), "imagelayer.tmx"))
 
@@ -188,6 +304,121 @@ func TestGroup(t *testing.T) {
 	assert.Len(t, c.Groups, 0)
 }
 
+func TestLayersByClass(t *testing.T) {
+	r := bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="2" tilewidth="16" tileheight="16">
+<layer id="1" name="Ground" class="floor" width="2" height="2">
+<data encoding="csv">0,0,0,0</data>
+</layer>
+<layer id="2" name="Walls" class="collision" width="2" height="2">
+<data encoding="csv">0,0,0,0</data>
+</layer>
+<group id="3" name="Interior">
+<layer id="4" name="Furniture" class="collision" visible="0" width="2" height="2">
+<data encoding="csv">0,0,0,0</data>
+</layer>
+</group>
+</map>`)
+	m, err := LoadReader(GetAssetsDirectory(), r)
+	assert.NoError(t, err)
+
+	layers := m.LayersByClass("collision")
+	if assert.Len(t, layers, 2) {
+		assert.Equal(t, "Walls", layers[0].Name)
+		assert.Equal(t, "Furniture", layers[1].Name)
+	}
+	assert.Len(t, layers.Visible(), 1)
+
+	layers.SetVisible(false)
+	assert.Len(t, layers.Visible(), 0)
+	assert.True(t, m.Layers[0].Visible)
+
+	assert.Len(t, m.LayersByClass("missing"), 0)
+}
+
+func TestWithoutDevOnly(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<layer id="1" name="Ground" width="1" height="1">
+<data encoding="csv">0</data>
+</layer>
+<layer id="2" name="Notes" width="1" height="1">
+<properties><property name="devonly" type="bool" value="true"/></properties>
+<data encoding="csv">0</data>
+</layer>
+<objectgroup id="3" name="Spawns">
+<object id="1" name="Player" x="0" y="0"/>
+<object id="2" name="Debug" x="0" y="0">
+<properties><property name="editoronly" type="bool" value="true"/></properties>
+</object>
+</objectgroup>
+<group id="4" name="Markers">
+<properties><property name="editoronly" type="bool" value="true"/></properties>
+<layer id="5" name="Arrows" width="1" height="1">
+<data encoding="csv">0</data>
+</layer>
+</group>
+</map>`
+
+	m, err := LoadReader(GetAssetsDirectory(), bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	assert.Len(t, m.Layers, 2)
+	assert.Len(t, m.ObjectGroups[0].Objects, 2)
+	assert.Len(t, m.Groups, 1)
+
+	m, err = LoadReader(GetAssetsDirectory(), bytes.NewBufferString(tmx), WithoutDevOnly())
+	assert.NoError(t, err)
+	if assert.Len(t, m.Layers, 1) {
+		assert.Equal(t, "Ground", m.Layers[0].Name)
+	}
+	if assert.Len(t, m.ObjectGroups[0].Objects, 1) {
+		assert.Equal(t, "Player", m.ObjectGroups[0].Objects[0].Name)
+	}
+	assert.Len(t, m.Groups, 0)
+}
+
+func TestWithTransforms(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<layer id="1" name="Ground" width="1" height="1">
+<data encoding="csv">0</data>
+</layer>
+<layer id="2" name="Notes" width="1" height="1">
+<properties><property name="devonly" type="bool" value="true"/></properties>
+<data encoding="csv">0</data>
+</layer>
+</map>`
+
+	var order []string
+	RegisterTransform("test-winter", func(m *Map) error {
+		order = append(order, "winter")
+		m.Layers[0].Class = "winter"
+		return nil
+	})
+	t.Cleanup(func() { unregisterTransform("test-winter") })
+	assert.Panics(t, func() { RegisterTransform("test-winter", func(*Map) error { return nil }) })
+	assert.Contains(t, Transforms(), "without-devonly")
+
+	m, err := LoadReader(GetAssetsDirectory(), bytes.NewBufferString(tmx),
+		WithTransform(func(m *Map) error {
+			order = append(order, "first")
+			assert.Len(t, m.Layers, 2)
+			return nil
+		}),
+		WithTransforms("without-devonly", "test-winter"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "winter"}, order)
+	assert.Len(t, m.Layers, 1)
+	assert.Equal(t, "winter", m.Layers[0].Class)
+
+	_, err = LoadReader(GetAssetsDirectory(), bytes.NewBufferString(tmx), WithTransforms("missing"))
+	assert.ErrorIs(t, err, ErrUnknownTransform)
+
+	failed := errors.New("failed")
+	_, err = LoadReader(GetAssetsDirectory(), bytes.NewBufferString(tmx), WithTransform(func(*Map) error { return failed }))
+	assert.ErrorIs(t, err, failed)
+}
+
 func TestFont(t *testing.T) {
 	m, err := LoadFile(filepath.Join(GetAssetsDirectory(), "font.tmx"))
 
@@ -440,3 +671,216 @@ func TestVersions(t *testing.T) {
 	assert.Equal(t, tileset.Version, "1.2")
 	assert.Equal(t, tileset.TiledVersion, "1.2.3")
 }
+
+func TestWithGIDMask(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.2" tiledversion="1.2.1" orientation="orthogonal" renderorder="right-down" width="2" height="1" tilewidth="16" tileheight="16" infinite="0" nextlayerid="2" nextobjectid="2">
+ <tileset firstgid="1" name="tiles" tilewidth="16" tileheight="16" tilecount="4" columns="2">
+  <image source="tiles.png" width="32" height="32"/>
+ </tileset>
+ <layer id="1" name="ground" width="2" height="1">
+  <data encoding="csv">268435458,2147483651</data>
+ </layer>
+ <objectgroup id="2" name="objects">
+  <object id="1" gid="268435460" x="0" y="16" width="16" height="16"/>
+ </objectgroup>
+</map>`
+
+	var warnings []error
+	m, err := LoadReader(GetAssetsDirectory(), bytes.NewBufferString(data),
+		WithGIDMask(0x0fffffff), WithWarningHandler(func(err error) { warnings = append(warnings, err) }))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), m.Layers[0].Tiles[0].ID)
+	assert.Equal(t, uint32(2), m.Layers[0].Tiles[1].ID)
+	assert.True(t, m.Layers[0].Tiles[1].HorizontalFlip)
+	assert.Equal(t, uint32(4), m.ObjectGroups[0].Objects[0].GID)
+	assert.Len(t, warnings, 2)
+	for _, w := range warnings {
+		assert.ErrorIs(t, w, ErrReservedGIDBits)
+	}
+
+	m, err = LoadReader(GetAssetsDirectory(), bytes.NewBufferString(data))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x10000001), m.Layers[0].Tiles[0].ID)
+}
+
+func TestWithVariables(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.2" tiledversion="1.2.1" orientation="orthogonal" renderorder="right-down" width="1" height="1" tilewidth="16" tileheight="16" infinite="0" nextlayerid="2" nextobjectid="1">
+ <properties>
+  <property name="difficulty" value="${DIFFICULTY}"/>
+  <property name="unknown" value="${UNKNOWN}"/>
+ </properties>
+ <tileset firstgid="1" source="${TILESETS}/test2.tsx"/>
+ <tileset firstgid="100" name="embedded" tilewidth="16" tileheight="16" tilecount="1" columns="1">
+  <image source="${ROOT}/tiles.png" width="16" height="16"/>
+ </tileset>
+ <layer id="1" name="ground" width="1" height="1">
+  <properties>
+   <property name="speed" value="${SPEED}x"/>
+  </properties>
+  <data encoding="csv">1</data>
+ </layer>
+</map>`
+
+	vars := map[string]string{"DIFFICULTY": "hard", "TILESETS": "tilesets", "ROOT": "/assets", "SPEED": "2"}
+	m, err := LoadReader(GetAssetsDirectory(), bytes.NewBufferString(data), WithVariables(vars), WithPathVariables())
+	assert.NoError(t, err)
+	assert.Equal(t, "hard", m.Properties.GetString("difficulty"))
+	assert.Equal(t, "${UNKNOWN}", m.Properties.GetString("unknown"))
+	assert.Equal(t, "2x", m.Layers[0].Properties.GetString("speed"))
+	assert.Equal(t, "tilesets/test2.tsx", m.Tilesets[0].Source)
+	assert.True(t, m.Tilesets[0].SourceLoaded)
+	assert.Equal(t, "/assets/tiles.png", m.Tilesets[1].Image.Source)
+
+	// Paths are kept without WithPathVariables
+	_, err = LoadReader(GetAssetsDirectory(), bytes.NewBufferString(data), WithVariables(vars))
+	assert.Error(t, err)
+}
+
+func TestLoadParallax(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16" parallaxoriginx="160" parallaxoriginy="-8.5">
+<layer id="1" name="Ground" width="1" height="1"><data encoding="csv">0</data></layer>
+<group id="2" name="Far" parallaxx="0.5" parallaxy="0.25">
+<imagelayer id="3" name="Sky" parallaxx="0"/>
+<objectgroup id="4" name="Objects" parallaxy="2"/>
+</group>
+</map>`
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	assert.Equal(t, 160.0, m.ParallaxOriginX)
+	assert.Equal(t, -8.5, m.ParallaxOriginY)
+	assert.Equal(t, float32(1), m.Layers[0].ParallaxX)
+	assert.Equal(t, float32(1), m.Layers[0].ParallaxY)
+	g := m.Groups[0]
+	assert.Equal(t, float32(0.5), g.ParallaxX)
+	assert.Equal(t, float32(0.25), g.ParallaxY)
+	assert.Equal(t, float32(0), g.ImageLayers[0].ParallaxX)
+	assert.Equal(t, float32(1), g.ImageLayers[0].ParallaxY)
+	assert.Equal(t, float32(1), g.ObjectGroups[0].ParallaxX)
+	assert.Equal(t, float32(2), g.ObjectGroups[0].ParallaxY)
+
+	var out bytes.Buffer
+	assert.NoError(t, newTMXEncoder(&out, ".").encodeMap(m))
+	assert.Contains(t, out.String(), `parallaxoriginx="160" parallaxoriginy="-8.5"`)
+	assert.Contains(t, out.String(), `<layer id="1" name="Ground" width="1" height="1">`)
+	assert.Contains(t, out.String(), `parallaxx="0"`)
+
+	m, err = LoadJSONReader(".", bytes.NewBufferString(`{
+  "orientation": "orthogonal", "width": 1, "height": 1, "tilewidth": 16, "tileheight": 16,
+  "parallaxoriginx": 16, "parallaxoriginy": 32,
+  "layers": [
+    {"type": "tilelayer", "id": 1, "name": "Ground", "width": 1, "height": 1, "data": [0]},
+    {"type": "group", "id": 2, "name": "Far", "parallaxx": 0.5, "layers": []}
+  ]
+}`))
+	assert.NoError(t, err)
+	assert.Equal(t, 16.0, m.ParallaxOriginX)
+	assert.Equal(t, 32.0, m.ParallaxOriginY)
+	assert.Equal(t, float32(1), m.Layers[0].ParallaxX)
+	assert.Equal(t, float32(0.5), m.Groups[0].ParallaxX)
+	assert.Equal(t, float32(1), m.Groups[0].ParallaxY)
+}
+
+func TestLoadTintColor(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<layer id="1" name="Ground" width="1" height="1" tintcolor="#80ff0000"><data encoding="csv">0</data></layer>
+<group id="2" name="Night" tintcolor="#3040ff">
+<imagelayer id="3" name="Sky" tintcolor="#ff00ff00"/>
+<objectgroup id="4" name="Objects"/>
+</group>
+</map>`
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	assert.Equal(t, color.RGBA{R: 0xff, A: 0x80}, color.RGBAModel.Convert(m.Layers[0].TintColor))
+	// Missing alpha is opaque
+	assert.Equal(t, color.RGBA{R: 0x30, G: 0x40, B: 0xff, A: 0xff}, color.RGBAModel.Convert(m.Groups[0].TintColor))
+	assert.Equal(t, "#00ff00", m.Groups[0].ImageLayers[0].TintColor.String())
+	assert.Nil(t, m.Groups[0].ObjectGroups[0].TintColor)
+
+	var out bytes.Buffer
+	assert.NoError(t, newTMXEncoder(&out, ".").encodeMap(m))
+	assert.Contains(t, out.String(), `tintcolor="#80ff0000"`)
+	assert.Contains(t, out.String(), `tintcolor="#3040ff"`)
+
+	m, err = LoadJSONReader(".", bytes.NewBufferString(`{
+  "orientation": "orthogonal", "width": 1, "height": 1, "tilewidth": 16, "tileheight": 16,
+  "layers": [
+    {"type": "tilelayer", "id": 1, "name": "Ground", "width": 1, "height": 1, "data": [0], "tintcolor": "#80ff0000"},
+    {"type": "objectgroup", "id": 2, "name": "Objects", "tintcolor": "#00ff00", "objects": []}
+  ]
+}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "#80ff0000", m.Layers[0].TintColor.String())
+	assert.Equal(t, "#00ff00", m.ObjectGroups[0].TintColor.String())
+}
+
+func TestLoadStagger(t *testing.T) {
+	load := func(attrs string) (*Map, error) {
+		return LoadReader(".", bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" width="1" height="1" tilewidth="16" tileheight="16" `+attrs+`>
+<layer id="1" name="Ground" width="1" height="1"><data encoding="csv">0</data></layer>
+</map>`))
+	}
+
+	m, err := load(`orientation="hexagonal" hexsidelength="6" staggeraxis="x" staggerindex="even"`)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, m.HexSideLength)
+	assert.Equal(t, AxisX, m.StaggerAxis)
+	assert.Equal(t, StaggerIndexEven, m.StaggerIndex)
+
+	m, err = load(`orientation="staggered"`)
+	assert.NoError(t, err)
+	assert.Equal(t, AxisY, m.StaggerAxis)
+	assert.Equal(t, StaggerIndexOdd, m.StaggerIndex)
+
+	m, err = load(`orientation="orthogonal"`)
+	assert.NoError(t, err)
+	assert.Equal(t, Axis(""), m.StaggerAxis)
+
+	_, err = load(`orientation="hexagonal" staggeraxis="z"`)
+	assert.ErrorIs(t, err, ErrInvalidStagger)
+	_, err = load(`orientation="hexagonal" staggerindex="third"`)
+	assert.ErrorIs(t, err, ErrInvalidStagger)
+	_, err = load(`orientation="hexagonal" hexsidelength="-1"`)
+	assert.ErrorIs(t, err, ErrInvalidStagger)
+
+	_, err = LoadJSONReader(".", bytes.NewBufferString(`{
+  "orientation": "staggered", "width": 1, "height": 1, "tilewidth": 16, "tileheight": 16,
+  "staggeraxis": "w", "layers": []
+}`))
+	assert.ErrorIs(t, err, ErrInvalidStagger)
+}
+
+func TestLayerCells(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="3" height="2" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="ground" tilewidth="16" tileheight="16" tilecount="4" columns="2"/>
+<layer id="1" name="Ground" width="3" height="2"><data encoding="csv">1,0,2,0,3,0</data></layer>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+
+	var cells []image.Point
+	var ids []uint32
+	for cell, tile := range m.Layers[0].Cells() {
+		cells = append(cells, cell)
+		ids = append(ids, tile.ID)
+	}
+	assert.Equal(t, []image.Point{{0, 0}, {2, 0}, {1, 1}}, cells)
+	assert.Equal(t, []uint32{0, 1, 2}, ids)
+
+	n := 0
+	for range m.Layers[0].Cells() {
+		n++
+		break
+	}
+	assert.Equal(t, 1, n)
+
+	for range (&Layer{}).Cells() {
+		t.Fatal("cells of a layer without a map")
+	}
+}
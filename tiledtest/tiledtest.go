@@ -0,0 +1,192 @@
+// Package tiledtest builds maps, tilesets and images in memory, so code
+// loading Tiled maps can be tested without binary fixtures.
+package tiledtest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io/fs"
+	"math"
+	"path"
+	"strings"
+	"testing/fstest"
+
+	"github.com/Tsukumogami-Software/go-tiled"
+)
+
+// gidMask clears the flip flags of tile GIDs
+const gidMask = 0x0fffffff
+
+// Fixture is an in-memory file system holding generated files
+type Fixture struct {
+	FS fstest.MapFS
+
+	// Number of tiles of the tilesets added, by file name
+	tileCounts map[string]int
+}
+
+// New creates an empty Fixture
+func New() *Fixture {
+	return &Fixture{FS: fstest.MapFS{}, tileCounts: map[string]int{}}
+}
+
+// AddFile adds a file with the given content
+func (f *Fixture) AddFile(name string, data []byte) *Fixture {
+	f.FS[name] = &fstest.MapFile{Data: data, Mode: 0o644}
+	return f
+}
+
+// TileColor returns the color of the tile with the given ID in generated
+// tileset images, distinct for each tile
+func TileColor(id int) color.NRGBA {
+	// Golden angle hue steps keep neighbouring tiles apart
+	h := math.Mod(float64(id)*137.508, 360) / 60
+	x := 1 - math.Abs(math.Mod(h, 2)-1)
+	var r, g, b float64
+	switch int(h) {
+	case 0:
+		r, g = 1, x
+	case 1:
+		r, g = x, 1
+	case 2:
+		g, b = 1, x
+	case 3:
+		g, b = x, 1
+	case 4:
+		r, b = x, 1
+	default:
+		r, b = 1, x
+	}
+	return color.NRGBA{R: uint8(r * 255), G: uint8(g * 255), B: uint8(b * 255), A: 0xff}
+}
+
+// TilesetImage returns a tileset image of columns by rows tiles, each filled
+// with its TileColor
+func TilesetImage(tileWidth, tileHeight, columns, rows int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, tileWidth*columns, tileHeight*rows))
+	for y := 0; y < img.Rect.Dy(); y++ {
+		for x := 0; x < img.Rect.Dx(); x++ {
+			img.SetNRGBA(x, y, TileColor(y/tileHeight*columns+x/tileWidth))
+		}
+	}
+	return img
+}
+
+// AddImage adds a PNG image
+func (f *Fixture) AddImage(name string, img image.Image) *Fixture {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		panic(err)
+	}
+	return f.AddFile(name, buf.Bytes())
+}
+
+// AddTileset adds a TSX tileset of columns by rows tiles, along with its
+// image generated by TilesetImage, named after the tileset with a .png
+// extension.
+func (f *Fixture) AddTileset(name string, tileWidth, tileHeight, columns, rows int) *Fixture {
+	imageName := strings.TrimSuffix(path.Base(name), path.Ext(name)) + ".png"
+	f.AddImage(path.Join(path.Dir(name), imageName), TilesetImage(tileWidth, tileHeight, columns, rows))
+
+	f.tileCounts[name] = columns * rows
+	return f.AddFile(name, fmt.Appendf(nil, `<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="%s" tilewidth="%d" tileheight="%d" tilecount="%d" columns="%d">
+ <image source="%s" width="%d" height="%d"/>
+</tileset>
+`, escape(strings.TrimSuffix(path.Base(name), path.Ext(name))), tileWidth, tileHeight, columns*rows, columns,
+		escape(imageName), tileWidth*columns, tileHeight*rows))
+}
+
+// escape escapes text for XML attributes
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// Layer is a tile layer of a generated map
+type Layer struct {
+	Name string
+	// GIDs of the tiles, row by row
+	GIDs []uint32
+}
+
+// Map describes a generated orthogonal map
+type Map struct {
+	Width, Height         int
+	TileWidth, TileHeight int
+	// File names of tilesets added with AddTileset, relative to the map.
+	// Maps without tilesets get one generated next to them, holding the
+	// tiles up to the highest GID of their layers.
+	Tilesets []string
+	Layers   []Layer
+}
+
+// AddMap adds a TMX map. The first GIDs of the tilesets follow each other in
+// the given order, starting at 1.
+func (f *Fixture) AddMap(name string, m Map) *Fixture {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" renderorder="right-down" width="%d" height="%d" tilewidth="%d" tileheight="%d">
+`, m.Width, m.Height, m.TileWidth, m.TileHeight)
+
+	if len(m.Tilesets) == 0 {
+		if count := maxGID(m.Layers); count > 0 {
+			ts := strings.TrimSuffix(path.Base(name), path.Ext(name)) + "_tiles.tsx"
+			f.AddTileset(path.Join(path.Dir(name), ts), m.TileWidth, m.TileHeight, count, 1)
+			m.Tilesets = []string{ts}
+		}
+	}
+
+	firstGID := 1
+	for _, ts := range m.Tilesets {
+		fmt.Fprintf(&b, " <tileset firstgid=\"%d\" source=\"%s\"/>\n", firstGID, escape(ts))
+		firstGID += f.tileCounts[path.Join(path.Dir(name), ts)]
+	}
+
+	for i, l := range m.Layers {
+		fmt.Fprintf(&b, " <layer id=\"%d\" name=\"%s\" width=\"%d\" height=\"%d\">\n  <data encoding=\"csv\">\n", i+1, escape(l.Name), m.Width, m.Height)
+		for y := 0; y < m.Height; y++ {
+			for x := 0; x < m.Width; x++ {
+				var gid uint32
+				if i := y*m.Width + x; i < len(l.GIDs) {
+					gid = l.GIDs[i]
+				}
+				fmt.Fprintf(&b, "%d", gid)
+				if x < m.Width-1 || y < m.Height-1 {
+					b.WriteByte(',')
+				}
+			}
+			b.WriteByte('\n')
+		}
+		b.WriteString("  </data>\n </layer>\n")
+	}
+	b.WriteString("</map>\n")
+
+	return f.AddFile(name, []byte(b.String()))
+}
+
+// maxGID returns the highest GID of the layers, without flip flags
+func maxGID(layers []Layer) int {
+	var res uint32
+	for _, l := range layers {
+		for _, gid := range l.GIDs {
+			res = max(res, gid&gidMask)
+		}
+	}
+	return int(res)
+}
+
+// LoadMap loads a map of the fixture
+func (f *Fixture) LoadMap(name string, options ...tiled.LoaderOption) (*tiled.Map, error) {
+	return tiled.LoadFile(name, append(options, tiled.WithFileSystem(f.FS))...)
+}
+
+// Open opens a file of the fixture, so a Fixture can be used as an fs.FS
+func (f *Fixture) Open(name string) (fs.File, error) {
+	return f.FS.Open(name)
+}
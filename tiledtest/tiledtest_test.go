@@ -0,0 +1,56 @@
+package tiledtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFixture(t *testing.T) {
+	f := New().
+		AddTileset("tilesets/ground.tsx", 8, 8, 2, 2).
+		AddTileset("tilesets/props.tsx", 8, 8, 3, 1).
+		AddMap("level.tmx", Map{
+			Width: 2, Height: 2, TileWidth: 8, TileHeight: 8,
+			Tilesets: []string{"tilesets/ground.tsx", "tilesets/props.tsx"},
+			Layers:   []Layer{{Name: "Ground", GIDs: []uint32{1, 2, 0, 5}}},
+		})
+
+	m, err := f.LoadMap("level.tmx")
+	assert.NoError(t, err)
+	assert.Len(t, m.Tilesets, 2)
+	assert.Equal(t, uint32(5), m.Tilesets[1].FirstGID)
+
+	tiles := m.Layers[0].Tiles
+	assert.Equal(t, "ground", tiles[1].Tileset.Name)
+	assert.Equal(t, uint32(1), tiles[1].ID)
+	assert.True(t, tiles[2].IsNil())
+	assert.Equal(t, "props", tiles[3].Tileset.Name)
+	assert.Equal(t, uint32(0), tiles[3].ID)
+
+	img := TilesetImage(8, 8, 2, 2)
+	assert.Equal(t, TileColor(3), img.NRGBAAt(12, 12))
+	assert.NotEqual(t, TileColor(0), TileColor(1))
+	_, err = f.Open("tilesets/ground.png")
+	assert.NoError(t, err)
+}
+
+func TestFixtureDefaultTileset(t *testing.T) {
+	f := New().AddMap("maps/level.tmx", Map{
+		Width: 2, Height: 1, TileWidth: 8, TileHeight: 8,
+		Layers: []Layer{{Name: "Ground", GIDs: []uint32{3, 0x80000002}}},
+	})
+
+	m, err := f.LoadMap("maps/level.tmx")
+	assert.NoError(t, err)
+	if assert.Len(t, m.Tilesets, 1) {
+		assert.Equal(t, "level_tiles", m.Tilesets[0].Name)
+		assert.Equal(t, 3, m.Tilesets[0].TileCount)
+	}
+	tiles := m.Layers[0].Tiles
+	assert.Equal(t, uint32(2), tiles[0].ID)
+	assert.Equal(t, uint32(1), tiles[1].ID)
+	assert.True(t, tiles[1].HorizontalFlip)
+	_, err = f.Open("maps/level_tiles.png")
+	assert.NoError(t, err)
+}
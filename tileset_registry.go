@@ -0,0 +1,83 @@
+package tiled
+
+import "sync"
+
+// TilesetRegistry keeps the external tilesets parsed while loading maps, by
+// path, so maps referencing the same TSX or JSON tileset parse it once. It is
+// passed to the loads sharing it with WithTilesetRegistry, and is safe for
+// concurrent use.
+//
+// Maps get copies of the tileset sharing its tiles, Wang sets and properties,
+// which must not be modified. Maps loaded from different file systems or
+// with different variables should not share a registry.
+type TilesetRegistry struct {
+	mu       sync.RWMutex
+	tilesets map[string]*Tileset
+}
+
+// NewTilesetRegistry creates an empty TilesetRegistry
+func NewTilesetRegistry() *TilesetRegistry {
+	return &TilesetRegistry{tilesets: map[string]*Tileset{}}
+}
+
+// WithTilesetRegistry returns an option reusing the external tilesets of the
+// registry, and adding the ones parsed to it
+func WithTilesetRegistry(registry *TilesetRegistry) LoaderOption {
+	return func(l *loader) {
+		l.tilesets = registry
+	}
+}
+
+// Len returns the number of tilesets in the registry
+func (r *TilesetRegistry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.tilesets)
+}
+
+// Forget drops the tileset with the given path, so it is parsed again when
+// next referenced
+func (r *TilesetRegistry) Forget(path string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.tilesets, path)
+}
+
+func (l *loader) tilesetRegistry() *TilesetRegistry {
+	if l == nil {
+		return nil
+	}
+	return l.tilesets
+}
+
+// get copies the tileset with the given path into ts, keeping the first GID
+// and source of ts, and reports whether the registry has it
+func (r *TilesetRegistry) get(path string, ts *Tileset) bool {
+	if r == nil {
+		return false
+	}
+	r.mu.RLock()
+	shared, ok := r.tilesets[path]
+	r.mu.RUnlock()
+	if !ok {
+		return false
+	}
+	firstGID, source := ts.FirstGID, ts.Source
+	*ts = *shared
+	ts.FirstGID, ts.Source = firstGID, source
+	return true
+}
+
+// add keeps a copy of the tileset parsed from path, unless the registry has
+// one already
+func (r *TilesetRegistry) add(path string, ts *Tileset) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.tilesets[path]; !ok {
+		shared := *ts
+		r.tilesets[path] = &shared
+	}
+}
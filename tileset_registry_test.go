@@ -0,0 +1,58 @@
+package tiled
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingFS counts the files opened by name
+type countingFS struct {
+	fs.FS
+	opened map[string]int
+}
+
+func (c *countingFS) Open(name string) (fs.File, error) {
+	c.opened[name]++
+	return c.FS.Open(name)
+}
+
+func TestTilesetRegistry(t *testing.T) {
+	level := func(firstGID string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="1" tilewidth="8" tileheight="8">
+<tileset firstgid="` + firstGID + `" source="../tilesets/ground.tsx"/>
+<layer id="1" name="Ground" width="2" height="1"><data encoding="csv">0,` + firstGID + `</data></layer>
+</map>`)}
+	}
+	fsys := &countingFS{FS: fstest.MapFS{
+		"maps/a.tmx": level("1"),
+		"maps/b.tmx": level("5"),
+		"tilesets/ground.tsx": &fstest.MapFile{Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="ground" tilewidth="8" tileheight="8" tilecount="2" columns="2">
+<image source="ground.png" width="16" height="8"/>
+</tileset>`)},
+	}, opened: map[string]int{}}
+
+	registry := NewTilesetRegistry()
+	a, err := LoadFile("maps/a.tmx", WithFileSystem(fsys), WithTilesetRegistry(registry))
+	assert.NoError(t, err)
+	b, err := LoadFile("maps/b.tmx", WithFileSystem(fsys), WithTilesetRegistry(registry))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, fsys.opened["tilesets/ground.tsx"])
+	assert.Equal(t, 1, registry.Len())
+
+	assert.NotSame(t, a.Tilesets[0], b.Tilesets[0])
+	assert.Equal(t, uint32(1), a.Tilesets[0].FirstGID)
+	assert.Equal(t, uint32(5), b.Tilesets[0].FirstGID)
+	assert.Equal(t, "ground", b.Tilesets[0].Name)
+	assert.Equal(t, "tilesets", b.Tilesets[0].BaseDir())
+	assert.Equal(t, uint32(0), b.Layers[0].Tiles[1].ID)
+
+	registry.Forget("tilesets/ground.tsx")
+	_, err = LoadFile("maps/b.tmx", WithFileSystem(fsys), WithTilesetRegistry(registry))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, fsys.opened["tilesets/ground.tsx"])
+}
@@ -0,0 +1,820 @@
+package tiled
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrUnknownLayerType is returned for JSON layers of unknown type
+var ErrUnknownLayerType = errors.New("tiled: unknown layer type")
+
+// jsonString returns a JSON string as is, and the text of other JSON values
+// such as numbers, which some fields were written as by older versions
+func jsonString(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// jsonColor parses an optional color
+func jsonColor(s string) (*HexColor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	c, err := ParseHexColor(s)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// jsonImage returns the image of a JSON tileset, tile or image layer
+func jsonImage(source string, width, height int, trans string) (*Image, error) {
+	if source == "" {
+		return nil, nil
+	}
+	c, err := jsonColor(trans)
+	if err != nil {
+		return nil, err
+	}
+	return &Image{Source: source, Width: width, Height: height, Trans: c}, nil
+}
+
+type jsonProperty struct {
+	Name         string          `json:"name"`
+	Type         string          `json:"type"`
+	PropertyType string          `json:"propertytype,omitempty"`
+	Value        json.RawMessage `json:"value"`
+}
+
+func jsonProperties(props []*jsonProperty) Properties {
+	if len(props) == 0 {
+		return nil
+	}
+	res := make(Properties, 0, len(props))
+	for _, p := range props {
+		property := &Property{Name: p.Name, Type: p.Type, PropertyType: p.PropertyType}
+		if p.Type == "class" {
+			property.Properties = jsonClassMembers(p.Value)
+		} else {
+			property.Value = jsonString(p.Value)
+		}
+		res = append(res, property)
+	}
+	return res
+}
+
+// jsonClassMembers converts the value of a class property, an object of the
+// members set. Their types aren't stored and are guessed from their values,
+// WithPropertyTypes giving them the types of their definition.
+func jsonClassMembers(value json.RawMessage) Properties {
+	var members map[string]json.RawMessage
+	if json.Unmarshal(value, &members) != nil || len(members) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	res := make(Properties, 0, len(members))
+	for _, name := range names {
+		v := bytes.TrimSpace(members[name])
+		p := &Property{Name: name}
+		switch {
+		case len(v) == 0:
+		case v[0] == '{':
+			p.Type = "class"
+			p.Properties = jsonClassMembers(v)
+		case v[0] == '"':
+			p.Type = "string"
+		case bytes.Equal(v, []byte("true")) || bytes.Equal(v, []byte("false")):
+			p.Type = "bool"
+		case bytes.ContainsAny(v, ".eE"):
+			p.Type = "float"
+		default:
+			p.Type = "int"
+		}
+		if p.Type != "class" {
+			p.Value = jsonString(v)
+		}
+		res = append(res, p)
+	}
+	return res
+}
+
+type jsonMap struct {
+	// Written as "map", not read
+	Type            string           `json:"type,omitempty"`
+	Version         json.RawMessage  `json:"version"`
+	TiledVersion    string           `json:"tiledversion,omitempty"`
+	Class           string           `json:"class,omitempty"`
+	Orientation     string           `json:"orientation"`
+	RenderOrder     string           `json:"renderorder"`
+	Width           int              `json:"width"`
+	Height          int              `json:"height"`
+	TileWidth       int              `json:"tilewidth"`
+	TileHeight      int              `json:"tileheight"`
+	HexSideLength   int              `json:"hexsidelength,omitempty"`
+	StaggerAxis     Axis             `json:"staggeraxis,omitempty"`
+	StaggerIndex    StaggerIndexType `json:"staggerindex,omitempty"`
+	ParallaxOriginX float64          `json:"parallaxoriginx,omitempty"`
+	ParallaxOriginY float64          `json:"parallaxoriginy,omitempty"`
+	BackgroundColor string           `json:"backgroundcolor,omitempty"`
+	NextLayerID     uint32           `json:"nextlayerid,omitempty"`
+	NextObjectID    uint32           `json:"nextobjectid,omitempty"`
+	Properties      []*jsonProperty  `json:"properties,omitempty"`
+	Tilesets        []*jsonTileset   `json:"tilesets"`
+	Layers          []*jsonLayer     `json:"layers"`
+}
+
+// UnmarshalJSON decodes a map, filling in defaults like UnmarshalXML does
+func (jm *jsonMap) UnmarshalJSON(data []byte) error {
+	type alias jsonMap
+	item := alias{RenderOrder: "right-down"}
+	if err := json.Unmarshal(data, &item); err != nil {
+		return err
+	}
+	*jm = jsonMap(item)
+	return nil
+}
+
+func (jm *jsonMap) toMap(l *loader, baseDir string) (*Map, error) {
+	m := &Map{
+		loader:          l,
+		baseDir:         baseDir,
+		Version:         jsonString(jm.Version),
+		TiledVersion:    jm.TiledVersion,
+		Class:           jm.Class,
+		Orientation:     jm.Orientation,
+		RenderOrder:     jm.RenderOrder,
+		Width:           jm.Width,
+		Height:          jm.Height,
+		TileWidth:       jm.TileWidth,
+		TileHeight:      jm.TileHeight,
+		HexSideLength:   jm.HexSideLength,
+		StaggerAxis:     jm.StaggerAxis,
+		StaggerIndex:    jm.StaggerIndex,
+		ParallaxOriginX: jm.ParallaxOriginX,
+		ParallaxOriginY: jm.ParallaxOriginY,
+		NextLayerID:     jm.NextLayerID,
+		NextObjectID:    jm.NextObjectID,
+	}
+
+	var err error
+	if m.BackgroundColor, err = jsonColor(jm.BackgroundColor); err != nil {
+		return nil, err
+	}
+	if props := jsonProperties(jm.Properties); props != nil {
+		m.Properties = &props
+	}
+
+	for _, jts := range jm.Tilesets {
+		ts, err := jts.toTileset()
+		if err != nil {
+			return nil, elementError("tileset", jts.Name, 0, err)
+		}
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+
+	for _, jl := range jm.Layers {
+		if err := jl.addTo(&m.Layers, &m.ObjectGroups, &m.ImageLayers, &m.Groups); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+type jsonLayer struct {
+	Type       string          `json:"type"`
+	ID         uint32          `json:"id"`
+	Name       string          `json:"name"`
+	Class      string          `json:"class,omitempty"`
+	Opacity    float32         `json:"opacity"`
+	Visible    bool            `json:"visible"`
+	OffsetX    float64         `json:"offsetx,omitempty"`
+	OffsetY    float64         `json:"offsety,omitempty"`
+	ParallaxX  float32         `json:"parallaxx"`
+	ParallaxY  float32         `json:"parallaxy"`
+	TintColor  string          `json:"tintcolor,omitempty"`
+	Properties []*jsonProperty `json:"properties,omitempty"`
+
+	// Tile layers
+	Width       int             `json:"width,omitempty"`
+	Height      int             `json:"height,omitempty"`
+	Data        json.RawMessage `json:"data,omitempty"`
+	Encoding    string          `json:"encoding,omitempty"`
+	Compression string          `json:"compression,omitempty"`
+
+	// Object groups
+	DrawOrder string        `json:"draworder,omitempty"`
+	Color     string        `json:"color,omitempty"`
+	Objects   []*jsonObject `json:"objects,omitempty"`
+
+	// Image layers
+	Image            string  `json:"image,omitempty"`
+	ImageWidth       int     `json:"imagewidth,omitempty"`
+	ImageHeight      int     `json:"imageheight,omitempty"`
+	TransparentColor string  `json:"transparentcolor,omitempty"`
+	X                float64 `json:"x,omitempty"`
+	Y                float64 `json:"y,omitempty"`
+	RepeatX          bool    `json:"repeatx,omitempty"`
+	RepeatY          bool    `json:"repeaty,omitempty"`
+
+	// Groups
+	Layers []*jsonLayer `json:"layers,omitempty"`
+}
+
+// UnmarshalJSON decodes a layer, filling in defaults like UnmarshalXML does
+func (jl *jsonLayer) UnmarshalJSON(data []byte) error {
+	type alias jsonLayer
+	item := alias{Opacity: 1, Visible: true, ParallaxX: 1, ParallaxY: 1}
+	if err := json.Unmarshal(data, &item); err != nil {
+		return err
+	}
+	*jl = jsonLayer(item)
+	return nil
+}
+
+// data returns the tile data of a tile layer, given as an array of GIDs or as
+// an encoded string. Infinite maps have no data.
+func (jl *jsonLayer) data() (*Data, error) {
+	raw := bytes.TrimSpace(jl.Data)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return nil, err
+		}
+		return &Data{Encoding: jl.Encoding, Compression: jl.Compression, RawData: []byte(s)}, nil
+	}
+
+	var gids []uint32
+	if err := json.Unmarshal(raw, &gids); err != nil {
+		return nil, err
+	}
+	d := &Data{DataTiles: make([]*DataTile, len(gids))}
+	for i, gid := range gids {
+		d.DataTiles[i] = &DataTile{GID: gid}
+	}
+	return d, nil
+}
+
+func (jl *jsonLayer) objectGroup() (*ObjectGroup, error) {
+	g := &ObjectGroup{
+		ID:         jl.ID,
+		Name:       jl.Name,
+		Class:      jl.Class,
+		Opacity:    jl.Opacity,
+		Visible:    jl.Visible,
+		OffsetX:    int(jl.OffsetX),
+		OffsetY:    int(jl.OffsetY),
+		DrawOrder:  jl.DrawOrder,
+		ParallaxX:  jl.ParallaxX,
+		ParallaxY:  jl.ParallaxY,
+		Properties: jsonProperties(jl.Properties),
+	}
+	var err error
+	if g.Color, err = jsonColor(jl.Color); err != nil {
+		return nil, err
+	}
+	if g.TintColor, err = jsonColor(jl.TintColor); err != nil {
+		return nil, err
+	}
+	for _, jo := range jl.Objects {
+		o, err := jo.toObject()
+		if err != nil {
+			return nil, elementError("object", jo.Name, jo.ID, err)
+		}
+		g.Objects = append(g.Objects, o)
+	}
+	return g, nil
+}
+
+// addTo converts the layer and appends it to the list of its type. Errors are
+// located in the layer like the TMX decoder does.
+func (jl *jsonLayer) addTo(layers *[]*Layer, objectGroups *[]*ObjectGroup, imageLayers *[]*ImageLayer, groups *[]*Group) (err error) {
+	defer func() {
+		element := jl.Type
+		if element != "objectgroup" && element != "imagelayer" && element != "group" {
+			element = "layer"
+		}
+		err = elementError(element, jl.Name, jl.ID, err)
+	}()
+
+	switch jl.Type {
+	case "tilelayer":
+		l := &Layer{
+			ID:         jl.ID,
+			Name:       jl.Name,
+			Class:      jl.Class,
+			Opacity:    jl.Opacity,
+			Visible:    jl.Visible,
+			OffsetX:    int(jl.OffsetX),
+			OffsetY:    int(jl.OffsetY),
+			ParallaxX:  jl.ParallaxX,
+			ParallaxY:  jl.ParallaxY,
+			Properties: jsonProperties(jl.Properties),
+		}
+		if l.data, err = jl.data(); err != nil {
+			return err
+		}
+		if l.TintColor, err = jsonColor(jl.TintColor); err != nil {
+			return err
+		}
+		*layers = append(*layers, l)
+
+	case "objectgroup":
+		g, err := jl.objectGroup()
+		if err != nil {
+			return err
+		}
+		*objectGroups = append(*objectGroups, g)
+
+	case "imagelayer":
+		l := &ImageLayer{
+			ID:         jl.ID,
+			Name:       jl.Name,
+			Class:      jl.Class,
+			OffsetX:    int(jl.OffsetX),
+			OffsetY:    int(jl.OffsetY),
+			X:          int(jl.X),
+			Y:          int(jl.Y),
+			Opacity:    jl.Opacity,
+			Visible:    jl.Visible,
+			Properties: jsonProperties(jl.Properties),
+			ParallaxX:  jl.ParallaxX,
+			ParallaxY:  jl.ParallaxY,
+			RepeatX:    jl.RepeatX,
+			RepeatY:    jl.RepeatY,
+		}
+		if l.Image, err = jsonImage(jl.Image, jl.ImageWidth, jl.ImageHeight, jl.TransparentColor); err != nil {
+			return err
+		}
+		if l.TintColor, err = jsonColor(jl.TintColor); err != nil {
+			return err
+		}
+		*imageLayers = append(*imageLayers, l)
+
+	case "group":
+		g := &Group{
+			ID:         jl.ID,
+			Name:       jl.Name,
+			Class:      jl.Class,
+			OffsetX:    int(jl.OffsetX),
+			OffsetY:    int(jl.OffsetY),
+			Opacity:    jl.Opacity,
+			Visible:    jl.Visible,
+			ParallaxX:  jl.ParallaxX,
+			ParallaxY:  jl.ParallaxY,
+			Properties: jsonProperties(jl.Properties),
+		}
+		if g.TintColor, err = jsonColor(jl.TintColor); err != nil {
+			return err
+		}
+		for _, sub := range jl.Layers {
+			if err := sub.addTo(&g.Layers, &g.ObjectGroups, &g.ImageLayers, &g.Groups); err != nil {
+				return err
+			}
+		}
+		*groups = append(*groups, g)
+
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownLayerType, jl.Type)
+	}
+	return nil
+}
+
+type jsonPoint struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+func jsonPoints(points []jsonPoint) *Points {
+	res := make(Points, len(points))
+	for i, p := range points {
+		res[i] = &Point{X: p.X, Y: p.Y}
+	}
+	return &res
+}
+
+type jsonObject struct {
+	ID         uint32          `json:"id"`
+	Name       string          `json:"name"`
+	Type       string          `json:"type,omitempty"`
+	Class      string          `json:"class,omitempty"`
+	X          float64         `json:"x"`
+	Y          float64         `json:"y"`
+	Width      float64         `json:"width"`
+	Height     float64         `json:"height"`
+	Rotation   float64         `json:"rotation"`
+	GID        uint32          `json:"gid,omitempty"`
+	Visible    bool            `json:"visible"`
+	Properties []*jsonProperty `json:"properties,omitempty"`
+	Ellipse    bool            `json:"ellipse,omitempty"`
+	Polygon    []jsonPoint     `json:"polygon,omitempty"`
+	PolyLine   []jsonPoint     `json:"polyline,omitempty"`
+	Text       *jsonText       `json:"text,omitempty"`
+	Template   string          `json:"template,omitempty"`
+
+	// Fields set on an object using a template, the ones it overrides
+	overrides map[string]bool
+}
+
+// UnmarshalJSON decodes an object, filling in defaults like UnmarshalXML does
+func (jo *jsonObject) UnmarshalJSON(data []byte) error {
+	type alias jsonObject
+	item := alias{Visible: true}
+	if err := json.Unmarshal(data, &item); err != nil {
+		return err
+	}
+	*jo = jsonObject(item)
+	if jo.Template != "" {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return err
+		}
+		jo.overrides = map[string]bool{}
+		for name := range fields {
+			jo.overrides[name] = true
+		}
+	}
+	return nil
+}
+
+func (jo *jsonObject) toObject() (*Object, error) {
+	o := &Object{
+		ID:             jo.ID,
+		Name:           jo.Name,
+		Type:           jo.Type,
+		Class:          jo.Class,
+		X:              jo.X,
+		Y:              jo.Y,
+		Width:          jo.Width,
+		Height:         jo.Height,
+		Rotation:       jo.Rotation,
+		GID:            jo.GID,
+		Visible:        jo.Visible,
+		Properties:     jsonProperties(jo.Properties),
+		TemplateSource: jo.Template,
+		overrides:      jo.overrides,
+	}
+	if jo.Ellipse {
+		o.Ellipses = []*Ellipse{{}}
+	}
+	if jo.Polygon != nil {
+		o.Polygons = []*Polygon{{Points: jsonPoints(jo.Polygon)}}
+	}
+	if jo.PolyLine != nil {
+		o.PolyLines = []*PolyLine{{Points: jsonPoints(jo.PolyLine)}}
+	}
+	if jo.Text != nil {
+		t := &Text{
+			Text:          jo.Text.Text,
+			FontFamily:    jo.Text.FontFamily,
+			Size:          jo.Text.PixelSize,
+			Wrap:          jo.Text.Wrap,
+			Bold:          jo.Text.Bold,
+			Italic:        jo.Text.Italic,
+			Underline:     jo.Text.Underline,
+			Strikethrough: jo.Text.Strikeout,
+			Kerning:       jo.Text.Kerning,
+			HAlign:        jo.Text.HAlign,
+			VAlign:        jo.Text.VAlign,
+			Color:         &HexColor{},
+		}
+		if jo.Text.Color != "" {
+			c, err := ParseHexColor(jo.Text.Color)
+			if err != nil {
+				return nil, err
+			}
+			t.Color = &c
+		}
+		o.Text = t
+	}
+	return o, nil
+}
+
+type jsonText struct {
+	Text       string `json:"text"`
+	FontFamily string `json:"fontfamily"`
+	PixelSize  int    `json:"pixelsize"`
+	Wrap       bool   `json:"wrap,omitempty"`
+	Color      string `json:"color,omitempty"`
+	Bold       bool   `json:"bold,omitempty"`
+	Italic     bool   `json:"italic,omitempty"`
+	Underline  bool   `json:"underline,omitempty"`
+	Strikeout  bool   `json:"strikeout,omitempty"`
+	Kerning    bool   `json:"kerning"`
+	HAlign     string `json:"halign"`
+	VAlign     string `json:"valign"`
+}
+
+// UnmarshalJSON decodes a text, filling in defaults like UnmarshalXML does
+func (jt *jsonText) UnmarshalJSON(data []byte) error {
+	type alias jsonText
+	item := alias{FontFamily: "sans-serif", PixelSize: 16, Kerning: true, HAlign: "left", VAlign: "top"}
+	if err := json.Unmarshal(data, &item); err != nil {
+		return err
+	}
+	*jt = jsonText(item)
+	return nil
+}
+
+// isJSON reports whether the file read by br is in the JSON format rather
+// than XML, from its first character
+func isJSON(br *bufio.Reader) (bool, error) {
+	for {
+		c, err := br.ReadByte()
+		if err != nil {
+			return false, err
+		}
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == 0xef || c == 0xbb || c == 0xbf {
+			// Whitespace and byte order marks
+			continue
+		}
+		return c == '{', br.UnreadByte()
+	}
+}
+
+// decodeTileset decodes a TSX or JSON tileset into ts. The first GID and
+// source of ts are kept.
+func decodeTileset(r io.Reader, ts *Tileset) error {
+	br := bufio.NewReader(r)
+	isJSON, err := isJSON(br)
+	if err != nil {
+		return err
+	}
+	if !isJSON {
+		return xml.NewDecoder(br).Decode(ts)
+	}
+
+	var jts jsonTileset
+	if err := json.NewDecoder(br).Decode(&jts); err != nil {
+		return err
+	}
+	t, err := jts.toTileset()
+	if err != nil {
+		return elementError("tileset", jts.Name, 0, err)
+	}
+	t.baseDir, t.FirstGID, t.Source = ts.baseDir, ts.FirstGID, ts.Source
+	*ts = *t
+	return nil
+}
+
+type jsonTemplate struct {
+	Tileset *jsonTileset `json:"tileset"`
+	Object  *jsonObject  `json:"object"`
+}
+
+// decodeTemplate decodes a TX or JSON (.tj) template into t
+func decodeTemplate(r io.Reader, t *Template) error {
+	br := bufio.NewReader(r)
+	isJSON, err := isJSON(br)
+	if err != nil {
+		return err
+	}
+	if !isJSON {
+		return xml.NewDecoder(br).Decode(t)
+	}
+
+	var jt jsonTemplate
+	if err := json.NewDecoder(br).Decode(&jt); err != nil {
+		return err
+	}
+	if jt.Tileset != nil {
+		if t.Tileset, err = jt.Tileset.toTileset(); err != nil {
+			return elementError("tileset", jt.Tileset.Name, 0, err)
+		}
+	}
+	if jt.Object != nil {
+		if t.Object, err = jt.Object.toObject(); err != nil {
+			return elementError("object", jt.Object.Name, jt.Object.ID, err)
+		}
+	}
+	return nil
+}
+
+type jsonTileset struct {
+	// Written as "tileset" for tileset files, not read
+	Type             string             `json:"type,omitempty"`
+	FirstGID         uint32             `json:"firstgid,omitempty"`
+	Source           string             `json:"source,omitempty"`
+	Name             string             `json:"name,omitempty"`
+	Class            string             `json:"class,omitempty"`
+	TileWidth        int                `json:"tilewidth,omitempty"`
+	TileHeight       int                `json:"tileheight,omitempty"`
+	Spacing          int                `json:"spacing,omitempty"`
+	Margin           int                `json:"margin,omitempty"`
+	TileCount        int                `json:"tilecount,omitempty"`
+	Columns          int                `json:"columns,omitempty"`
+	Image            string             `json:"image,omitempty"`
+	ImageWidth       int                `json:"imagewidth,omitempty"`
+	ImageHeight      int                `json:"imageheight,omitempty"`
+	TransparentColor string             `json:"transparentcolor,omitempty"`
+	TileRenderSize   TileRenderSize     `json:"tilerendersize,omitempty"`
+	FillMode         FillMode           `json:"fillmode,omitempty"`
+	TileOffset       *jsonPoint         `json:"tileoffset,omitempty"`
+	Grid             *jsonGrid          `json:"grid,omitempty"`
+	Transformations  *jsonTransforms    `json:"transformations,omitempty"`
+	Properties       []*jsonProperty    `json:"properties,omitempty"`
+	Terrains         []*jsonTerrain     `json:"terrains,omitempty"`
+	Tiles            []*jsonTilesetTile `json:"tiles,omitempty"`
+	WangSets         []*jsonWangSet     `json:"wangsets,omitempty"`
+}
+
+type jsonGrid struct {
+	Orientation string `json:"orientation"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+}
+
+type jsonTransforms struct {
+	HFlip               bool `json:"hflip"`
+	VFlip               bool `json:"vflip"`
+	Rotate              bool `json:"rotate"`
+	PreferUntransformed bool `json:"preferuntransformed"`
+}
+
+func (jts *jsonTileset) toTileset() (*Tileset, error) {
+	ts := &Tileset{
+		FirstGID:       jts.FirstGID,
+		Source:         jts.Source,
+		Name:           jts.Name,
+		Class:          jts.Class,
+		TileWidth:      jts.TileWidth,
+		TileHeight:     jts.TileHeight,
+		Spacing:        jts.Spacing,
+		Margin:         jts.Margin,
+		TileCount:      jts.TileCount,
+		Columns:        jts.Columns,
+		TileRenderSize: jts.TileRenderSize,
+		FillMode:       jts.FillMode,
+		Properties:     jsonProperties(jts.Properties),
+	}
+
+	var err error
+	if ts.Image, err = jsonImage(jts.Image, jts.ImageWidth, jts.ImageHeight, jts.TransparentColor); err != nil {
+		return nil, err
+	}
+	if jts.TileOffset != nil {
+		ts.TileOffset = &TilesetTileOffset{X: int(jts.TileOffset.X), Y: int(jts.TileOffset.Y)}
+	}
+	if g := jts.Grid; g != nil {
+		ts.Grid = &TilesetGrid{Orientation: g.Orientation, Width: g.Width, Height: g.Height}
+	}
+	if t := jts.Transformations; t != nil {
+		ts.Transformations = &TilesetTransformations{
+			HFlip:               t.HFlip,
+			VFlip:               t.VFlip,
+			Rotate:              t.Rotate,
+			PreferUntransformed: t.PreferUntransformed,
+		}
+	}
+
+	for _, t := range jts.Terrains {
+		ts.TerrainTypes = append(ts.TerrainTypes, &Terrain{
+			Name:       t.Name,
+			Tile:       t.Tile,
+			Properties: jsonProperties(t.Properties),
+		})
+	}
+
+	for _, jt := range jts.Tiles {
+		t, err := jt.toTilesetTile()
+		if err != nil {
+			return nil, elementError("tile", "", jt.ID, err)
+		}
+		ts.Tiles = append(ts.Tiles, t)
+	}
+
+	for _, jw := range jts.WangSets {
+		w := &WangSet{
+			Name:   jw.Name,
+			Class:  jw.Class,
+			Type:   jw.Type,
+			TileID: jw.Tile,
+		}
+		for _, c := range jw.Colors {
+			w.WangColors = append(w.WangColors, &WangColor{
+				Name:        c.Name,
+				Class:       c.Class,
+				Color:       c.Color,
+				TileID:      c.Tile,
+				Probability: c.Probability,
+			})
+		}
+		for _, t := range jw.WangTiles {
+			ids := make([]string, len(t.WangID))
+			for i, id := range t.WangID {
+				ids[i] = strconv.FormatUint(uint64(id), 10)
+			}
+			w.WangTiles = append(w.WangTiles, &WangTile{TileID: t.TileID, WangID: strings.Join(ids, ",")})
+		}
+		ts.WangSets = append(ts.WangSets, w)
+	}
+
+	return ts, nil
+}
+
+type jsonTerrain struct {
+	Name       string          `json:"name"`
+	Tile       uint32          `json:"tile"`
+	Properties []*jsonProperty `json:"properties,omitempty"`
+}
+
+type jsonTilesetTile struct {
+	ID               uint32            `json:"id"`
+	Type             string            `json:"type,omitempty"`
+	Class            string            `json:"class,omitempty"`
+	Image            string            `json:"image,omitempty"`
+	ImageWidth       int               `json:"imagewidth,omitempty"`
+	ImageHeight      int               `json:"imageheight,omitempty"`
+	TransparentColor string            `json:"transparentcolor,omitempty"`
+	X                int               `json:"x,omitempty"`
+	Y                int               `json:"y,omitempty"`
+	Width            int               `json:"width,omitempty"`
+	Height           int               `json:"height,omitempty"`
+	Terrain          []int             `json:"terrain,omitempty"`
+	Probability      float32           `json:"probability,omitempty"`
+	Properties       []*jsonProperty   `json:"properties,omitempty"`
+	ObjectGroup      *jsonLayer        `json:"objectgroup,omitempty"`
+	Animation        []*AnimationFrame `json:"animation,omitempty"`
+}
+
+func (jt *jsonTilesetTile) toTilesetTile() (*TilesetTile, error) {
+	t := &TilesetTile{
+		ID:          jt.ID,
+		Type:        jt.Type,
+		Class:       jt.Class,
+		X:           jt.X,
+		Y:           jt.Y,
+		Width:       jt.Width,
+		Height:      jt.Height,
+		Probability: jt.Probability,
+		Properties:  jsonProperties(jt.Properties),
+		Animation:   jt.Animation,
+	}
+
+	if jt.Terrain != nil {
+		corners := make([]string, len(jt.Terrain))
+		for i, c := range jt.Terrain {
+			if c >= 0 {
+				corners[i] = strconv.Itoa(c)
+			}
+		}
+		t.Terrain = strings.Join(corners, ",")
+	}
+
+	var err error
+	if t.Image, err = jsonImage(jt.Image, jt.ImageWidth, jt.ImageHeight, jt.TransparentColor); err != nil {
+		return nil, err
+	}
+	if jt.ObjectGroup != nil {
+		g, err := jt.ObjectGroup.objectGroup()
+		if err != nil {
+			return nil, err
+		}
+		t.ObjectGroups = []*ObjectGroup{g}
+	}
+	return t, nil
+}
+
+type jsonWangSet struct {
+	Name      string           `json:"name"`
+	Class     string           `json:"class,omitempty"`
+	Type      string           `json:"type"`
+	Tile      int64            `json:"tile"`
+	Colors    []*jsonWangColor `json:"colors"`
+	WangTiles []*jsonWangTile  `json:"wangtiles"`
+}
+
+type jsonWangColor struct {
+	Name        string  `json:"name"`
+	Class       string  `json:"class,omitempty"`
+	Color       string  `json:"color"`
+	Tile        int64   `json:"tile"`
+	Probability float32 `json:"probability"`
+}
+
+type jsonWangTile struct {
+	TileID uint32   `json:"tileid"`
+	WangID []uint32 `json:"wangid"`
+}
@@ -0,0 +1,415 @@
+package tiled
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteJSON writes the map in the Tiled JSON format (.tmj), with its tile
+// layers as arrays of GIDs. Like WriteTMX, tilesets loaded from files stay
+// external tilesets, and paths are written relative to the directory the map
+// was loaded from. Images embedded in the map can't be written in JSON and
+// are left out.
+func (m *Map) WriteJSON(w io.Writer) error {
+	jm, err := m.toJSON()
+	if err != nil {
+		return err
+	}
+	e := json.NewEncoder(w)
+	e.SetIndent("", " ")
+	return e.Encode(jm)
+}
+
+// jsonEncodedString returns s as a JSON string
+func jsonEncodedString(s string) json.RawMessage {
+	b, _ := json.Marshal(s)
+	return b
+}
+
+// jsonColorString returns the text of an optional color
+func jsonColorString(c *HexColor) string {
+	if c == nil {
+		return ""
+	}
+	return c.String()
+}
+
+func toJSONProperties(props Properties) []*jsonProperty {
+	if len(props) == 0 {
+		return nil
+	}
+	res := make([]*jsonProperty, len(props))
+	for i, p := range props {
+		typ := p.Type
+		if typ == "" {
+			typ = "string"
+		}
+		res[i] = &jsonProperty{Name: p.Name, Type: typ, PropertyType: p.PropertyType, Value: jsonPropertyValue(p)}
+	}
+	return res
+}
+
+// jsonPropertyValue returns the value of a property as Tiled writes it, a
+// number, a boolean or an object of members for properties of these types
+// and a string otherwise. Values not matching their type are kept as strings.
+func jsonPropertyValue(p *Property) json.RawMessage {
+	switch p.Type {
+	case "int":
+		if n, err := strconv.ParseInt(p.Value, 10, 64); err == nil {
+			return json.RawMessage(strconv.FormatInt(n, 10))
+		}
+	case "object":
+		if n, err := strconv.ParseUint(p.Value, 10, 32); err == nil {
+			return json.RawMessage(strconv.FormatUint(n, 10))
+		}
+	case "float":
+		if f, err := strconv.ParseFloat(p.Value, 64); err == nil {
+			if b, err := json.Marshal(f); err == nil {
+				return b
+			}
+		}
+	case "bool":
+		if b, err := strconv.ParseBool(p.Value); err == nil {
+			return json.RawMessage(strconv.FormatBool(b))
+		}
+	case "class":
+		members := make(map[string]json.RawMessage, len(p.Properties))
+		for _, member := range p.Properties {
+			members[member.Name] = jsonPropertyValue(member)
+		}
+		b, _ := json.Marshal(members)
+		return b
+	}
+	return jsonEncodedString(p.Value)
+}
+
+func (m *Map) toJSON() (*jsonMap, error) {
+	version := m.Version
+	if version == "" {
+		version = "1.10"
+	}
+	jm := &jsonMap{
+		Type:            "map",
+		Version:         jsonEncodedString(version),
+		TiledVersion:    m.TiledVersion,
+		Class:           m.Class,
+		Orientation:     m.Orientation,
+		RenderOrder:     m.RenderOrder,
+		Width:           m.Width,
+		Height:          m.Height,
+		TileWidth:       m.TileWidth,
+		TileHeight:      m.TileHeight,
+		HexSideLength:   m.HexSideLength,
+		StaggerAxis:     m.StaggerAxis,
+		StaggerIndex:    m.StaggerIndex,
+		ParallaxOriginX: m.ParallaxOriginX,
+		ParallaxOriginY: m.ParallaxOriginY,
+		BackgroundColor: jsonColorString(m.BackgroundColor),
+		NextLayerID:     m.NextLayerID,
+		NextObjectID:    m.NextObjectID,
+		Tilesets:        []*jsonTileset{},
+	}
+	if jm.RenderOrder == "" {
+		jm.RenderOrder = "right-down"
+	}
+	if m.Properties != nil {
+		jm.Properties = toJSONProperties(*m.Properties)
+	}
+
+	for _, ts := range m.Tilesets {
+		jts, err := m.toJSONTileset(ts)
+		if err != nil {
+			return nil, err
+		}
+		jm.Tilesets = append(jm.Tilesets, jts)
+	}
+	jm.Layers = m.toJSONLayers(m.Layers, m.ObjectGroups, m.ImageLayers, m.Groups)
+	return jm, nil
+}
+
+// jsonPath returns the path of a file relative to the directory of the map
+func (m *Map) jsonPath(fullPath string) string {
+	return relativePath(m.baseDir, fullPath)
+}
+
+// jsonImageFields sets the image fields of a tileset, tile or image layer
+func (m *Map) jsonImageFields(img *Image, fullPath func(string) string, source *string, width, height *int, trans *string) {
+	if img == nil || img.Source == "" {
+		return
+	}
+	*source = m.jsonPath(fullPath(img.Source))
+	*width = img.Width
+	*height = img.Height
+	*trans = jsonColorString(img.Trans)
+}
+
+func (m *Map) toJSONTileset(ts *Tileset) (*jsonTileset, error) {
+	jts := &jsonTileset{FirstGID: ts.FirstGID}
+	if ts.Source != "" {
+		jts.Source = m.jsonPath(m.GetFileFullPath(ts.Source))
+		return jts, nil
+	}
+
+	jts.Name = ts.Name
+	jts.Class = ts.Class
+	jts.TileWidth = ts.TileWidth
+	jts.TileHeight = ts.TileHeight
+	jts.Spacing = ts.Spacing
+	jts.Margin = ts.Margin
+	jts.TileCount = ts.TileCount
+	jts.Columns = ts.Columns
+	jts.TileRenderSize = ts.TileRenderSize
+	jts.FillMode = ts.FillMode
+	jts.Properties = toJSONProperties(ts.Properties)
+	m.jsonImageFields(ts.Image, ts.GetFileFullPath, &jts.Image, &jts.ImageWidth, &jts.ImageHeight, &jts.TransparentColor)
+	if ts.TileOffset != nil {
+		jts.TileOffset = &jsonPoint{X: float64(ts.TileOffset.X), Y: float64(ts.TileOffset.Y)}
+	}
+	if g := ts.Grid; g != nil {
+		jts.Grid = &jsonGrid{Orientation: g.Orientation, Width: g.Width, Height: g.Height}
+	}
+	if t := ts.Transformations; t != nil {
+		jts.Transformations = &jsonTransforms{
+			HFlip:               t.HFlip,
+			VFlip:               t.VFlip,
+			Rotate:              t.Rotate,
+			PreferUntransformed: t.PreferUntransformed,
+		}
+	}
+
+	for _, t := range ts.TerrainTypes {
+		jts.Terrains = append(jts.Terrains, &jsonTerrain{
+			Name:       t.Name,
+			Tile:       t.Tile,
+			Properties: toJSONProperties(t.Properties),
+		})
+	}
+
+	for _, t := range ts.Tiles {
+		jt := &jsonTilesetTile{
+			ID:          t.ID,
+			Type:        t.Type,
+			Class:       t.Class,
+			X:           t.X,
+			Y:           t.Y,
+			Width:       t.Width,
+			Height:      t.Height,
+			Probability: t.Probability,
+			Properties:  toJSONProperties(t.Properties),
+			Animation:   t.Animation,
+		}
+		if t.Terrain != "" {
+			// Corners without terrain are written as -1
+			for _, c := range strings.Split(t.Terrain, ",") {
+				index, err := strconv.Atoi(strings.TrimSpace(c))
+				if err != nil {
+					index = -1
+				}
+				jt.Terrain = append(jt.Terrain, index)
+			}
+		}
+		m.jsonImageFields(t.Image, ts.GetFileFullPath, &jt.Image, &jt.ImageWidth, &jt.ImageHeight, &jt.TransparentColor)
+		// JSON tiles have a single object group
+		if len(t.ObjectGroups) > 0 {
+			jt.ObjectGroup = m.toJSONObjectGroup(t.ObjectGroups[0])
+		}
+		jts.Tiles = append(jts.Tiles, jt)
+	}
+
+	for _, w := range ts.WangSets {
+		jw := &jsonWangSet{
+			Name:      w.Name,
+			Class:     w.Class,
+			Type:      w.Type,
+			Tile:      w.TileID,
+			Colors:    []*jsonWangColor{},
+			WangTiles: []*jsonWangTile{},
+		}
+		for _, c := range w.WangColors {
+			jw.Colors = append(jw.Colors, &jsonWangColor{
+				Name:        c.Name,
+				Class:       c.Class,
+				Color:       c.Color,
+				Tile:        c.TileID,
+				Probability: c.Probability,
+			})
+		}
+		for _, t := range w.WangTiles {
+			id, err := w.wangID(t)
+			if err != nil {
+				return nil, err
+			}
+			wangID := make([]uint32, len(id))
+			for i, c := range id {
+				wangID[i] = uint32(c)
+			}
+			jw.WangTiles = append(jw.WangTiles, &jsonWangTile{TileID: t.TileID, WangID: wangID})
+		}
+		jts.WangSets = append(jts.WangSets, jw)
+	}
+
+	return jts, nil
+}
+
+// toJSONLayers converts layers of each kind, in the order WriteTMX writes
+// them
+func (m *Map) toJSONLayers(layers []*Layer, objectGroups []*ObjectGroup, imageLayers []*ImageLayer, groups []*Group) []*jsonLayer {
+	res := []*jsonLayer{}
+	for _, l := range layers {
+		res = append(res, m.toJSONLayer(l))
+	}
+	for _, g := range objectGroups {
+		res = append(res, m.toJSONObjectGroup(g))
+	}
+	for _, l := range imageLayers {
+		jl := &jsonLayer{
+			Type:       "imagelayer",
+			ID:         l.ID,
+			Name:       l.Name,
+			Class:      l.Class,
+			Opacity:    l.Opacity,
+			Visible:    l.Visible,
+			OffsetX:    float64(l.OffsetX),
+			OffsetY:    float64(l.OffsetY),
+			ParallaxX:  l.ParallaxX,
+			ParallaxY:  l.ParallaxY,
+			TintColor:  jsonColorString(l.TintColor),
+			Properties: toJSONProperties(l.Properties),
+			X:          float64(l.X),
+			Y:          float64(l.Y),
+			RepeatX:    l.RepeatX,
+			RepeatY:    l.RepeatY,
+		}
+		m.jsonImageFields(l.Image, m.GetFileFullPath, &jl.Image, &jl.ImageWidth, &jl.ImageHeight, &jl.TransparentColor)
+		res = append(res, jl)
+	}
+	for _, g := range groups {
+		res = append(res, &jsonLayer{
+			Type:       "group",
+			ID:         g.ID,
+			Name:       g.Name,
+			Class:      g.Class,
+			Opacity:    g.Opacity,
+			Visible:    g.Visible,
+			OffsetX:    float64(g.OffsetX),
+			OffsetY:    float64(g.OffsetY),
+			ParallaxX:  g.ParallaxX,
+			ParallaxY:  g.ParallaxY,
+			TintColor:  jsonColorString(g.TintColor),
+			Properties: toJSONProperties(g.Properties),
+			Layers:     m.toJSONLayers(g.Layers, g.ObjectGroups, g.ImageLayers, g.Groups),
+		})
+	}
+	return res
+}
+
+func (m *Map) toJSONLayer(l *Layer) *jsonLayer {
+	// Layers have a tile per cell, missing tiles are written empty
+	gids := make([]uint32, max(len(l.Tiles), m.Width*m.Height))
+	for i, tile := range l.Tiles {
+		gids[i] = tile.gid()
+	}
+	data, _ := json.Marshal(gids)
+
+	return &jsonLayer{
+		Type:       "tilelayer",
+		ID:         l.ID,
+		Name:       l.Name,
+		Class:      l.Class,
+		Opacity:    l.Opacity,
+		Visible:    l.Visible,
+		OffsetX:    float64(l.OffsetX),
+		OffsetY:    float64(l.OffsetY),
+		ParallaxX:  l.ParallaxX,
+		ParallaxY:  l.ParallaxY,
+		TintColor:  jsonColorString(l.TintColor),
+		Properties: toJSONProperties(l.Properties),
+		Width:      m.Width,
+		Height:     m.Height,
+		Data:       data,
+	}
+}
+
+func (m *Map) toJSONObjectGroup(g *ObjectGroup) *jsonLayer {
+	jl := &jsonLayer{
+		Type:       "objectgroup",
+		ID:         g.ID,
+		Name:       g.Name,
+		Class:      g.Class,
+		Opacity:    g.Opacity,
+		Visible:    g.Visible,
+		OffsetX:    float64(g.OffsetX),
+		OffsetY:    float64(g.OffsetY),
+		ParallaxX:  g.ParallaxX,
+		ParallaxY:  g.ParallaxY,
+		TintColor:  jsonColorString(g.TintColor),
+		Properties: toJSONProperties(g.Properties),
+		DrawOrder:  g.DrawOrder,
+		Color:      jsonColorString(g.Color),
+		Objects:    []*jsonObject{},
+	}
+	for _, o := range g.Objects {
+		jl.Objects = append(jl.Objects, m.toJSONObject(o))
+	}
+	return jl
+}
+
+func toJSONPoints(points *Points) []jsonPoint {
+	if points == nil {
+		return []jsonPoint{}
+	}
+	res := make([]jsonPoint, len(*points))
+	for i, p := range *points {
+		res[i] = jsonPoint{X: p.X, Y: p.Y}
+	}
+	return res
+}
+
+func (m *Map) toJSONObject(o *Object) *jsonObject {
+	jo := &jsonObject{
+		ID:         o.ID,
+		Name:       o.Name,
+		Type:       o.Type,
+		Class:      o.Class,
+		X:          o.X,
+		Y:          o.Y,
+		Width:      o.Width,
+		Height:     o.Height,
+		Rotation:   o.Rotation,
+		GID:        o.GID,
+		Visible:    o.Visible,
+		Properties: toJSONProperties(o.Properties),
+		Ellipse:    len(o.Ellipses) > 0,
+	}
+	if o.TemplateSource != "" {
+		jo.Template = m.jsonPath(m.GetFileFullPath(o.TemplateSource))
+	}
+	// JSON objects have a single shape
+	if len(o.Polygons) > 0 {
+		jo.Polygon = toJSONPoints(o.Polygons[0].Points)
+	}
+	if len(o.PolyLines) > 0 {
+		jo.PolyLine = toJSONPoints(o.PolyLines[0].Points)
+	}
+	if t := o.Text; t != nil {
+		jo.Text = &jsonText{
+			Text:       t.Text,
+			FontFamily: t.FontFamily,
+			PixelSize:  t.Size,
+			Wrap:       t.Wrap,
+			Bold:       t.Bold,
+			Italic:     t.Italic,
+			Underline:  t.Underline,
+			Strikeout:  t.Strikethrough,
+			Kerning:    t.Kerning,
+			HAlign:     t.HAlign,
+			VAlign:     t.VAlign,
+		}
+		if t.Color != nil && t.Color.c != (HexColor{}).c {
+			jo.Text.Color = t.Color.String()
+		}
+	}
+	return jo
+}
@@ -0,0 +1,194 @@
+package tiled
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const jsonTestMap = `{
+  "type": "map", "version": "1.10", "tiledversion": "1.10.2",
+  "orientation": "orthogonal", "renderorder": "right-down",
+  "width": 2, "height": 2, "tilewidth": 16, "tileheight": 16,
+  "infinite": false, "nextobjectid": 3, "backgroundcolor": "#ff0000",
+  "properties": [
+    {"name": "music", "type": "string", "value": "theme.ogg"},
+    {"name": "gravity", "type": "float", "value": 9.5},
+    {"name": "dark", "type": "bool", "value": true},
+    {"name": "boss", "type": "class", "propertytype": "Stats", "value": {"hp": 99, "alive": true, "resist": {"fire": 0.5}}}
+  ],
+  "tilesets": [{
+    "firstgid": 1, "name": "tiles", "tilewidth": 16, "tileheight": 16,
+    "tilecount": 4, "columns": 4, "image": "tiles.png", "imagewidth": 64, "imageheight": 16,
+    "tiles": [{"id": 1, "type": "wall", "animation": [{"tileid": 1, "duration": 100}, {"tileid": 2, "duration": 100}]}]
+  }],
+  "layers": [
+    {"type": "tilelayer", "id": 1, "name": "Ground", "width": 2, "height": 2, "opacity": 0.5,
+     "visible": true, "x": 0, "y": 0, "data": [1, 2, 0, 2147483652]},
+    {"type": "group", "id": 2, "name": "Group", "visible": false, "layers": [
+      {"type": "tilelayer", "id": 3, "name": "Encoded", "width": 2, "height": 2,
+       "encoding": "base64", "data": "AQAAAAIAAAADAAAABAAAAA=="},
+      {"type": "imagelayer", "id": 4, "name": "Sky", "image": "sky.png", "repeatx": true}
+    ]},
+    {"type": "objectgroup", "id": 5, "name": "Objects", "draworder": "index", "objects": [
+      {"id": 1, "name": "zone", "type": "trigger", "x": 4, "y": 8, "width": 0, "height": 0,
+       "rotation": 0, "visible": true, "polygon": [{"x": 0, "y": 0}, {"x": 8, "y": 0}, {"x": 0, "y": 8}]},
+      {"id": 2, "x": 1, "y": 2, "width": 30, "height": 10, "text": {"text": "Hello", "wrap": true, "color": "#00ff00"}}
+    ]}
+  ]
+}`
+
+func TestLoadJSONReader(t *testing.T) {
+	m, err := LoadJSONReader(GetAssetsDirectory(), bytes.NewBufferString(jsonTestMap))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "1.10", m.Version)
+	assert.Equal(t, 2, m.Width)
+	assert.Equal(t, uint32(3), m.NextObjectID)
+	assert.Equal(t, "#ff0000", m.BackgroundColor.String())
+	assert.Equal(t, "theme.ogg", m.Properties.GetString("music"))
+	assert.Equal(t, 9.5, m.Properties.GetFloat("gravity"))
+	assert.True(t, m.Properties.GetBool("dark"))
+	boss := m.Properties.GetClass("boss")
+	assert.Equal(t, 99, boss.GetInt("hp"))
+	assert.True(t, boss.GetBool("alive"))
+	assert.Equal(t, 0.5, boss.GetClass("resist").GetFloat("fire"))
+
+	ts := m.Tilesets[0]
+	assert.Equal(t, "tiles.png", ts.Image.Source)
+	assert.Len(t, ts.Tiles[0].Animation, 2)
+
+	assert.Len(t, m.Layers, 1)
+	ground := m.Layers[0]
+	assert.Equal(t, float32(0.5), ground.Opacity)
+	assert.True(t, ground.Visible)
+	assert.Equal(t, uint32(1), ground.Tiles[1].ID)
+	assert.True(t, ground.Tiles[2].IsNil())
+	assert.True(t, ground.Tiles[3].HorizontalFlip)
+	assert.Equal(t, uint32(3), ground.Tiles[3].ID)
+
+	group := m.Groups[0]
+	assert.False(t, group.Visible)
+	assert.Equal(t, float32(1), group.Opacity)
+	assert.Equal(t, uint32(2), group.Layers[0].Tiles[2].ID)
+	assert.Equal(t, "sky.png", group.ImageLayers[0].Image.Source)
+	assert.True(t, group.ImageLayers[0].RepeatX)
+
+	objects := m.ObjectGroups[0]
+	assert.Equal(t, DrawOrderIndex, objects.DrawOrder)
+	zone := objects.Objects[0]
+	assert.Equal(t, "trigger", zone.Type)
+	assert.True(t, zone.Visible)
+	assert.Len(t, *zone.Polygons[0].Points, 3)
+	label := objects.Objects[1]
+	assert.True(t, label.Visible)
+	assert.Equal(t, "Hello", label.Text.Text)
+	assert.Equal(t, 16, label.Text.Size)
+	assert.True(t, label.Text.Wrap)
+	assert.Equal(t, "#00ff00", label.Text.Color.String())
+}
+
+func TestLoadJSONReaderUnknownLayer(t *testing.T) {
+	_, err := LoadJSONReader(GetAssetsDirectory(), bytes.NewBufferString(`{"layers": [{"type": "hologram"}]}`))
+	assert.ErrorIs(t, err, ErrUnknownLayerType)
+}
+
+func TestJSONTileset(t *testing.T) {
+	tsj := `{"type": "tileset", "name": "props", "tilewidth": 8, "tileheight": 8,
+  "tilecount": 2, "columns": 2, "image": "props.png", "imagewidth": 16, "imageheight": 8,
+  "tiles": [{"id": 1, "properties": [{"name": "solid", "type": "bool", "value": true}]}]}`
+	fsys := fstest.MapFS{
+		"tilesets/props.tsj": {Data: []byte(tsj)},
+		"level.tmx": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="1" tilewidth="8" tileheight="8">
+<tileset firstgid="3" source="tilesets/props.tsj"/>
+<layer id="1" name="Props" width="2" height="1"><data encoding="csv">3,4</data></layer>
+</map>`)},
+	}
+
+	m, err := LoadFile("level.tmx", WithFileSystem(fsys))
+	assert.NoError(t, err)
+	ts := m.Tilesets[0]
+	assert.Equal(t, uint32(3), ts.FirstGID)
+	assert.Equal(t, "tilesets/props.tsj", ts.Source)
+	assert.Equal(t, "props", ts.Name)
+	assert.Equal(t, "tilesets", ts.BaseDir())
+	assert.Equal(t, "props.png", ts.Image.Source)
+	assert.True(t, m.Layers[0].Tiles[1].Tileset.Tiles[0].Properties.GetBool("solid"))
+
+	ts, err = LoadTilesetReader("tilesets", bytes.NewBufferString(" \n"+tsj))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ts.TileCount)
+	assert.True(t, ts.SourceLoaded)
+}
+
+func TestWriteJSON(t *testing.T) {
+	dir := GetAssetsDirectory()
+	m, err := LoadJSONReader(dir, bytes.NewBufferString(jsonTestMap))
+	assert.NoError(t, err)
+
+	var out bytes.Buffer
+	assert.NoError(t, m.WriteJSON(&out))
+	assert.Contains(t, out.String(), `"value": 9.5`)
+
+	written, err := LoadJSONReader(dir, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, "1.10", written.Version)
+	assert.Equal(t, "#ff0000", written.BackgroundColor.String())
+	assert.Equal(t, 9.5, written.Properties.GetFloat("gravity"))
+	assert.True(t, written.Properties.GetBool("dark"))
+	boss := written.Properties.GetClass("boss")
+	assert.Equal(t, 99, boss.GetInt("hp"))
+	assert.Equal(t, 0.5, boss.GetClass("resist").GetFloat("fire"))
+
+	ts := written.Tilesets[0]
+	assert.Equal(t, "tiles.png", ts.Image.Source)
+	assert.Equal(t, []*AnimationFrame{{TileID: 1, Duration: 100}, {TileID: 2, Duration: 100}}, ts.Tiles[0].Animation)
+
+	ground := written.Layers[0]
+	assert.Equal(t, float32(0.5), ground.Opacity)
+	assert.True(t, ground.Tiles[2].IsNil())
+	assert.True(t, ground.Tiles[3].HorizontalFlip)
+	assert.Equal(t, uint32(3), ground.Tiles[3].ID)
+
+	group := written.Groups[0]
+	assert.False(t, group.Visible)
+	assert.Equal(t, uint32(2), group.Layers[0].Tiles[2].ID)
+	assert.True(t, group.ImageLayers[0].RepeatX)
+
+	objects := written.ObjectGroups[0]
+	assert.Len(t, *objects.Objects[0].Polygons[0].Points, 3)
+	label := objects.Objects[1]
+	assert.True(t, label.Visible)
+	assert.True(t, label.Text.Wrap)
+	assert.True(t, label.Text.Kerning)
+	assert.Equal(t, "#00ff00", label.Text.Color.String())
+}
+
+func TestWriteJSONExternalTilesets(t *testing.T) {
+	dir := GetAssetsDirectory()
+	m, err := LoadFile(filepath.Join(dir, "test_template.tmx"))
+	assert.NoError(t, err)
+
+	var out bytes.Buffer
+	assert.NoError(t, m.WriteJSON(&out))
+
+	written, err := LoadJSONReader(dir, &out)
+	assert.NoError(t, err)
+	if assert.Len(t, written.Tilesets, len(m.Tilesets)) {
+		for i, ts := range written.Tilesets {
+			assert.Equal(t, m.Tilesets[i].Source, ts.Source)
+			assert.Equal(t, m.Tilesets[i].Name, ts.Name)
+		}
+	}
+	for i, g := range written.ObjectGroups {
+		for j, o := range g.Objects {
+			orig := m.ObjectGroups[i].Objects[j]
+			assert.Equal(t, orig.TemplateSource, o.TemplateSource)
+			assert.Equal(t, orig.GID, o.GID)
+		}
+	}
+}
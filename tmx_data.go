@@ -28,6 +28,7 @@ import (
 	"compress/zlib"
 	"encoding/base64"
 	"errors"
+	"fmt"
 	"io"
 	"strconv"
 	"strings"
@@ -36,6 +37,9 @@ import (
 // ErrUnknownCompression error is returned when file contains invalid compression method
 var ErrUnknownCompression = errors.New("tiled: invalid compression method")
 
+// ErrInvalidLayerData error is returned when tile layer data can't be decoded
+var ErrInvalidLayerData = errors.New("tiled: invalid layer data")
+
 // Data is raw data
 type Data struct {
 	// The encoding used to encode the tile layer data. When used, it can be "base64" and "csv" at the moment.
@@ -65,21 +69,26 @@ func (d *Data) decodeBase64() (data []byte, err error) {
 	case "gzip":
 		comr, err = gzip.NewReader(encr)
 		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrInvalidLayerData, err)
 			return
 		}
 	case "zlib":
 		comr, err = zlib.NewReader(encr)
 		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrInvalidLayerData, err)
 			return
 		}
 	case "":
 		comr = encr
 	default:
-		err = ErrUnknownCompression
+		err = fmt.Errorf("%w %q", ErrUnknownCompression, d.Compression)
 		return
 	}
 
-	return io.ReadAll(comr)
+	if data, err = io.ReadAll(comr); err != nil {
+		err = fmt.Errorf("%w: %w", ErrInvalidLayerData, err)
+	}
+	return
 }
 
 func (d *Data) decodeCSV() ([]uint32, error) {
@@ -98,7 +107,7 @@ func (d *Data) decodeCSV() ([]uint32, error) {
 		var id uint64
 		var err error
 		if id, err = strconv.ParseUint(s, 10, 32); err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w: csv value of tile %d: %w", ErrInvalidLayerData, i, err)
 		}
 		gids[i] = uint32(id)
 	}
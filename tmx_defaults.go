@@ -35,24 +35,31 @@ type (
 	aliasObject      Object
 	aliasObjectGroup ObjectGroup
 	aliasText        Text
+	aliasTileset     Tileset
 )
 
 // SetDefaults provides default values for Group.
 func (a *aliasGroup) SetDefaults() {
 	a.Opacity = 1
 	a.Visible = true
+	a.ParallaxX = 1
+	a.ParallaxY = 1
 }
 
 // SetDefaults provides default values for ImageLayer.
 func (a *aliasImageLayer) SetDefaults() {
 	a.Opacity = 1
 	a.Visible = true
+	a.ParallaxX = 1
+	a.ParallaxY = 1
 }
 
 // SetDefaults provides default values for Layer.
 func (a *aliasLayer) SetDefaults() {
 	a.internalLayer.Opacity = 1
 	a.internalLayer.Visible = true
+	a.internalLayer.ParallaxX = 1
+	a.internalLayer.ParallaxY = 1
 }
 
 // SetDefaults provides default values for Map.
@@ -69,6 +76,8 @@ func (a *aliasObject) SetDefaults() {
 func (a *aliasObjectGroup) SetDefaults() {
 	a.Visible = true
 	a.Opacity = 1
+	a.ParallaxX = 1
+	a.ParallaxY = 1
 }
 
 // SetDefaults provides default values for Text.
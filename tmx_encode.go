@@ -0,0 +1,546 @@
+package tiled
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// xmlAttrs builds the attributes of an element, leaving out empty optional ones
+type xmlAttrs []xml.Attr
+
+func (a *xmlAttrs) add(name, value string) {
+	*a = append(*a, xml.Attr{Name: xml.Name{Local: name}, Value: value})
+}
+
+func (a *xmlAttrs) str(name, value string) {
+	if value != "" {
+		a.add(name, value)
+	}
+}
+
+func (a *xmlAttrs) int(name string, value int) {
+	if value != 0 {
+		a.add(name, strconv.Itoa(value))
+	}
+}
+
+func (a *xmlAttrs) uint(name string, value uint32) {
+	if value != 0 {
+		a.add(name, strconv.FormatUint(uint64(value), 10))
+	}
+}
+
+func (a *xmlAttrs) float(name string, value float64) {
+	if value != 0 {
+		a.add(name, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+}
+
+// opacity adds an opacity attribute when it isn't the default of 1
+func (a *xmlAttrs) opacity(value float32) {
+	if value != 1 {
+		a.add("opacity", strconv.FormatFloat(float64(value), 'f', -1, 32))
+	}
+}
+
+// visible adds a visible attribute for hidden elements
+func (a *xmlAttrs) visible(value bool) {
+	if !value {
+		a.add("visible", "0")
+	}
+}
+
+// bool adds a boolean attribute as 1 or 0
+func (a *xmlAttrs) bool(name string, value bool) {
+	if value {
+		a.add(name, "1")
+	} else {
+		a.add(name, "0")
+	}
+}
+
+// parallax adds the parallax factors of layers, 1 being the default
+func (a *xmlAttrs) parallax(x, y float32) {
+	if x != 1 {
+		a.add("parallaxx", strconv.FormatFloat(float64(x), 'f', -1, 32))
+	}
+	if y != 1 {
+		a.add("parallaxy", strconv.FormatFloat(float64(y), 'f', -1, 32))
+	}
+}
+
+func (a *xmlAttrs) color(name string, value *HexColor) {
+	if value != nil {
+		a.add(name, value.String())
+	}
+}
+
+// tmxEncoder writes maps in TMX format. The first error stops the encoding
+// and is kept in err.
+type tmxEncoder struct {
+	e *xml.Encoder
+	// Directory of the written file, which file paths are made relative to
+	dir string
+	err error
+}
+
+func newTMXEncoder(w io.Writer, dir string) *tmxEncoder {
+	e := xml.NewEncoder(w)
+	e.Indent("", " ")
+	return &tmxEncoder{e: e, dir: dir}
+}
+
+func (enc *tmxEncoder) token(t xml.Token) {
+	if enc.err == nil {
+		enc.err = enc.e.EncodeToken(t)
+	}
+}
+
+func (enc *tmxEncoder) start(name string, attrs xmlAttrs) {
+	enc.token(xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+}
+
+func (enc *tmxEncoder) end(name string) {
+	enc.token(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+func (enc *tmxEncoder) element(name string, attrs xmlAttrs) {
+	enc.start(name, attrs)
+	enc.end(name)
+}
+
+// path returns the path of a file relative to the written file
+func (enc *tmxEncoder) path(fileName string) string {
+	return relativePath(enc.dir, fileName)
+}
+
+// relativePath returns the path of a file relative to dir, with forward
+// slashes as Tiled writes them
+func relativePath(dir, fileName string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return filepath.ToSlash(fileName)
+	}
+	abs, err := filepath.Abs(fileName)
+	if err != nil {
+		return filepath.ToSlash(fileName)
+	}
+	if rel, err := filepath.Rel(dir, abs); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(abs)
+}
+
+func (enc *tmxEncoder) flush() error {
+	if enc.err == nil {
+		enc.err = enc.e.Flush()
+	}
+	return enc.err
+}
+
+func (enc *tmxEncoder) properties(props Properties) {
+	if len(props) == 0 {
+		return
+	}
+	enc.start("properties", nil)
+	for _, p := range props {
+		a := xmlAttrs{}
+		a.add("name", p.Name)
+		a.str("type", p.Type)
+		a.str("propertytype", p.PropertyType)
+		if p.Type != "class" {
+			a.add("value", p.Value)
+			enc.element("property", a)
+			continue
+		}
+		if len(p.Properties) == 0 {
+			enc.element("property", a)
+			continue
+		}
+		enc.start("property", a)
+		enc.properties(p.Properties)
+		enc.end("property")
+	}
+	enc.end("properties")
+}
+
+func (enc *tmxEncoder) image(img *Image, fullPath func(string) string) {
+	if img == nil {
+		return
+	}
+	a := xmlAttrs{}
+	a.str("format", img.Format)
+	if img.Source != "" {
+		a.add("source", enc.path(fullPath(img.Source)))
+	}
+	a.color("trans", img.Trans)
+	a.int("width", img.Width)
+	a.int("height", img.Height)
+	if !img.Embedded() {
+		enc.element("image", a)
+		return
+	}
+
+	enc.start("image", a)
+	a = xmlAttrs{}
+	a.str("encoding", img.Data.Encoding)
+	a.str("compression", img.Data.Compression)
+	enc.start("data", a)
+	enc.token(xml.CharData(bytes.TrimSpace(img.Data.RawData)))
+	enc.end("data")
+	enc.end("image")
+}
+
+// WriteTMX writes the map in TMX format, with its tile layers encoded as CSV.
+// Tilesets loaded from files stay external tilesets, and paths of tilesets,
+// templates and images are written relative to the directory the map was
+// loaded from, so the map can be saved next to the original.
+func (m *Map) WriteTMX(w io.Writer) error {
+	return newTMXEncoder(w, m.baseDir).encodeMap(m)
+}
+
+func (enc *tmxEncoder) encodeMap(m *Map) error {
+	enc.token(xml.ProcInst{Target: "xml", Inst: []byte(`version="1.0" encoding="UTF-8"`)})
+	enc.token(xml.CharData("\n"))
+
+	a := xmlAttrs{}
+	version := m.Version
+	if version == "" {
+		version = "1.10"
+	}
+	a.add("version", version)
+	a.str("tiledversion", m.TiledVersion)
+	a.str("class", m.Class)
+	a.add("orientation", m.Orientation)
+	a.str("renderorder", m.RenderOrder)
+	a.add("width", strconv.Itoa(m.Width))
+	a.add("height", strconv.Itoa(m.Height))
+	a.add("tilewidth", strconv.Itoa(m.TileWidth))
+	a.add("tileheight", strconv.Itoa(m.TileHeight))
+	a.int("hexsidelength", m.HexSideLength)
+	a.str("staggeraxis", string(m.StaggerAxis))
+	a.str("staggerindex", string(m.StaggerIndex))
+	a.float("parallaxoriginx", m.ParallaxOriginX)
+	a.float("parallaxoriginy", m.ParallaxOriginY)
+	a.color("backgroundcolor", m.BackgroundColor)
+	a.uint("nextlayerid", m.NextLayerID)
+	a.uint("nextobjectid", m.NextObjectID)
+	enc.start("map", a)
+
+	if m.Properties != nil {
+		enc.properties(*m.Properties)
+	}
+	for _, ts := range m.Tilesets {
+		enc.tileset(m, ts)
+	}
+	enc.layers(m, m.Layers, m.ObjectGroups, m.ImageLayers, m.Groups)
+
+	enc.end("map")
+	return enc.flush()
+}
+
+func (enc *tmxEncoder) tileset(m *Map, ts *Tileset) {
+	a := xmlAttrs{}
+	a.uint("firstgid", ts.FirstGID)
+	if ts.Source != "" {
+		a.add("source", enc.path(m.GetFileFullPath(ts.Source)))
+		enc.element("tileset", a)
+		return
+	}
+
+	a.add("name", ts.Name)
+	a.str("class", ts.Class)
+	a.add("tilewidth", strconv.Itoa(ts.TileWidth))
+	a.add("tileheight", strconv.Itoa(ts.TileHeight))
+	a.int("spacing", ts.Spacing)
+	a.int("margin", ts.Margin)
+	a.add("tilecount", strconv.Itoa(ts.TileCount))
+	a.add("columns", strconv.Itoa(ts.Columns))
+	a.str("tilerendersize", string(ts.TileRenderSize))
+	a.str("fillmode", string(ts.FillMode))
+	enc.start("tileset", a)
+
+	if ts.TileOffset != nil {
+		a := xmlAttrs{}
+		a.add("x", strconv.Itoa(ts.TileOffset.X))
+		a.add("y", strconv.Itoa(ts.TileOffset.Y))
+		enc.element("tileoffset", a)
+	}
+	if ts.Grid != nil {
+		a := xmlAttrs{}
+		a.add("orientation", ts.Grid.Orientation)
+		a.add("width", strconv.Itoa(ts.Grid.Width))
+		a.add("height", strconv.Itoa(ts.Grid.Height))
+		enc.element("grid", a)
+	}
+	if t := ts.Transformations; t != nil {
+		a := xmlAttrs{}
+		a.bool("hflip", t.HFlip)
+		a.bool("vflip", t.VFlip)
+		a.bool("rotate", t.Rotate)
+		a.bool("preferuntransformed", t.PreferUntransformed)
+		enc.element("transformations", a)
+	}
+	enc.properties(ts.Properties)
+	enc.image(ts.Image, ts.GetFileFullPath)
+
+	for _, t := range ts.Tiles {
+		a := xmlAttrs{}
+		a.add("id", strconv.FormatUint(uint64(t.ID), 10))
+		a.str("type", t.Type)
+		a.str("class", t.Class)
+		enc.start("tile", a)
+		enc.properties(t.Properties)
+		enc.image(t.Image, ts.GetFileFullPath)
+		for _, g := range t.ObjectGroups {
+			enc.objectGroup(m, g)
+		}
+		if len(t.Animation) > 0 {
+			enc.start("animation", nil)
+			for _, f := range t.Animation {
+				a := xmlAttrs{}
+				a.add("tileid", strconv.FormatUint(uint64(f.TileID), 10))
+				a.add("duration", strconv.FormatUint(uint64(f.Duration), 10))
+				enc.element("frame", a)
+			}
+			enc.end("animation")
+		}
+		enc.end("tile")
+	}
+
+	enc.end("tileset")
+}
+
+// layers writes layers of each kind. The order of layers of different kinds
+// is not kept by Map, so tile layers come first, then object groups, image
+// layers and groups.
+func (enc *tmxEncoder) layers(m *Map, layers []*Layer, objectGroups []*ObjectGroup, imageLayers []*ImageLayer, groups []*Group) {
+	for _, l := range layers {
+		enc.layer(m, l)
+	}
+	for _, g := range objectGroups {
+		enc.objectGroup(m, g)
+	}
+	for _, l := range imageLayers {
+		enc.imageLayer(m, l)
+	}
+	for _, g := range groups {
+		enc.group(m, g)
+	}
+}
+
+// gid returns the global ID of the tile with its flip flags
+func (t *LayerTile) gid() uint32 {
+	if t == nil || t.Nil || t.Tileset == nil {
+		return 0
+	}
+	gid := t.Tileset.FirstGID + t.ID
+	if t.HorizontalFlip {
+		gid |= tileHorizontalFlipMask
+	}
+	if t.VerticalFlip {
+		gid |= tileVerticalFlipMask
+	}
+	if t.DiagonalFlip {
+		gid |= tileDiagonalFlipMask
+	}
+	return gid
+}
+
+func (enc *tmxEncoder) layer(m *Map, l *Layer) {
+	a := xmlAttrs{}
+	a.uint("id", l.ID)
+	a.add("name", l.Name)
+	a.str("class", l.Class)
+	a.add("width", strconv.Itoa(m.Width))
+	a.add("height", strconv.Itoa(m.Height))
+	a.opacity(l.Opacity)
+	a.visible(l.Visible)
+	a.int("offsetx", l.OffsetX)
+	a.int("offsety", l.OffsetY)
+	a.parallax(l.ParallaxX, l.ParallaxY)
+	a.color("tintcolor", l.TintColor)
+	enc.start("layer", a)
+	enc.properties(l.Properties)
+
+	// Layers have a tile per cell, missing tiles are written empty
+	size := max(len(l.Tiles), m.Width*m.Height)
+	var sb strings.Builder
+	sb.WriteByte('\n')
+	for i := range size {
+		var tile *LayerTile
+		if i < len(l.Tiles) {
+			tile = l.Tiles[i]
+		}
+		sb.WriteString(strconv.FormatUint(uint64(tile.gid()), 10))
+		if i < size-1 {
+			sb.WriteByte(',')
+		}
+		if m.Width > 0 && (i+1)%m.Width == 0 {
+			sb.WriteByte('\n')
+		}
+	}
+
+	a = xmlAttrs{}
+	a.add("encoding", "csv")
+	enc.start("data", a)
+	enc.token(xml.CharData(sb.String()))
+	enc.end("data")
+
+	enc.end("layer")
+}
+
+func (enc *tmxEncoder) objectGroup(m *Map, g *ObjectGroup) {
+	a := xmlAttrs{}
+	a.uint("id", g.ID)
+	a.str("name", g.Name)
+	a.str("class", g.Class)
+	a.color("color", g.Color)
+	a.opacity(g.Opacity)
+	a.visible(g.Visible)
+	a.int("offsetx", g.OffsetX)
+	a.int("offsety", g.OffsetY)
+	a.parallax(g.ParallaxX, g.ParallaxY)
+	a.color("tintcolor", g.TintColor)
+	a.str("draworder", g.DrawOrder)
+	enc.start("objectgroup", a)
+	enc.properties(g.Properties)
+	for _, o := range g.Objects {
+		enc.object(m, o)
+	}
+	enc.end("objectgroup")
+}
+
+func formatPoints(points *Points) string {
+	if points == nil {
+		return ""
+	}
+	s := make([]string, len(*points))
+	for i, p := range *points {
+		s[i] = strconv.FormatFloat(p.X, 'f', -1, 64) + "," + strconv.FormatFloat(p.Y, 'f', -1, 64)
+	}
+	return strings.Join(s, " ")
+}
+
+func (enc *tmxEncoder) object(m *Map, o *Object) {
+	a := xmlAttrs{}
+	a.uint("id", o.ID)
+	if o.TemplateSource != "" {
+		a.add("template", enc.path(m.GetFileFullPath(o.TemplateSource)))
+	}
+	a.str("name", o.Name)
+	a.str("type", o.Type)
+	a.str("class", o.Class)
+	a.add("x", strconv.FormatFloat(o.X, 'f', -1, 64))
+	a.add("y", strconv.FormatFloat(o.Y, 'f', -1, 64))
+	a.float("width", o.Width)
+	a.float("height", o.Height)
+	a.float("rotation", o.Rotation)
+	a.uint("gid", o.GID)
+	a.visible(o.Visible)
+	enc.start("object", a)
+	enc.properties(o.Properties)
+
+	for range o.Ellipses {
+		enc.element("ellipse", nil)
+	}
+	for _, p := range o.Polygons {
+		a := xmlAttrs{}
+		a.add("points", formatPoints(p.Points))
+		enc.element("polygon", a)
+	}
+	for _, p := range o.PolyLines {
+		a := xmlAttrs{}
+		a.add("points", formatPoints(p.Points))
+		enc.element("polyline", a)
+	}
+	if o.Text != nil {
+		enc.text(o.Text)
+	}
+
+	enc.end("object")
+}
+
+func (enc *tmxEncoder) text(t *Text) {
+	boolAttr := func(a *xmlAttrs, name string, value, def bool) {
+		if value == def {
+			return
+		}
+		if value {
+			a.add(name, "1")
+		} else {
+			a.add(name, "0")
+		}
+	}
+
+	a := xmlAttrs{}
+	if t.FontFamily != "sans-serif" {
+		a.str("fontfamily", t.FontFamily)
+	}
+	if t.Size != 16 {
+		a.int("pixelsize", t.Size)
+	}
+	boolAttr(&a, "wrap", t.Wrap, false)
+	if t.Color != nil && t.Color.c != (HexColor{}).c {
+		a.color("color", t.Color)
+	}
+	boolAttr(&a, "bold", t.Bold, false)
+	boolAttr(&a, "italic", t.Italic, false)
+	boolAttr(&a, "underline", t.Underline, false)
+	boolAttr(&a, "strikeout", t.Strikethrough, false)
+	boolAttr(&a, "kerning", t.Kerning, true)
+	if t.HAlign != "left" {
+		a.str("halign", t.HAlign)
+	}
+	if t.VAlign != "top" {
+		a.str("valign", t.VAlign)
+	}
+
+	enc.start("text", a)
+	enc.token(xml.CharData(t.Text))
+	enc.end("text")
+}
+
+func (enc *tmxEncoder) imageLayer(m *Map, l *ImageLayer) {
+	a := xmlAttrs{}
+	a.uint("id", l.ID)
+	a.str("name", l.Name)
+	a.str("class", l.Class)
+	a.int("offsetx", l.OffsetX)
+	a.int("offsety", l.OffsetY)
+	a.opacity(l.Opacity)
+	a.visible(l.Visible)
+	a.parallax(l.ParallaxX, l.ParallaxY)
+	a.color("tintcolor", l.TintColor)
+	if l.RepeatX {
+		a.add("repeatx", "1")
+	}
+	if l.RepeatY {
+		a.add("repeaty", "1")
+	}
+	enc.start("imagelayer", a)
+	enc.properties(l.Properties)
+	enc.image(l.Image, m.GetFileFullPath)
+	enc.end("imagelayer")
+}
+
+func (enc *tmxEncoder) group(m *Map, g *Group) {
+	a := xmlAttrs{}
+	a.uint("id", g.ID)
+	a.str("name", g.Name)
+	a.str("class", g.Class)
+	a.int("offsetx", g.OffsetX)
+	a.int("offsety", g.OffsetY)
+	a.opacity(g.Opacity)
+	a.visible(g.Visible)
+	a.parallax(g.ParallaxX, g.ParallaxY)
+	a.color("tintcolor", g.TintColor)
+	enc.start("group", a)
+	enc.properties(g.Properties)
+	enc.layers(m, g.Layers, g.ObjectGroups, g.ImageLayers, g.Groups)
+	enc.end("group")
+}
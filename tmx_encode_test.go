@@ -0,0 +1,76 @@
+package tiled
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTMX(t *testing.T) {
+	dir := GetAssetsDirectory()
+	m, err := LoadFile(filepath.Join(dir, "test_template.tmx"))
+	assert.NoError(t, err)
+
+	var out bytes.Buffer
+	assert.NoError(t, m.WriteTMX(&out))
+	for _, ts := range m.Tilesets {
+		if ts.Source != "" {
+			assert.Contains(t, out.String(), `source="`+ts.Source+`"`)
+		}
+	}
+
+	written, err := LoadReader(dir, &out)
+	assert.NoError(t, err)
+	assert.Equal(t, m.Width, written.Width)
+	if assert.Len(t, written.Tilesets, len(m.Tilesets)) {
+		for i, ts := range written.Tilesets {
+			assert.Equal(t, m.Tilesets[i].Source, ts.Source)
+			assert.Equal(t, m.Tilesets[i].Name, ts.Name)
+		}
+	}
+	if assert.Len(t, written.Layers, len(m.Layers)) {
+		for i, l := range written.Layers {
+			for j, tile := range l.Tiles {
+				assert.Equal(t, m.Layers[i].Tiles[j].gid(), tile.gid())
+			}
+		}
+	}
+	if assert.Len(t, written.ObjectGroups, len(m.ObjectGroups)) {
+		for i, g := range written.ObjectGroups {
+			if assert.Len(t, g.Objects, len(m.ObjectGroups[i].Objects)) {
+				for j, o := range g.Objects {
+					orig := m.ObjectGroups[i].Objects[j]
+					assert.Equal(t, orig.TemplateSource, o.TemplateSource)
+					assert.Equal(t, orig.GID, o.GID)
+					assert.Equal(t, orig.X, o.X)
+				}
+			}
+		}
+	}
+}
+
+func TestWriteTMXGenerated(t *testing.T) {
+	m, err := LoadReader(".", bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="2" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="ground" tilewidth="16" tileheight="16" tilecount="4" columns="2"/>
+</map>`))
+	assert.NoError(t, err)
+
+	// Layers built in code may only hold their first tiles
+	tile, err := m.TileGIDToTile(3)
+	assert.NoError(t, err)
+	m.Layers = append(m.Layers, &Layer{ID: 1, Name: "Generated", Opacity: 1, Visible: true, Tiles: []*LayerTile{tile}})
+
+	var out bytes.Buffer
+	assert.NoError(t, m.WriteTMX(&out))
+	assert.Contains(t, out.String(), "\n3,0,\n0,0\n")
+
+	written, err := LoadReader(".", &out)
+	assert.NoError(t, err)
+	if assert.Len(t, written.Layers, 1) {
+		assert.Len(t, written.Layers[0].Tiles, 4)
+		assert.Equal(t, uint32(2), written.Layers[0].Tiles[0].ID)
+	}
+}
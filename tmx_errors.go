@@ -0,0 +1,162 @@
+package tiled
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ParseError locates an error of a map being loaded: the file, or the layer,
+// tileset, group or object, it was found in. Errors of nested elements are
+// wrapped in the ParseError of each parent, so the message reads like
+// "map.tmx: layer 'Collision' (id 7): invalid csv data at tile 418".
+type ParseError struct {
+	// File being parsed, set for errors of the outermost element of a file
+	File string
+	// Element being parsed, such as "layer" or "object", with its name and
+	// ID when it has them
+	Element string
+	Name    string
+	ID      uint32
+	Err     error
+}
+
+// Error returns the error message, prefixed with the file and elements the
+// error was found in
+func (e *ParseError) Error() string {
+	var parts []string
+	if e.File != "" {
+		parts = append(parts, e.File)
+	}
+	if e.Element != "" {
+		element := e.Element
+		if e.Name != "" {
+			element += " '" + e.Name + "'"
+		}
+		if e.ID != 0 {
+			element += fmt.Sprintf(" (id %d)", e.ID)
+		}
+		parts = append(parts, element)
+	}
+	return strings.Join(append(parts, e.Err.Error()), ": ")
+}
+
+// Unwrap returns the error located
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// located reports whether err needs no ParseError: nil errors, and errors
+// opening a file, which name it already. Missing files are left as is for
+// os.IsNotExist, which doesn't unwrap errors.
+func located(err error) bool {
+	_, ok := err.(*fs.PathError)
+	return err == nil || ok || err == fs.ErrNotExist
+}
+
+// fileError locates err in file, unless located
+func fileError(file string, err error) error {
+	if located(err) {
+		return err
+	}
+	return &ParseError{File: file, Err: err}
+}
+
+// elementError locates err in an element, unless located
+func elementError(element, name string, id uint32, err error) error {
+	if located(err) {
+		return err
+	}
+	return &ParseError{Element: element, Name: name, ID: id, Err: err}
+}
+
+// startElementError locates err in the XML element starting with start,
+// decoded into v, reading its name and ID attributes, and names the attribute
+// that couldn't be parsed
+func startElementError(start xml.StartElement, v any, err error) error {
+	if located(err) {
+		return err
+	}
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		if attr := failedAttr(reflect.TypeOf(v), start, numErr.Num); attr != "" {
+			err = fmt.Errorf("attribute %q: %w", attr, err)
+		}
+	}
+	var name string
+	var id uint32
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "name":
+			name = attr.Value
+		case "id":
+			if v, err := strconv.ParseUint(attr.Value, 10, 32); err == nil {
+				id = uint32(v)
+			}
+		}
+	}
+	return elementError(start.Name.Local, name, id, err)
+}
+
+// failedAttr returns the name of the attribute of start with the given value
+// that doesn't parse into its field of struct type t, trying fields in order
+// like encoding/xml, or "" if there is none
+func failedAttr(t reflect.Type, start xml.StartElement, value string) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return ""
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("xml")
+		if f.Anonymous && tag == "" {
+			if name := failedAttr(f.Type, start, value); name != "" {
+				return name
+			}
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if !strings.Contains(opts, "attr") {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local == name && attr.Value == value && !parsesAttr(f.Type, value) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// parsesAttr reports whether value decodes into an attribute field of type t.
+// Values of types decoding themselves are assumed not to.
+func parsesAttr(t reflect.Type, value string) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[xml.UnmarshalerAttr]()) {
+		return false
+	}
+	value = strings.TrimSpace(value)
+	var err error
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err = strconv.ParseInt(value, 10, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		_, err = strconv.ParseUint(value, 10, t.Bits())
+	case reflect.Float32, reflect.Float64:
+		_, err = strconv.ParseFloat(value, t.Bits())
+	case reflect.Bool:
+		_, err = strconv.ParseBool(value)
+	}
+	return err == nil
+}
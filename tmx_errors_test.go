@@ -0,0 +1,109 @@
+package tiled
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseErrorContext(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="1" tilewidth="16" tileheight="16">
+<layer id="7" name="Collision" width="2" height="1"><data encoding="csv">1,99999999999</data></layer>
+</map>`
+
+	_, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.EqualError(t, err, `layer 'Collision' (id 7): tiled: invalid layer data: csv value of tile 1: strconv.ParseUint: parsing "99999999999": value out of range`)
+	var perr *ParseError
+	if assert.ErrorAs(t, err, &perr) {
+		assert.Equal(t, "layer", perr.Element)
+		assert.Equal(t, "Collision", perr.Name)
+		assert.Equal(t, uint32(7), perr.ID)
+	}
+
+	tmx = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<objectgroup id="2" name="Spawns">
+<object id="3" name="Player" x="1.5" y="oops"/>
+</objectgroup>
+</map>`
+
+	_, err = LoadReader(".", bytes.NewBufferString(tmx))
+	assert.EqualError(t, err, `objectgroup 'Spawns' (id 2): object 'Player' (id 3): attribute "y": strconv.ParseFloat: parsing "oops": invalid syntax`)
+
+	// The attribute failing is named, not another one with the same value
+	tmx = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<objectgroup id="2" name="Spawns" parallaxx="1.5" offsetx="1.5"/>
+</map>`
+
+	_, err = LoadReader(".", bytes.NewBufferString(tmx))
+	assert.EqualError(t, err, `objectgroup 'Spawns' (id 2): attribute "offsetx": strconv.ParseInt: parsing "1.5": invalid syntax`)
+
+	tmx = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="ground" tilewidth="x" tileheight="16" tilecount="1" columns="1"/>
+</map>`
+
+	_, err = LoadReader(".", bytes.NewBufferString(tmx))
+	assert.EqualError(t, err, `tileset 'ground': attribute "tilewidth": strconv.ParseInt: parsing "x": invalid syntax`)
+	assert.ErrorAs(t, err, &perr)
+}
+
+func TestParseErrorJSON(t *testing.T) {
+	tmj := `{"type": "map", "orientation": "orthogonal", "width": 1, "height": 1, "tilewidth": 16, "tileheight": 16,
+"tilesets": [{"firstgid": 1, "name": "ground", "tilewidth": 16, "tileheight": 16, "image": "ground.png", "transparentcolor": "#12345"}],
+"layers": [{"type": "group", "id": 4, "name": "Props", "layers": [
+	{"type": "objectgroup", "id": 5, "name": "Spawns", "objects": [{"id": 3, "name": "Player", "text": {"text": "hi", "color": "nope"}}]}
+]}]}`
+
+	_, err := LoadJSONReader(".", bytes.NewBufferString(tmj))
+	assert.ErrorIs(t, err, ErrInvalidColor)
+	var perr *ParseError
+	if assert.ErrorAs(t, err, &perr) {
+		assert.Equal(t, "tileset", perr.Element)
+		assert.Equal(t, "ground", perr.Name)
+	}
+
+	tmj = strings.Replace(tmj, `"#12345"`, `"#123456"`, 1)
+	_, err = LoadJSONReader(".", bytes.NewBufferString(tmj))
+	assert.ErrorIs(t, err, ErrInvalidColor)
+	assert.ErrorContains(t, err, "group 'Props' (id 4): objectgroup 'Spawns' (id 5): object 'Player' (id 3): ")
+}
+
+func TestParseErrorFile(t *testing.T) {
+	err := fileError("maps/level.tmx", elementError("layer", "Ground", 1, ErrEmptyLayerData))
+	assert.EqualError(t, err, "maps/level.tmx: layer 'Ground' (id 1): "+ErrEmptyLayerData.Error())
+	assert.ErrorIs(t, err, ErrEmptyLayerData)
+
+	assert.NoError(t, fileError("maps/level.tmx", nil))
+}
+
+func TestSentinelErrors(t *testing.T) {
+	load := func(data string) error {
+		_, err := LoadReader(".", bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<tileset firstgid="5" name="ground" tilewidth="16" tileheight="16" tilecount="1" columns="1"/>
+<layer id="1" name="Ground" width="1" height="1">`+data+`</layer>
+</map>`))
+		return err
+	}
+
+	assert.ErrorIs(t, load(`<data encoding="csv">99999999999</data>`), ErrInvalidLayerData)
+	assert.ErrorIs(t, load(`<data encoding="base64" compression="zlib">AAAA</data>`), ErrInvalidLayerData)
+	assert.ErrorIs(t, load(`<data encoding="base64" compression="lzma">AAAA</data>`), ErrUnknownCompression)
+	assert.ErrorIs(t, load(`<data encoding="hex">00</data>`), ErrUnknownEncoding)
+
+	err := load(`<data encoding="csv">2</data>`)
+	assert.ErrorIs(t, err, ErrTilesetNotFound)
+	assert.ErrorIs(t, err, ErrInvalidTileGID)
+
+	_, err = ParseHexColor("#12345")
+	assert.ErrorIs(t, err, ErrInvalidColor)
+
+	ts := &Tileset{Name: "ground"}
+	_, err = ts.GetTilesetTile(3)
+	assert.ErrorIs(t, err, ErrTileNotFound)
+}
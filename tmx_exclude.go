@@ -0,0 +1,74 @@
+package tiled
+
+const (
+	// DevOnlyProperty is the bool property flagging layers and objects only
+	// meant for development builds
+	DevOnlyProperty = "devonly"
+	// EditorOnlyProperty is the bool property flagging layers and objects only
+	// meant to be seen in the editor
+	EditorOnlyProperty = "editoronly"
+)
+
+// WithoutDevOnly returns an option to exclude layers and objects with a true
+// devonly or editoronly property, prefixed or not, from the loaded map
+func WithoutDevOnly() LoaderOption {
+	return WithExcludedProperties(devOnlyProperties...)
+}
+
+var devOnlyProperties = []string{DevOnlyProperty, EditorOnlyProperty, XDevOnlyProperty, XEditorOnlyProperty}
+
+// WithExcludedProperties returns an option to exclude layers and objects with
+// any of the given bool properties set to true from the loaded map
+func WithExcludedProperties(names ...string) LoaderOption {
+	return func(l *loader) {
+		l.excludedProperties = append(l.excludedProperties, names...)
+	}
+}
+
+func flagged(p Properties, names []string) bool {
+	for _, name := range names {
+		if p.GetBool(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// without returns the items of s for which exclude is false, reusing s
+func without[T any](s []T, exclude func(T) bool) []T {
+	res := s[:0]
+	for _, item := range s {
+		if !exclude(item) {
+			res = append(res, item)
+		}
+	}
+	return res
+}
+
+// Exclude removes the layers, groups and objects with any of the given bool
+// properties set to true. Layers nested in an excluded group are removed with it.
+func (m *Map) Exclude(names ...string) {
+	m.Layers = without(m.Layers, func(l *Layer) bool { return flagged(l.Properties, names) })
+	m.ObjectGroups = excludeObjectGroups(m.ObjectGroups, names)
+	m.ImageLayers = without(m.ImageLayers, func(l *ImageLayer) bool { return flagged(l.Properties, names) })
+	m.Groups = excludeGroups(m.Groups, names)
+}
+
+func excludeObjectGroups(groups []*ObjectGroup, names []string) []*ObjectGroup {
+	groups = without(groups, func(g *ObjectGroup) bool { return flagged(g.Properties, names) })
+	for _, g := range groups {
+		g.Objects = without(g.Objects, func(o *Object) bool { return flagged(o.Properties, names) })
+	}
+	return groups
+}
+
+func excludeGroups(groups []*Group, names []string) []*Group {
+	groups = without(groups, func(g *Group) bool { return flagged(g.Properties, names) })
+	for _, g := range groups {
+		g.Layers = without(g.Layers, func(l *Layer) bool { return flagged(l.Properties, names) })
+		g.ObjectGroups = excludeObjectGroups(g.ObjectGroups, names)
+		g.ImageLayers = without(g.ImageLayers, func(l *ImageLayer) bool { return flagged(l.Properties, names) })
+		g.Groups = excludeGroups(g.Groups, names)
+	}
+	return groups
+}
@@ -0,0 +1,63 @@
+package tiled
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrReservedGIDBits error is reported when GIDs have bits set outside of the
+// GID mask and flip flags
+var ErrReservedGIDBits = errors.New("tiled: reserved GID bits set")
+
+// WithGIDMask returns an option clearing the bits of tile GIDs that are
+// neither in mask nor flip flags, so maps written by exporters setting
+// reserved bits still resolve to the right tiles. Passing 0x0fffffff clears
+// the bits not used by Tiled for orthogonal maps. Each layer or object group
+// with GIDs cleared is reported to the warning handler.
+func WithGIDMask(mask uint32) LoaderOption {
+	return func(l *loader) {
+		l.gidMask = mask | tileFlip
+	}
+}
+
+// WithWarningHandler returns an option calling fn for each problem tolerated
+// while loading a map.
+func WithWarningHandler(fn func(error)) LoaderOption {
+	return func(l *loader) {
+		l.warn = fn
+	}
+}
+
+func (l *loader) warning(err error) {
+	if l == nil {
+		return
+	}
+	l.warnings = append(l.warnings, ParseWarning{File: l.file, Err: err})
+	if l.warn != nil {
+		l.warn(err)
+	}
+}
+
+// maskGID clears the bits of a GID outside of the GID mask, and reports
+// whether it changed
+func (l *loader) maskGID(gid *uint32) bool {
+	if l == nil || l.gidMask == 0 || *gid&^l.gidMask == 0 {
+		return false
+	}
+	*gid &= l.gidMask
+	return true
+}
+
+// maskGIDs clears the bits of GIDs outside of the GID mask, warning about
+// the ones changed
+func (l *loader) maskGIDs(gids []uint32, layer string) {
+	n := 0
+	for i := range gids {
+		if l.maskGID(&gids[i]) {
+			n++
+		}
+	}
+	if n > 0 {
+		l.warning(fmt.Errorf("%w: %d tiles of layer %q", ErrReservedGIDBits, n, layer))
+	}
+}
@@ -44,10 +44,12 @@ type Group struct {
 	Opacity float32 `xml:"opacity,attr"`
 	// Whether the layer is shown (1) or hidden (0). Defaults to 1.
 	Visible bool `xml:"visible,attr"`
-	// The parallax x factor of the layer 0 - 1.0
+	// The parallax x factor of the layer 0 - 1.0 (defaults to 1)
 	ParallaxX float32 `xml:"parallaxx,attr"`
-	// The parallax y factor of the layer 0 - 1.0
+	// The parallax y factor of the layer 0 - 1.0 (defaults to 1)
 	ParallaxY float32 `xml:"parallaxy,attr"`
+	// A tint color that is multiplied with any graphics drawn by this layer or any child layers, in #AARRGGBB or #RRGGBB format (optional, since 1.9)
+	TintColor *HexColor `xml:"tintcolor,attr"`
 	// Custom properties
 	Properties Properties `xml:"properties>property"`
 	// Map layers
@@ -66,7 +68,7 @@ func (g *Group) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	item.SetDefaults()
 
 	if err := d.DecodeElement(&item, &start); err != nil {
-		return err
+		return startElementError(start, &item, err)
 	}
 
 	*g = (Group)(item)
@@ -74,9 +76,28 @@ func (g *Group) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	return nil
 }
 
+// LayersByClass returns all tile layers of the group and its subgroups with
+// the given class.
+func (g *Group) LayersByClass(class string) Layers {
+	res := Layers{}
+	for _, l := range g.Layers {
+		if l.Class == class {
+			res = append(res, l)
+		}
+	}
+	for _, sub := range g.Groups {
+		res = append(res, sub.LayersByClass(class)...)
+	}
+	return res
+}
+
 // DecodeGroup decodes Group data. This includes all subgroups and the Layer
 // data for each.
 func (g *Group) DecodeGroup(m *Map) error {
+	return elementError("group", g.Name, g.ID, g.decodeGroup(m))
+}
+
+func (g *Group) decodeGroup(m *Map) error {
 	for i := 0; i < len(g.Groups); i++ {
 		g := g.Groups[i]
 		if err := g.DecodeGroup(m); err != nil {
@@ -2,12 +2,18 @@ package tiled
 
 import (
 	"encoding/hex"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"image/color"
 	"strings"
 )
 
+// ErrInvalidColor error is returned for colors not in form #AARRGGBB, #RRGGBB,
+// #ARGB or #RGB
+var ErrInvalidColor = errors.New("tiled: invalid color")
+
 // HexColor handles the conversion between hex color strings in form #AARRGGBB
 // to color.RGBA structure. Be aware that this doesn't match CSS hex color because
 // the alpha channel appears first.
@@ -61,6 +67,12 @@ func (color *HexColor) String() string {
 	return string(dst)
 }
 
+// MarshalJSON implements the json.Marshaler interface, writing the color in
+// the form #AARRGGBB
+func (color *HexColor) MarshalJSON() ([]byte, error) {
+	return json.Marshal(color.String())
+}
+
 // UnmarshalXMLAttr implements xml.UnmarshalerAttr
 func (color *HexColor) UnmarshalXMLAttr(attr xml.Attr) error {
 	c, err := parseHexColor(attr.Value)
@@ -90,7 +102,7 @@ func parseHexColor(s string) (c color.RGBA, err error) {
 		case b >= 'A' && b <= 'F':
 			return b - 'A' + 10
 		}
-		err = errors.New("Invalid Format")
+		err = fmt.Errorf("%w %q", ErrInvalidColor, s)
 		return 0
 	}
 
@@ -118,7 +130,7 @@ func parseHexColor(s string) (c color.RGBA, err error) {
 		c.G = hexToByte(s[1]) * 17
 		c.B = hexToByte(s[2]) * 17
 	default:
-		err = errors.New("Invalid Format")
+		err = fmt.Errorf("%w %q", ErrInvalidColor, s)
 	}
 	return
 }
@@ -23,9 +23,15 @@ SOFTWARE.
 package tiled
 
 import (
+	"bytes"
 	"encoding/xml"
+	"errors"
 )
 
+// ErrUnknownImageEncoding error is returned when an embedded image is not
+// base64 encoded
+var ErrUnknownImageEncoding = errors.New("tiled: unknown embedded image encoding")
+
 // ImageLayer is a layer consisting of a single image.
 type ImageLayer struct {
 	// Unique ID of the layer.
@@ -52,10 +58,12 @@ type ImageLayer struct {
 	Properties Properties `xml:"properties>property"`
 	// The group image
 	Image *Image `xml:"image"`
-	// The parallax x factor of the layer 0 - 1.0
+	// The parallax x factor of the layer 0 - 1.0 (defaults to 1)
 	ParallaxX float32 `xml:"parallaxx,attr"`
-	// The parallax y factor of the layer 0 - 1.0
+	// The parallax y factor of the layer 0 - 1.0 (defaults to 1)
 	ParallaxY float32 `xml:"parallaxy,attr"`
+	// A tint color that is multiplied with any graphics drawn by this layer or any child layers, in #AARRGGBB or #RRGGBB format (optional, since 1.9)
+	TintColor *HexColor `xml:"tintcolor,attr"`
 	// The repeat x settings of the image.
 	RepeatX bool `xml:"repeatx,attr"`
 	// The repeat y settings of the image.
@@ -68,7 +76,7 @@ func (l *ImageLayer) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error
 	item.SetDefaults()
 
 	if err := d.DecodeElement(&item, &start); err != nil {
-		return err
+		return startElementError(start, &item, err)
 	}
 
 	*l = (ImageLayer)(item)
@@ -90,5 +98,23 @@ type Image struct {
 	// The image height in pixels (optional)
 	Height int `xml:"height,attr"`
 	// Embedded image content
-	Data *Data `xml:"data,attr"`
+	Data *Data `xml:"data"`
+}
+
+// Embedded reports whether the image content is embedded in the map or
+// tileset, rather than referenced by Source
+func (i *Image) Embedded() bool {
+	return i.Data != nil && len(bytes.TrimSpace(i.Data.RawData)) > 0
+}
+
+// EmbeddedData returns the decoded content of an embedded image, a file in
+// the format given by Format
+func (i *Image) EmbeddedData() ([]byte, error) {
+	if !i.Embedded() {
+		return nil, nil
+	}
+	if i.Data.Encoding != "base64" {
+		return nil, ErrUnknownImageEncoding
+	}
+	return i.Data.decodeBase64()
 }
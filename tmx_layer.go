@@ -25,7 +25,9 @@ package tiled
 import (
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"image"
+	"iter"
 )
 
 // NilLayerTile is reusable layer tile that is nil
@@ -61,6 +63,19 @@ func (t *LayerTile) IsNil() bool {
 	return t.Nil
 }
 
+// Properties returns the properties of the tile in its tileset, or nil for
+// nil tiles and tiles without properties. With WithPropertyTypes, they hold
+// the default values of the members of the class of the tile it doesn't set.
+func (t *LayerTile) Properties() Properties {
+	if t == nil || t.IsNil() || t.Tileset == nil {
+		return nil
+	}
+	if tilesetTile := t.Tileset.Tile(t.ID); tilesetTile != nil {
+		return tilesetTile.Properties
+	}
+	return nil
+}
+
 // Layer is a map layer
 type Layer struct {
 	_map *Map
@@ -80,10 +95,12 @@ type Layer struct {
 	OffsetX int `xml:"offsetx,attr"`
 	// Rendering offset for this layer in pixels. Defaults to 0. (since 0.14)
 	OffsetY int `xml:"offsety,attr"`
-	// The parallax x factor of the layer 0 - 1.0
+	// The parallax x factor of the layer 0 - 1.0 (defaults to 1)
 	ParallaxX float32 `xml:"parallaxx,attr"`
-	// The parallax y factor of the layer 0 - 1.0
+	// The parallax y factor of the layer 0 - 1.0 (defaults to 1)
 	ParallaxY float32 `xml:"parallaxy,attr"`
+	// A tint color that is multiplied with any graphics drawn by this layer or any child layers, in #AARRGGBB or #RRGGBB format (optional, since 1.9)
+	TintColor *HexColor `xml:"tintcolor,attr"`
 	// Custom properties
 	Properties Properties `xml:"properties>property"`
 	// This is the attribute you'd like to use, not Data. Tile entry at (x,y) is obtained using l.DecodedTiles[y*map.Width+x].
@@ -94,21 +111,45 @@ type Layer struct {
 	empty bool
 }
 
+// Layers is a set of tile layers that can be operated on at once
+type Layers []*Layer
+
+// SetVisible shows or hides all layers of the set
+func (ls Layers) SetVisible(visible bool) {
+	for _, l := range ls {
+		l.Visible = visible
+	}
+}
+
+// Visible returns the visible layers of the set
+func (ls Layers) Visible() Layers {
+	var res Layers
+	for _, l := range ls {
+		if l.Visible {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
 // IsEmpty checks if layer has tiles other than nil
 func (l *Layer) IsEmpty() bool {
 	return l.empty
 }
 
-func (l *Layer) decodeLayerXML() (gids []uint32, err error) {
-	if len(l.data.DataTiles) != l._map.Width*l._map.Height {
-		return []uint32{}, ErrInvalidDecodedTileCount
-	}
+// decodeLayerXML, decodeLayerCSV and decodeLayerBase64 return the GIDs
+// decoded along with ErrInvalidDecodedTileCount when they don't fill the map
 
+func (l *Layer) decodeLayerXML() (gids []uint32, err error) {
 	gids = make([]uint32, len(l.data.DataTiles))
 	for i := 0; i < len(gids); i++ {
 		gids[i] = l.data.DataTiles[i].GID
 	}
 
+	if len(gids) != l._map.Width*l._map.Height {
+		return gids, ErrInvalidDecodedTileCount
+	}
+
 	return gids, nil
 }
 
@@ -119,7 +160,7 @@ func (l *Layer) decodeLayerCSV() ([]uint32, error) {
 	}
 
 	if len(gids) != l._map.Width*l._map.Height {
-		return []uint32{}, ErrInvalidDecodedTileCount
+		return gids, ErrInvalidDecodedTileCount
 	}
 
 	return gids, nil
@@ -131,64 +172,86 @@ func (l *Layer) decodeLayerBase64() ([]uint32, error) {
 		return []uint32{}, err
 	}
 
-	if len(dataBytes) != l._map.Width*l._map.Height*4 {
-		return []uint32{}, ErrInvalidDecodedTileCount
+	gids := make([]uint32, len(dataBytes)/4)
+	for i := range gids {
+		j := i * 4
+		gids[i] = uint32(dataBytes[j]) +
+			uint32(dataBytes[j+1])<<8 +
+			uint32(dataBytes[j+2])<<16 +
+			uint32(dataBytes[j+3])<<24
 	}
 
-	gids := make([]uint32, l._map.Width*l._map.Height)
-
-	j := 0
-	for y := 0; y < l._map.Height; y++ {
-		for x := 0; x < l._map.Width; x++ {
-			gid := uint32(dataBytes[j]) +
-				uint32(dataBytes[j+1])<<8 +
-				uint32(dataBytes[j+2])<<16 +
-				uint32(dataBytes[j+3])<<24
-			j += 4
-
-			gids[y*l._map.Width+x] = gid
-		}
+	if len(dataBytes) != l._map.Width*l._map.Height*4 {
+		return gids, ErrInvalidDecodedTileCount
 	}
 
 	return gids, nil
 }
 
 func (l *Layer) decodeTiles() error {
+	loader := l._map.loader
+	size := l._map.Width * l._map.Height
+
 	var gids []uint32
 	var err error
 	switch l.data.Encoding {
 	case "csv":
-		if gids, err = l.decodeLayerCSV(); err != nil {
-			return err
-		}
+		gids, err = l.decodeLayerCSV()
 	case "base64":
-		if gids, err = l.decodeLayerBase64(); err != nil {
-			return err
-		}
+		gids, err = l.decodeLayerBase64()
 	case "": // XML "encoding"
-		if gids, err = l.decodeLayerXML(); err != nil {
+		gids, err = l.decodeLayerXML()
+	default:
+		err = fmt.Errorf("%w %q", ErrUnknownEncoding, l.data.Encoding)
+	}
+	if err != nil {
+		// Tolerated missing tiles are left empty, extra ones dropped
+		n := min(len(gids), size)
+		if err := loader.tolerate(err, "layer %q decoded with %d of its %d tiles", l.Name, n, size); err != nil {
 			return err
 		}
-	default:
-		return ErrUnknownEncoding
+		gids = append(gids[:n:n], make([]uint32, size-n)...)
 	}
 
+	loader.maskGIDs(gids, l.Name)
+
 	l.Tiles = make([]*LayerTile, len(gids))
+	invalid := 0
 	for j := 0; j < len(l.Tiles); j++ {
 		l.Tiles[j], err = l._map.TileGIDToTile(gids[j])
+		if errors.Is(err, ErrInvalidTileGID) && loader.parseMode() == ParseTolerant {
+			l.Tiles[j] = NilLayerTile
+			invalid++
+			continue
+		}
 		if err != nil {
 			return err
 		}
 	}
+	if invalid > 0 {
+		loader.warning(fmt.Errorf("%w: %d tiles of layer %q left empty", ErrInvalidTileGID, invalid, l.Name))
+	}
 
 	return nil
 }
 
 // DecodeLayer decodes layer data
 func (l *Layer) DecodeLayer(m *Map) error {
+	return elementError("layer", l.Name, l.ID, l.decodeLayer(m))
+}
+
+func (l *Layer) decodeLayer(m *Map) error {
 	l._map = m
 	if l.data == nil {
-		return ErrEmptyLayerData
+		if err := m.loader.tolerate(ErrEmptyLayerData, "layer %q left empty", l.Name); err != nil {
+			return err
+		}
+		l.Tiles = make([]*LayerTile, m.Width*m.Height)
+		for i := range l.Tiles {
+			l.Tiles[i] = NilLayerTile
+		}
+		l.empty = true
+		return nil
 	}
 
 	if err := l.decodeTiles(); err != nil {
@@ -217,7 +280,7 @@ func (l *Layer) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	item.SetDefaults()
 
 	if err := d.DecodeElement(&item, &start); err != nil {
-		return err
+		return startElementError(start, &item, err)
 	}
 
 	*l = (Layer)(item.internalLayer)
@@ -233,6 +296,29 @@ func (l *Layer) GetTilePosition(tileID int) (int, int) {
 	return l.OffsetX + x*l._map.TileWidth, l.OffsetY + y*l._map.TileHeight
 }
 
+// Cells returns an iterator over the tiles of the layer, along with their
+// cell coordinates, row by row. Empty cells are skipped.
+//
+//	for cell, tile := range layer.Cells() {
+//		fmt.Println(cell.X, cell.Y, tile.ID)
+//	}
+func (l *Layer) Cells() iter.Seq2[image.Point, *LayerTile] {
+	return func(yield func(image.Point, *LayerTile) bool) {
+		if l._map == nil || l._map.Width <= 0 {
+			return
+		}
+		width := l._map.Width
+		for i, tile := range l.Tiles {
+			if tile == nil || tile.IsNil() {
+				continue
+			}
+			if !yield(image.Point{X: i % width, Y: i / width}, tile) {
+				return
+			}
+		}
+	}
+}
+
 // GetTileRect returns the rectangle that contains the Tile in the original Tileset.Image
 func (t *LayerTile) GetTileRect() image.Rectangle {
 	return t.Tileset.GetTileRect(t.ID)
@@ -0,0 +1,229 @@
+package tiled
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrLayerNotFound error is returned when looking up a layer missing from
+	// a map or group
+	ErrLayerNotFound = errors.New("tiled: layer not found")
+	// ErrObjectNotFound error is returned when looking up an object missing
+	// from an object group
+	ErrObjectNotFound = errors.New("tiled: object not found")
+)
+
+// GetTileLayer returns the first tile layer with the given name, including
+// the ones nested in groups, in the order they appear in the map
+func (m *Map) GetTileLayer(name string) (*Layer, error) {
+	if l := findLayer(m.Layers, m.Groups, name); l != nil {
+		return l, nil
+	}
+	return nil, fmt.Errorf("%w: tile layer %q", ErrLayerNotFound, name)
+}
+
+// GetObjectGroup returns the first object group with the given name,
+// including the ones nested in groups, in the order they appear in the map
+func (m *Map) GetObjectGroup(name string) (*ObjectGroup, error) {
+	if g := findObjectGroup(m.ObjectGroups, m.Groups, name); g != nil {
+		return g, nil
+	}
+	return nil, fmt.Errorf("%w: object group %q", ErrLayerNotFound, name)
+}
+
+// GetLayer returns the first tile layer of the group and its subgroups with
+// the given name
+func (g *Group) GetLayer(name string) (*Layer, error) {
+	if l := findLayer(g.Layers, g.Groups, name); l != nil {
+		return l, nil
+	}
+	return nil, fmt.Errorf("%w: tile layer %q in group %q", ErrLayerNotFound, name, g.Name)
+}
+
+// GetObjectGroup returns the first object group of the group and its
+// subgroups with the given name
+func (g *Group) GetObjectGroup(name string) (*ObjectGroup, error) {
+	if og := findObjectGroup(g.ObjectGroups, g.Groups, name); og != nil {
+		return og, nil
+	}
+	return nil, fmt.Errorf("%w: object group %q in group %q", ErrLayerNotFound, name, g.Name)
+}
+
+// GetObject returns the first object of the group with the given name
+func (g *ObjectGroup) GetObject(name string) (*Object, error) {
+	for _, o := range g.Objects {
+		if o.Name == name {
+			return o, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: object %q in object group %q", ErrObjectNotFound, name, g.Name)
+}
+
+// walkLayers calls layer with the tile layers and objectGroup with the object
+// groups given and the ones nested in groups, in the order they appear in the
+// map. Nil functions are not called.
+func walkLayers(layers []*Layer, objectGroups []*ObjectGroup, groups []*Group, layer func(*Layer), objectGroup func(*ObjectGroup)) {
+	if layer != nil {
+		for _, l := range layers {
+			layer(l)
+		}
+	}
+	if objectGroup != nil {
+		for _, og := range objectGroups {
+			objectGroup(og)
+		}
+	}
+	for _, g := range groups {
+		walkLayers(g.Layers, g.ObjectGroups, g.Groups, layer, objectGroup)
+	}
+}
+
+// walkLayers calls layer and objectGroup with the tile layers and object
+// groups of the map, see walkLayers
+func (m *Map) walkLayers(layer func(*Layer), objectGroup func(*ObjectGroup)) {
+	walkLayers(m.Layers, m.ObjectGroups, m.Groups, layer, objectGroup)
+}
+
+func findLayer(layers []*Layer, groups []*Group, name string) *Layer {
+	var found *Layer
+	walkLayers(layers, nil, groups, func(l *Layer) {
+		if found == nil && l.Name == name {
+			found = l
+		}
+	}, nil)
+	return found
+}
+
+func findObjectGroup(objectGroups []*ObjectGroup, groups []*Group, name string) *ObjectGroup {
+	var found *ObjectGroup
+	walkLayers(nil, objectGroups, groups, nil, func(og *ObjectGroup) {
+		if found == nil && og.Name == name {
+			found = og
+		}
+	})
+	return found
+}
+
+// ObjectIndex indexes the objects of a map by ID, name and class, see
+// Map.Objects
+type ObjectIndex struct {
+	byID    map[uint32]*Object
+	byName  map[string][]*Object
+	byClass map[string][]*Object
+	groups  map[*Object]*ObjectGroup
+}
+
+// Objects indexes the objects of every object group of the map, including
+// the ones in group layers. The index isn't updated as the map changes:
+// index the map again after adding or removing objects.
+func (m *Map) Objects() *ObjectIndex {
+	idx := &ObjectIndex{
+		byID:    map[uint32]*Object{},
+		byName:  map[string][]*Object{},
+		byClass: map[string][]*Object{},
+		groups:  map[*Object]*ObjectGroup{},
+	}
+	m.walkLayers(nil, func(g *ObjectGroup) {
+		for _, o := range g.Objects {
+			if _, ok := idx.byID[o.ID]; !ok && o.ID != 0 {
+				idx.byID[o.ID] = o
+			}
+			idx.byName[o.Name] = append(idx.byName[o.Name], o)
+			idx.byClass[o.Class] = append(idx.byClass[o.Class], o)
+			idx.groups[o] = g
+		}
+	})
+	return idx
+}
+
+// Len returns the number of objects indexed
+func (idx *ObjectIndex) Len() int {
+	return len(idx.groups)
+}
+
+// ByID returns the object with the given ID, or nil
+func (idx *ObjectIndex) ByID(id uint32) *Object {
+	return idx.byID[id]
+}
+
+// ByName returns the objects with the given name, in the order they appear
+// in the map
+func (idx *ObjectIndex) ByName(name string) []*Object {
+	return idx.byName[name]
+}
+
+// ByClass returns the objects of the given class, in the order they appear
+// in the map
+func (idx *ObjectIndex) ByClass(class string) []*Object {
+	return idx.byClass[class]
+}
+
+// Group returns the object group holding the object, or nil if it wasn't
+// indexed
+func (idx *ObjectIndex) Group(o *Object) *ObjectGroup {
+	return idx.groups[o]
+}
+
+// LayerByID returns the tile layer with the given unique ID, including the
+// ones nested in groups, or nil. IDs don't change as layers are renamed or
+// reordered in Tiled.
+func (m *Map) LayerByID(id uint32) *Layer {
+	var found *Layer
+	m.walkLayers(func(l *Layer) {
+		if found == nil && l.ID == id {
+			found = l
+		}
+	}, nil)
+	return found
+}
+
+// ObjectGroupByID returns the object group with the given unique ID,
+// including the ones nested in groups, or nil
+func (m *Map) ObjectGroupByID(id uint32) *ObjectGroup {
+	var found *ObjectGroup
+	m.walkLayers(nil, func(g *ObjectGroup) {
+		if found == nil && g.ID == id {
+			found = g
+		}
+	})
+	return found
+}
+
+// ImageLayerByID returns the image layer with the given unique ID, including
+// the ones nested in groups, or nil
+func (m *Map) ImageLayerByID(id uint32) *ImageLayer {
+	var find func(imageLayers []*ImageLayer, groups []*Group) *ImageLayer
+	find = func(imageLayers []*ImageLayer, groups []*Group) *ImageLayer {
+		for _, l := range imageLayers {
+			if l.ID == id {
+				return l
+			}
+		}
+		for _, g := range groups {
+			if l := find(g.ImageLayers, g.Groups); l != nil {
+				return l
+			}
+		}
+		return nil
+	}
+	return find(m.ImageLayers, m.Groups)
+}
+
+// GroupByID returns the group layer with the given unique ID, including the
+// ones nested in other groups, or nil
+func (m *Map) GroupByID(id uint32) *Group {
+	var find func(groups []*Group) *Group
+	find = func(groups []*Group) *Group {
+		for _, g := range groups {
+			if g.ID == id {
+				return g
+			}
+			if sub := find(g.Groups); sub != nil {
+				return sub
+			}
+		}
+		return nil
+	}
+	return find(m.Groups)
+}
@@ -0,0 +1,128 @@
+package tiled
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const lookupTMX = `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<layer id="1" name="Ground" width="1" height="1"><data encoding="csv">0</data></layer>
+<objectgroup id="2" name="Spawns">
+<object id="1" name="Player" x="0" y="0"/>
+<object id="2" name="Enemy" x="16" y="0"/>
+</objectgroup>
+<group id="3" name="Castle">
+<layer id="4" name="Walls" width="1" height="1"><data encoding="csv">0</data></layer>
+<group id="5" name="Tower">
+<layer id="6" name="Roof" width="1" height="1"><data encoding="csv">0</data></layer>
+<objectgroup id="7" name="Doors">
+<object id="3" name="Gate" x="0" y="0"/>
+</objectgroup>
+</group>
+</group>
+</map>`
+
+func TestLookupByName(t *testing.T) {
+	m, err := LoadReader(".", bytes.NewBufferString(lookupTMX))
+	assert.NoError(t, err)
+
+	l, err := m.GetTileLayer("Ground")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(1), l.ID)
+	l, err = m.GetTileLayer("Roof")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(6), l.ID)
+	_, err = m.GetTileLayer("Spawns")
+	assert.ErrorIs(t, err, ErrLayerNotFound)
+	assert.EqualError(t, err, `tiled: layer not found: tile layer "Spawns"`)
+
+	g, err := m.GetObjectGroup("Doors")
+	assert.NoError(t, err)
+	o, err := g.GetObject("Gate")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(3), o.ID)
+	_, err = g.GetObject("Player")
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+	assert.EqualError(t, err, `tiled: object not found: object "Player" in object group "Doors"`)
+
+	spawns, err := m.GetObjectGroup("Spawns")
+	assert.NoError(t, err)
+	o, err = spawns.GetObject("Enemy")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), o.ID)
+
+	castle := m.Groups[0]
+	l, err = castle.GetLayer("Roof")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(6), l.ID)
+	_, err = castle.GetLayer("Ground")
+	assert.ErrorIs(t, err, ErrLayerNotFound)
+	_, err = castle.GetObjectGroup("Doors")
+	assert.NoError(t, err)
+}
+
+func TestObjectIndex(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<objectgroup id="1" name="Spawns">
+<object id="1" name="Enemy" class="Orc" x="0" y="0"/>
+<object id="2" name="Enemy" class="Goblin" x="16" y="0"/>
+</objectgroup>
+<group id="2" name="Castle">
+<group id="3" name="Tower">
+<objectgroup id="4" name="Guards">
+<object id="5" name="Captain" class="Orc" x="0" y="0"/>
+</objectgroup>
+</group>
+</group>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	idx := m.Objects()
+	assert.Equal(t, 3, idx.Len())
+
+	captain := idx.ByID(5)
+	if assert.NotNil(t, captain) {
+		assert.Equal(t, "Captain", captain.Name)
+		assert.Equal(t, "Guards", idx.Group(captain).Name)
+	}
+	assert.Nil(t, idx.ByID(3))
+
+	enemies := idx.ByName("Enemy")
+	if assert.Len(t, enemies, 2) {
+		assert.Equal(t, uint32(1), enemies[0].ID)
+		assert.Equal(t, uint32(2), enemies[1].ID)
+	}
+	orcs := idx.ByClass("Orc")
+	if assert.Len(t, orcs, 2) {
+		assert.Equal(t, uint32(1), orcs[0].ID)
+		assert.Equal(t, uint32(5), orcs[1].ID)
+	}
+	assert.Empty(t, idx.ByClass("Troll"))
+	assert.Nil(t, idx.Group(&Object{}))
+}
+
+func TestLookupByID(t *testing.T) {
+	m, err := LoadReader(".", bytes.NewBufferString(lookupTMX))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Ground", m.LayerByID(1).Name)
+	assert.Equal(t, "Roof", m.LayerByID(6).Name)
+	assert.Nil(t, m.LayerByID(2))
+
+	assert.Equal(t, "Spawns", m.ObjectGroupByID(2).Name)
+	assert.Equal(t, "Doors", m.ObjectGroupByID(7).Name)
+	assert.Nil(t, m.ObjectGroupByID(1))
+
+	assert.Equal(t, "Castle", m.GroupByID(3).Name)
+	assert.Equal(t, "Tower", m.GroupByID(5).Name)
+	assert.Nil(t, m.GroupByID(4))
+
+	assert.Nil(t, m.ImageLayerByID(1))
+	m.Groups[0].Groups[0].ImageLayers = append(m.Groups[0].Groups[0].ImageLayers, &ImageLayer{ID: 8, Name: "Sky"})
+	assert.Equal(t, "Sky", m.ImageLayerByID(8).Name)
+}
@@ -25,6 +25,7 @@ package tiled
 import (
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"path/filepath"
 )
 
@@ -39,6 +40,14 @@ const (
 // ErrInvalidTileGID error is returned when tile GID is not found
 var ErrInvalidTileGID = errors.New("tiled: invalid tile GID")
 
+// ErrTilesetNotFound error is returned for tile GIDs lower than the first GID
+// of every tileset of the map. It wraps ErrInvalidTileGID.
+var ErrTilesetNotFound = fmt.Errorf("%w: no tileset holds it", ErrInvalidTileGID)
+
+// ErrInvalidStagger error is returned when loading a map with an unknown
+// stagger axis or index, or a negative hex side length
+var ErrInvalidStagger = errors.New("tiled: invalid stagger attributes")
+
 // Axis type
 type Axis string
 
@@ -59,6 +68,47 @@ const (
 	StaggerIndexEven StaggerIndexType = "even"
 )
 
+// validateStagger checks the stagger attributes of the map, defaulting the
+// axis to AxisY and the index to StaggerIndexOdd for staggered and hexagonal
+// maps as Tiled does.
+func (m *Map) validateStagger() error {
+	switch m.StaggerAxis {
+	case "", AxisX, AxisY:
+	default:
+		err := fmt.Errorf("%w: stagger axis %q", ErrInvalidStagger, m.StaggerAxis)
+		if err := m.loader.tolerate(err, "using the default"); err != nil {
+			return err
+		}
+		m.StaggerAxis = ""
+	}
+	switch m.StaggerIndex {
+	case "", StaggerIndexOdd, StaggerIndexEven:
+	default:
+		err := fmt.Errorf("%w: stagger index %q", ErrInvalidStagger, m.StaggerIndex)
+		if err := m.loader.tolerate(err, "using the default"); err != nil {
+			return err
+		}
+		m.StaggerIndex = ""
+	}
+	if m.HexSideLength < 0 {
+		err := fmt.Errorf("%w: hex side length %d", ErrInvalidStagger, m.HexSideLength)
+		if err := m.loader.tolerate(err, "using 0"); err != nil {
+			return err
+		}
+		m.HexSideLength = 0
+	}
+
+	if m.Orientation == "staggered" || m.Orientation == "hexagonal" {
+		if m.StaggerAxis == "" {
+			m.StaggerAxis = AxisY
+		}
+		if m.StaggerIndex == "" {
+			m.StaggerIndex = StaggerIndexOdd
+		}
+	}
+	return nil
+}
+
 // Map contains three different kinds of layers.
 // Tile layers were once the only type, and are simply called layer, object layers have the objectgroup tag
 // and image layers use the imagelayer tag. The order in which these layers appear is the order in which the
@@ -68,6 +118,10 @@ type Map struct {
 	loader *loader
 	// Base directory for loading additional data
 	baseDir string
+	// Templates loaded, by path
+	templates map[string]*Template
+	// Tilesets added to the map for templates, by template tileset
+	templateTilesets map[*Tileset]*Tileset
 
 	// The TMX format version, generally 1.0.
 	Version string `xml:"version,attr"`
@@ -94,10 +148,17 @@ type Map struct {
 	StaggerAxis Axis `xml:"staggeraxis,attr"`
 	// For staggered and hexagonal maps, determines whether the "even" or "odd" indexes along the staggered axis are shifted. (since 0.11)
 	StaggerIndex StaggerIndexType `xml:"staggerindex,attr"`
+	// X coordinate of the parallax origin in pixels, the point where layers
+	// with any parallax factor line up when at the center of the view. (since 1.8, defaults to 0)
+	ParallaxOriginX float64 `xml:"parallaxoriginx,attr"`
+	// Y coordinate of the parallax origin in pixels. (since 1.8, defaults to 0)
+	ParallaxOriginY float64 `xml:"parallaxoriginy,attr"`
 	// The background color of the map. (since 0.9, optional, may include alpha value since 0.15 in the form #AARRGGBB)
 	BackgroundColor *HexColor `xml:"backgroundcolor,attr"`
 	// Stores the next available ID for new objects. This number is stored to prevent reuse of the same ID after objects have been removed. (since 0.11)
 	NextObjectID uint32 `xml:"nextobjectid,attr"`
+	// Stores the next available ID for new layers. This number is stored to prevent reuse of the same ID after layers have been removed. (since 1.2)
+	NextLayerID uint32 `xml:"nextlayerid,attr"`
 	// Custom properties
 	Properties *Properties `xml:"properties>property"`
 	// Map tilesets
@@ -119,27 +180,56 @@ func (m *Map) initTileset(ts *Tileset) error {
 	if len(ts.Source) == 0 {
 		ts.baseDir = m.baseDir
 		ts.SourceLoaded = true
+		m.loader.checkDeprecated(ts)
 		return nil
 	}
 	sourcePath := m.GetFileFullPath(ts.Source)
+	registry := m.loader.tilesetRegistry()
+	if registry.get(sourcePath, ts) {
+		return nil
+	}
 	f, err := m.loader.open(sourcePath)
 	if err != nil {
 		return err
 	}
 	defer f.Close()
 
-	d := xml.NewDecoder(f)
-
-	if err := d.Decode(ts); err != nil {
+	defer m.loader.inFile(sourcePath)()
+	r, err := checkDocument[Tileset](m.loader, f)
+	if err != nil {
+		return fileError(sourcePath, err)
+	}
+	if err := decodeTileset(r, ts); err != nil {
+		return fileError(sourcePath, err)
+	}
+	if err := m.loader.checkVersion(sourcePath, ts.Version, ts.TiledVersion); err != nil {
 		return err
 	}
+	m.loader.prepareTileset(ts)
+	m.loader.checkDeprecated(ts)
 
 	ts.baseDir = filepath.Dir(sourcePath)
 	ts.SourceLoaded = true
+	registry.add(sourcePath, ts)
 
 	return nil
 }
 
+// InitTilesets loads the external tilesets of the map and indexes the tiles
+// of every tileset, which is otherwise done on first use. The map can then be
+// read by concurrent goroutines, for example forked renderers.
+func (m *Map) InitTilesets() error {
+	for _, ts := range m.Tilesets {
+		if err := m.initTileset(ts); err != nil {
+			return err
+		}
+		if ts.tiles == nil {
+			ts.cacheTiles()
+		}
+	}
+	return nil
+}
+
 // TileGIDToTile is used to find tile data by GID
 func (m *Map) TileGIDToTile(gid uint32) (*LayerTile, error) {
 	if gid == 0 {
@@ -165,7 +255,7 @@ func (m *Map) TileGIDToTile(gid uint32) (*LayerTile, error) {
 		}
 	}
 
-	return nil, ErrInvalidTileGID
+	return nil, fmt.Errorf("%w: GID %d", ErrTilesetNotFound, gidBare)
 }
 
 // GetFileFullPath returns path to file relative to map file
@@ -173,6 +263,50 @@ func (m *Map) GetFileFullPath(fileName string) string {
 	return filepath.Join(m.baseDir, fileName)
 }
 
+// LayersByClass returns all tile layers with the given class, including the
+// ones nested in groups, in the order they appear in the map.
+func (m *Map) LayersByClass(class string) Layers {
+	res := Layers{}
+	for _, l := range m.Layers {
+		if l.Class == class {
+			res = append(res, l)
+		}
+	}
+	for _, g := range m.Groups {
+		res = append(res, g.LayersByClass(class)...)
+	}
+	return res
+}
+
+// newLayerID returns an ID for a new layer and advances NextLayerID. Maps
+// saved without NextLayerID get IDs after the highest one in use.
+func (m *Map) newLayerID() uint32 {
+	if m.NextLayerID == 0 {
+		m.NextLayerID = maxLayerID(m.Layers, m.ObjectGroups, m.ImageLayers, m.Groups) + 1
+	}
+	id := m.NextLayerID
+	m.NextLayerID++
+	return id
+}
+
+// maxLayerID returns the highest ID of the layers, recursively
+func maxLayerID(layers []*Layer, objectGroups []*ObjectGroup, imageLayers []*ImageLayer, groups []*Group) uint32 {
+	var res uint32
+	for _, l := range layers {
+		res = max(res, l.ID)
+	}
+	for _, og := range objectGroups {
+		res = max(res, og.ID)
+	}
+	for _, l := range imageLayers {
+		res = max(res, l.ID)
+	}
+	for _, g := range groups {
+		res = max(res, g.ID, maxLayerID(g.Layers, g.ObjectGroups, g.ImageLayers, g.Groups))
+	}
+	return res
+}
+
 // UnmarshalXML decodes a single XML element beginning with the given start element.
 func (m *Map) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	item := aliasMap{
@@ -185,29 +319,43 @@ func (m *Map) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		return err
 	}
 
+	*m = (Map)(item)
+	return m.decode()
+}
+
+// decode expands variables and decodes the data of the groups, layers and
+// object groups of a parsed map
+func (m *Map) decode() error {
+	if err := m.loader.checkVersion("map", m.Version, m.TiledVersion); err != nil {
+		return err
+	}
+	if err := m.validateStagger(); err != nil {
+		return err
+	}
+	m.loader.prepareMap(m)
+
 	// Decode Groups data
-	for i := 0; i < len(item.Groups); i++ {
-		g := item.Groups[i]
-		if err := g.DecodeGroup((*Map)(&item)); err != nil {
+	for i := 0; i < len(m.Groups); i++ {
+		g := m.Groups[i]
+		if err := g.DecodeGroup(m); err != nil {
 			return err
 		}
 	}
 
 	// Decode layers data
-	for i := 0; i < len(item.Layers); i++ {
-		l := item.Layers[i]
-		if err := l.DecodeLayer((*Map)(&item)); err != nil {
+	for i := 0; i < len(m.Layers); i++ {
+		l := m.Layers[i]
+		if err := l.DecodeLayer(m); err != nil {
 			return err
 		}
 	}
 
 	// Decode object groups.
-	for _, g := range item.ObjectGroups {
-		if err := g.DecodeObjectGroup((*Map)(&item)); err != nil {
+	for _, g := range m.ObjectGroups {
+		if err := g.DecodeObjectGroup(m); err != nil {
 			return err
 		}
 	}
 
-	*m = (Map)(item)
-	return nil
+	return m.checkConsistency()
 }
@@ -0,0 +1,70 @@
+package tiled
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// MatrixFormat is a format layer tiles are exported in by ExportMatrix
+type MatrixFormat int
+
+const (
+	// MatrixCSV writes a row of comma separated GIDs per map row
+	MatrixCSV MatrixFormat = iota
+	// MatrixNPY writes a NumPy .npy file holding a height by width array of
+	// little endian uint32 GIDs, loadable with numpy.load
+	MatrixNPY
+)
+
+// ErrUnknownMatrixFormat is returned by ExportMatrix for unknown formats
+var ErrUnknownMatrixFormat = errors.New("tiled: unknown matrix format")
+
+// ExportMatrix writes the GIDs of the layer tiles, flip flags included and 0
+// for empty cells, as a matrix of the map size in the given format, for
+// analysis with data science tools.
+func (l *Layer) ExportMatrix(w io.Writer, format MatrixFormat) error {
+	if l._map == nil || len(l.Tiles) != l._map.Width*l._map.Height {
+		return ErrInvalidDecodedTileCount
+	}
+	width, height := l._map.Width, l._map.Height
+
+	bw := bufio.NewWriter(w)
+	switch format {
+	case MatrixCSV:
+		var buf []byte
+		for y := 0; y < height; y++ {
+			buf = buf[:0]
+			for x := 0; x < width; x++ {
+				if x > 0 {
+					buf = append(buf, ',')
+				}
+				buf = strconv.AppendUint(buf, uint64(l.Tiles[y*width+x].gid()), 10)
+			}
+			buf = append(buf, '\n')
+			bw.Write(buf)
+		}
+	case MatrixNPY:
+		header := fmt.Sprintf("{'descr': '<u4', 'fortran_order': False, 'shape': (%d, %d), }", height, width)
+		// The header is padded with spaces so the data is 64 bytes aligned
+		total := 10 + len(header) + 1
+		for ; total%64 != 0; total++ {
+			header += " "
+		}
+		header += "\n"
+		bw.WriteString("\x93NUMPY\x01\x00")
+		binary.Write(bw, binary.LittleEndian, uint16(len(header)))
+		bw.WriteString(header)
+		var buf [4]byte
+		for _, t := range l.Tiles {
+			binary.LittleEndian.PutUint32(buf[:], t.gid())
+			bw.Write(buf[:])
+		}
+	default:
+		return ErrUnknownMatrixFormat
+	}
+	return bw.Flush()
+}
@@ -0,0 +1,40 @@
+package tiled
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportMatrix(t *testing.T) {
+	m, err := LoadReader(GetAssetsDirectory(), bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="3" height="2" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="tiles" tilewidth="16" tileheight="16" tilecount="4" columns="4">
+<image source="tiles.png" width="64" height="16"/>
+</tileset>
+<layer id="1" name="Ground" width="3" height="2">
+<data encoding="csv">1,0,2,4,2147483651,0</data>
+</layer>
+</map>`))
+	assert.NoError(t, err)
+	l := m.Layers[0]
+
+	var csv bytes.Buffer
+	assert.NoError(t, l.ExportMatrix(&csv, MatrixCSV))
+	assert.Equal(t, "1,0,2\n4,2147483651,0\n", csv.String())
+
+	var npy bytes.Buffer
+	assert.NoError(t, l.ExportMatrix(&npy, MatrixNPY))
+	data := npy.Bytes()
+	assert.Equal(t, "\x93NUMPY\x01\x00", string(data[:8]))
+	headerLen := int(binary.LittleEndian.Uint16(data[8:10]))
+	assert.Equal(t, 0, (10+headerLen)%64)
+	assert.Contains(t, string(data[10:10+headerLen]), "'shape': (2, 3)")
+	body := data[10+headerLen:]
+	assert.Len(t, body, 6*4)
+	assert.Equal(t, uint32(2147483651), binary.LittleEndian.Uint32(body[16:]))
+
+	assert.ErrorIs(t, l.ExportMatrix(&npy, MatrixFormat(42)), ErrUnknownMatrixFormat)
+}
@@ -25,6 +25,7 @@ package tiled
 import (
 	"encoding/xml"
 	"errors"
+	"fmt"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -33,6 +34,13 @@ import (
 // ErrInvalidObjectPoint error is returned if there is error parsing object points
 var ErrInvalidObjectPoint = errors.New("tiled: invalid object point")
 
+const (
+	// DrawOrderIndex draws objects in the order of appearance
+	DrawOrderIndex = "index"
+	// DrawOrderTopDown draws objects sorted by their y-coordinate
+	DrawOrderTopDown = "topdown"
+)
+
 // ObjectGroup is in fact a map layer, and is hence called "object layer" in Tiled Qt
 type ObjectGroup struct {
 	// Unique ID of the layer.
@@ -55,10 +63,12 @@ type ObjectGroup struct {
 	OffsetY int `xml:"offsety,attr"`
 	// Whether the objects are drawn according to the order of appearance ("index") or sorted by their y-coordinate ("topdown"). Defaults to "topdown".
 	DrawOrder string `xml:"draworder,attr"`
-	// The parallax x factor of the layer 0 - 1.0
+	// The parallax x factor of the layer 0 - 1.0 (defaults to 1)
 	ParallaxX float32 `xml:"parallaxx,attr"`
-	// The parallax y factor of the layer 0 - 1.0
+	// The parallax y factor of the layer 0 - 1.0 (defaults to 1)
 	ParallaxY float32 `xml:"parallaxy,attr"`
+	// A tint color that is multiplied with any graphics drawn by this layer or any child layers, in #AARRGGBB or #RRGGBB format (optional, since 1.9)
+	TintColor *HexColor `xml:"tintcolor,attr"`
 	// Custom properties
 	Properties Properties `xml:"properties>property"`
 	// Group objects
@@ -67,31 +77,55 @@ type ObjectGroup struct {
 
 // DecodeObjectGroup decodes object group data
 func (g *ObjectGroup) DecodeObjectGroup(m *Map) error {
+	return elementError("objectgroup", g.Name, g.ID, g.decodeObjectGroup(m))
+}
+
+func (g *ObjectGroup) decodeObjectGroup(m *Map) error {
+	masked := 0
 	for _, object := range g.Objects {
+		if len(object.TemplateSource) > 0 {
+			if err := object.initTemplate(m); err != nil {
+				return elementError("object", object.Name, object.ID, err)
+			}
+		}
+		if m.loader.maskGID(&object.GID) {
+			masked++
+		}
 		if object.GID > 0 {
 			// Initialize all tilesets that are referenced by tile objects. Otherwise,
 			// if a tileset is used by an object tile but not used by any layer it
 			// won't be loaded.
 			if _, err := m.TileGIDToTile(object.GID); err != nil {
-				return err
-			}
-		}
-		if len(object.TemplateSource) > 0 {
-			if err := object.initTemplate(m); err != nil {
-				return err
+				if !errors.Is(err, ErrInvalidTileGID) {
+					return elementError("object", object.Name, object.ID, err)
+				}
+				if err := m.loader.tolerate(err, "object %d of %q loaded without its tile", object.ID, g.Name); err != nil {
+					return elementError("object", object.Name, object.ID, err)
+				}
+				object.GID = 0
 			}
 		}
 	}
+	if masked > 0 {
+		m.loader.warning(fmt.Errorf("%w: %d objects of object group %q", ErrReservedGIDBits, masked, g.Name))
+	}
 	return nil
 }
 
+// ObjectByID returns the object with the given ID from the object groups of
+// the map, including the ones in group layers, or nil. It indexes the map
+// on each call, use Objects for repeated lookups.
+func (m *Map) ObjectByID(id uint32) *Object {
+	return m.Objects().ByID(id)
+}
+
 // UnmarshalXML decodes a single XML element beginning with the given start element.
 func (g *ObjectGroup) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	item := aliasObjectGroup{}
 	item.SetDefaults()
 
 	if err := d.DecodeElement(&item, &start); err != nil {
-		return err
+		return startElementError(start, &item, err)
 	}
 
 	*g = (ObjectGroup)(item)
@@ -136,10 +170,16 @@ type Object struct {
 	PolyLines []*PolyLine `xml:"polyline"`
 	// Text
 	Text *Text `xml:"text"`
-	// Template
+	// Reference to a template file (optional). Attributes not set on the
+	// object are filled in from the template object when the map is loaded.
 	TemplateSource string `xml:"template,attr"`
-	TemplateLoaded bool   `xml:"-"`
-	Template       *Template
+	// Template loaded.
+	TemplateLoaded bool `xml:"-"`
+	// The loaded template, if any.
+	Template *Template `xml:"-"`
+
+	// Attributes set on an object using a template, the ones it overrides
+	overrides map[string]bool
 }
 
 func (o *Object) initTemplate(m *Map) error {
@@ -150,27 +190,146 @@ func (o *Object) initTemplate(m *Map) error {
 		o.TemplateLoaded = true
 		return nil
 	}
-	sourcePath := m.GetFileFullPath(o.TemplateSource)
-	f, err := m.loader.open(sourcePath)
+	t, err := m.loadTemplate(o.TemplateSource)
 	if err != nil {
 		return err
 	}
+	o.Template = t
+	o.TemplateLoaded = true
+
+	if t.Object == nil {
+		return nil
+	}
+	return o.applyTemplate(m)
+}
+
+// loadTemplate loads a template file, along with its tileset. Templates are
+// loaded once per map and shared by the objects using them.
+func (m *Map) loadTemplate(source string) (*Template, error) {
+	sourcePath := m.GetFileFullPath(source)
+	if t, ok := m.templates[sourcePath]; ok {
+		return t, nil
+	}
+
+	f, err := m.loader.open(sourcePath)
+	if err != nil {
+		return nil, err
+	}
 	defer f.Close()
 
-	d := xml.NewDecoder(f)
-	if err := d.Decode(&o.Template); err != nil {
-		return err
+	defer m.loader.inFile(sourcePath)()
+	r, err := checkDocument[Template](m.loader, f)
+	if err != nil {
+		return nil, fileError(sourcePath, err)
 	}
-	o.TemplateLoaded = true
+	t := &Template{}
+	if err := decodeTemplate(r, t); err != nil {
+		return nil, fileError(sourcePath, err)
+	}
+	m.loader.prepareTemplate(t)
 
-	if o.Template == nil || o.Template.Tileset == nil || o.Template.Object == nil {
-		return nil
+	if t.Tileset != nil {
+		if src := t.Tileset.Source; len(src) > 0 {
+			// The tileset source may be relative from the template location.
+			t.Tileset.Source = filepath.Join(filepath.Dir(source), src)
+		}
+		if err := m.initTileset(t.Tileset); err != nil {
+			return nil, err
+		}
+	}
+
+	if m.templates == nil {
+		m.templates = map[string]*Template{}
+	}
+	m.templates[sourcePath] = t
+	return t, nil
+}
+
+// applyTemplate fills the attributes the object doesn't override with the
+// values of its template object.
+func (o *Object) applyTemplate(m *Map) error {
+	t := o.Template.Object
+
+	if !o.overrides["name"] {
+		o.Name = t.Name
+	}
+	if !o.overrides["type"] {
+		o.Type = t.Type
+	}
+	if !o.overrides["class"] {
+		o.Class = t.Class
+	}
+	if !o.overrides["width"] {
+		o.Width = t.Width
+	}
+	if !o.overrides["height"] {
+		o.Height = t.Height
+	}
+	if !o.overrides["rotation"] {
+		o.Rotation = t.Rotation
+	}
+	if !o.overrides["visible"] {
+		o.Visible = t.Visible
+	}
+	if !o.overrides["gid"] && t.GID != 0 {
+		gid, err := m.templateGID(o.Template.Tileset, t.GID)
+		if err != nil {
+			return err
+		}
+		o.GID = gid
+	}
+	// Shapes are copied, so instances can be edited on their own
+	if len(o.Ellipses) == 0 && len(o.Polygons) == 0 && len(o.PolyLines) == 0 && o.Text == nil {
+		copyShapes(o, t)
+	}
+
+	for _, p := range t.Properties.clone() {
+		if len(o.Properties.Get(p.Name)) == 0 {
+			o.Properties = append(o.Properties, p)
+		}
+	}
+	m.loader.inheritClass(&o.Properties, o.Class)
+	return nil
+}
+
+// templateGID converts a GID relative to a template tileset to a GID of the
+// map. Template tilesets the map doesn't reference are added to the map
+// after its other tilesets.
+func (m *Map) templateGID(ts *Tileset, gid uint32) (uint32, error) {
+	if ts == nil {
+		return 0, nil
+	}
+	convert := func(mts *Tileset) uint32 {
+		return gid&tileFlip | (gid&^tileFlip - ts.FirstGID + mts.FirstGID)
+	}
+
+	if mts, ok := m.templateTilesets[ts]; ok {
+		return convert(mts), nil
 	}
-	if src := o.Template.Tileset.Source; len(src) > 0 {
-		// The tileset source may be relative from the template location.
-		o.Template.Tileset.Source = filepath.Join(filepath.Dir(o.TemplateSource), src)
+	if len(ts.Source) > 0 {
+		source := m.GetFileFullPath(ts.Source)
+		for _, mts := range m.Tilesets {
+			if len(mts.Source) > 0 && m.GetFileFullPath(mts.Source) == source {
+				return convert(mts), nil
+			}
+		}
+	}
+
+	next := uint32(1)
+	for _, mts := range m.Tilesets {
+		if err := m.initTileset(mts); err != nil {
+			return 0, err
+		}
+		next = max(next, mts.FirstGID+uint32(mts.TileCount))
 	}
-	return m.initTileset(o.Template.Tileset)
+	added := *ts
+	added.FirstGID = next
+	m.Tilesets = append(m.Tilesets, &added)
+	if m.templateTilesets == nil {
+		m.templateTilesets = map[*Tileset]*Tileset{}
+	}
+	m.templateTilesets[ts] = &added
+	return convert(&added), nil
 }
 
 // UnmarshalXML decodes a single XML element beginning with the given start element.
@@ -179,10 +338,16 @@ func (o *Object) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	item.SetDefaults()
 
 	if err := d.DecodeElement(&item, &start); err != nil {
-		return err
+		return startElementError(start, &item, err)
 	}
 
 	*o = (Object)(item)
+	if o.TemplateSource != "" {
+		o.overrides = map[string]bool{}
+		for _, a := range start.Attr {
+			o.overrides[a.Name.Local] = true
+		}
+	}
 
 	return nil
 }
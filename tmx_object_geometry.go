@@ -0,0 +1,219 @@
+package tiled
+
+import (
+	"math"
+)
+
+// Rectangle is an axis-aligned rectangle in pixels
+type Rectangle struct {
+	// Top left corner
+	Min Point
+	// Bottom right corner
+	Max Point
+}
+
+// Width returns the width of the rectangle
+func (r Rectangle) Width() float64 {
+	return r.Max.X - r.Min.X
+}
+
+// Height returns the height of the rectangle
+func (r Rectangle) Height() float64 {
+	return r.Max.Y - r.Min.Y
+}
+
+// Intersects reports whether the rectangles overlap
+func (r Rectangle) Intersects(s Rectangle) bool {
+	return r.Min.X < s.Max.X && s.Min.X < r.Max.X &&
+		r.Min.Y < s.Max.Y && s.Min.Y < r.Max.Y
+}
+
+// Contains reports whether the point is inside the rectangle
+func (r Rectangle) Contains(p Point) bool {
+	return r.Min.X <= p.X && p.X < r.Max.X &&
+		r.Min.Y <= p.Y && p.Y < r.Max.Y
+}
+
+func boundsOf(points []Point) Rectangle {
+	r := Rectangle{Min: points[0], Max: points[0]}
+	for _, p := range points[1:] {
+		r.Min.X = math.Min(r.Min.X, p.X)
+		r.Min.Y = math.Min(r.Min.Y, p.Y)
+		r.Max.X = math.Max(r.Max.X, p.X)
+		r.Max.Y = math.Max(r.Max.Y, p.Y)
+	}
+	return r
+}
+
+// IsPoint reports whether the object is a point, with no size and no shape
+func (o *Object) IsPoint() bool {
+	return o.GID == 0 && o.Width == 0 && o.Height == 0 && o.Text == nil &&
+		len(o.Ellipses) == 0 && len(o.Polygons) == 0 && len(o.PolyLines) == 0
+}
+
+// transform converts a point relative to the object origin to map
+// coordinates, applying the object rotation around its origin.
+func (o *Object) transform(p Point) Point {
+	if o.Rotation == 0 {
+		return Point{X: o.X + p.X, Y: o.Y + p.Y}
+	}
+	sin, cos := math.Sincos(o.Rotation * math.Pi / 180)
+	return Point{
+		X: o.X + p.X*cos - p.Y*sin,
+		Y: o.Y + p.X*sin + p.Y*cos,
+	}
+}
+
+// untransform converts a point in map coordinates to coordinates relative to
+// the object origin, undoing the object rotation.
+func (o *Object) untransform(p Point) Point {
+	p = Point{X: p.X - o.X, Y: p.Y - o.Y}
+	if o.Rotation == 0 {
+		return p
+	}
+	sin, cos := math.Sincos(o.Rotation * math.Pi / 180)
+	return Point{
+		X: p.X*cos + p.Y*sin,
+		Y: -p.X*sin + p.Y*cos,
+	}
+}
+
+// localPoints returns the outline of a polygon or polyline object relative
+// to the object origin, or the corners of its rectangle otherwise. Tile
+// objects are anchored at their bottom left corner.
+func (o *Object) localPoints() []Point {
+	var points *Points
+	switch {
+	case len(o.Polygons) > 0:
+		points = o.Polygons[0].Points
+	case len(o.PolyLines) > 0:
+		points = o.PolyLines[0].Points
+	case o.GID != 0:
+		return []Point{{0, -o.Height}, {o.Width, -o.Height}, {o.Width, 0}, {0, 0}}
+	default:
+		return []Point{{0, 0}, {o.Width, 0}, {o.Width, o.Height}, {0, o.Height}}
+	}
+
+	if points == nil || len(*points) == 0 {
+		return []Point{{0, 0}}
+	}
+	return toPoints(points)
+}
+
+// polygonArea returns the signed area of a polygon with the shoelace formula
+func polygonArea(points []Point) float64 {
+	var a float64
+	for i, p := range points {
+		q := points[(i+1)%len(points)]
+		a += p.X*q.Y - q.X*p.Y
+	}
+	return a / 2
+}
+
+// Area returns the area of the object shape in square pixels. Points and
+// polylines have no area.
+func (o *Object) Area() float64 {
+	switch {
+	case len(o.Ellipses) > 0:
+		return math.Pi * o.Width * o.Height / 4
+	case len(o.Polygons) > 0:
+		return math.Abs(polygonArea(o.localPoints()))
+	case len(o.PolyLines) > 0:
+		return 0
+	}
+	return o.Width * o.Height
+}
+
+// Centroid returns the center of mass of the object shape in map
+// coordinates, taking its rotation into account. The centroid of a polyline
+// is the middle of its segments weighted by their length.
+func (o *Object) Centroid() Point {
+	points := o.localPoints()
+
+	switch {
+	case len(o.Polygons) > 0:
+		a := polygonArea(points)
+		if a == 0 {
+			return o.transform(average(points))
+		}
+		var c Point
+		for i, p := range points {
+			q := points[(i+1)%len(points)]
+			cross := p.X*q.Y - q.X*p.Y
+			c.X += (p.X + q.X) * cross
+			c.Y += (p.Y + q.Y) * cross
+		}
+		return o.transform(Point{X: c.X / (6 * a), Y: c.Y / (6 * a)})
+	case len(o.PolyLines) > 0:
+		var c Point
+		var length float64
+		for i := 1; i < len(points); i++ {
+			p, q := points[i-1], points[i]
+			l := math.Hypot(q.X-p.X, q.Y-p.Y)
+			c.X += (p.X + q.X) / 2 * l
+			c.Y += (p.Y + q.Y) / 2 * l
+			length += l
+		}
+		if length == 0 {
+			return o.transform(average(points))
+		}
+		return o.transform(Point{X: c.X / length, Y: c.Y / length})
+	}
+
+	return o.transform(average(points))
+}
+
+func average(points []Point) Point {
+	var c Point
+	for _, p := range points {
+		c.X += p.X
+		c.Y += p.Y
+	}
+	n := float64(len(points))
+	return Point{X: c.X / n, Y: c.Y / n}
+}
+
+// BoundingBox returns the smallest axis-aligned rectangle containing the
+// object shape in map coordinates, taking its rotation into account.
+func (o *Object) BoundingBox() Rectangle {
+	if len(o.Ellipses) > 0 {
+		a, b := o.Width/2, o.Height/2
+		sin, cos := math.Sincos(o.Rotation * math.Pi / 180)
+		ex := math.Hypot(a*cos, b*sin)
+		ey := math.Hypot(a*sin, b*cos)
+		c := o.transform(Point{X: a, Y: b})
+		return Rectangle{
+			Min: Point{X: c.X - ex, Y: c.Y - ey},
+			Max: Point{X: c.X + ex, Y: c.Y + ey},
+		}
+	}
+
+	points := o.localPoints()
+	for i, p := range points {
+		points[i] = o.transform(p)
+	}
+	return boundsOf(points)
+}
+
+// Contains reports whether the point in map coordinates is inside the object
+// shape, taking its rotation into account. Points and polylines contain no
+// point.
+func (o *Object) Contains(p Point) bool {
+	if o.IsPoint() || len(o.PolyLines) > 0 {
+		return false
+	}
+	p = o.untransform(p)
+	switch {
+	case len(o.Ellipses) > 0:
+		a, b := o.Width/2, o.Height/2
+		if a == 0 || b == 0 {
+			return false
+		}
+		dx, dy := (p.X-a)/a, (p.Y-b)/b
+		return dx*dx+dy*dy <= 1
+	case len(o.Polygons) > 0:
+		return pointInPolygon(p, o.localPoints())
+	default:
+		return boundsOf(o.localPoints()).Contains(p)
+	}
+}
@@ -0,0 +1,88 @@
+package tiled
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectGeometry(t *testing.T) {
+	triangle := Points{{X: 0, Y: 0}, {X: 30, Y: 0}, {X: 0, Y: 30}}
+	line := Points{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 30}}
+
+	type test struct {
+		name     string
+		object   Object
+		area     float64
+		centroid Point
+		bounds   Rectangle
+	}
+	tests := []test{
+		{
+			name:     "Rectangle",
+			object:   Object{X: 10, Y: 20, Width: 40, Height: 20},
+			area:     800,
+			centroid: Point{X: 30, Y: 30},
+			bounds:   Rectangle{Min: Point{X: 10, Y: 20}, Max: Point{X: 50, Y: 40}},
+		},
+		{
+			name:     "Rotated rectangle",
+			object:   Object{X: 10, Y: 20, Width: 40, Height: 20, Rotation: 90},
+			area:     800,
+			centroid: Point{X: 0, Y: 40},
+			bounds:   Rectangle{Min: Point{X: -10, Y: 20}, Max: Point{X: 10, Y: 60}},
+		},
+		{
+			name:     "Tile object",
+			object:   Object{X: 10, Y: 20, Width: 32, Height: 32, GID: 1},
+			area:     1024,
+			centroid: Point{X: 26, Y: 4},
+			bounds:   Rectangle{Min: Point{X: 10, Y: -12}, Max: Point{X: 42, Y: 20}},
+		},
+		{
+			name:     "Ellipse",
+			object:   Object{X: 0, Y: 0, Width: 20, Height: 10, Ellipses: []*Ellipse{{}}},
+			area:     math.Pi * 50,
+			centroid: Point{X: 10, Y: 5},
+			bounds:   Rectangle{Min: Point{X: 0, Y: 0}, Max: Point{X: 20, Y: 10}},
+		},
+		{
+			name:     "Polygon",
+			object:   Object{X: 100, Y: 100, Polygons: []*Polygon{{Points: &triangle}}},
+			area:     450,
+			centroid: Point{X: 110, Y: 110},
+			bounds:   Rectangle{Min: Point{X: 100, Y: 100}, Max: Point{X: 130, Y: 130}},
+		},
+		{
+			name:     "Polyline",
+			object:   Object{X: 0, Y: 0, PolyLines: []*PolyLine{{Points: &line}}},
+			area:     0,
+			centroid: Point{X: 8.75, Y: 11.25},
+			bounds:   Rectangle{Min: Point{X: 0, Y: 0}, Max: Point{X: 10, Y: 30}},
+		},
+		{
+			name:     "Point",
+			object:   Object{X: 5, Y: 6},
+			area:     0,
+			centroid: Point{X: 5, Y: 6},
+			bounds:   Rectangle{Min: Point{X: 5, Y: 6}, Max: Point{X: 5, Y: 6}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.InDelta(t, test.area, test.object.Area(), 1e-9)
+
+			c := test.object.Centroid()
+			assert.InDelta(t, test.centroid.X, c.X, 1e-9)
+			assert.InDelta(t, test.centroid.Y, c.Y, 1e-9)
+
+			b := test.object.BoundingBox()
+			assert.InDelta(t, test.bounds.Min.X, b.Min.X, 1e-9)
+			assert.InDelta(t, test.bounds.Min.Y, b.Min.Y, 1e-9)
+			assert.InDelta(t, test.bounds.Max.X, b.Max.X, 1e-9)
+			assert.InDelta(t, test.bounds.Max.Y, b.Max.Y, 1e-9)
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package tiled
+
+import (
+	"math"
+)
+
+// toPoints copies points into a slice of values
+func toPoints(points *Points) []Point {
+	if points == nil {
+		return nil
+	}
+	res := make([]Point, len(*points))
+	for i, p := range *points {
+		res[i] = *p
+	}
+	return res
+}
+
+// fromPoints copies a slice of values into Points
+func fromPoints(points []Point) *Points {
+	res := make(Points, len(points))
+	for i := range points {
+		p := points[i]
+		res[i] = &p
+	}
+	return &res
+}
+
+// segmentDistance returns the distance between p and the segment [a, b]
+func segmentDistance(p, a, b Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	l := dx*dx + dy*dy
+	if l == 0 {
+		return math.Hypot(p.X-a.X, p.Y-a.Y)
+	}
+	t := math.Max(0, math.Min(1, ((p.X-a.X)*dx+(p.Y-a.Y)*dy)/l))
+	return math.Hypot(p.X-(a.X+t*dx), p.Y-(a.Y+t*dy))
+}
+
+// douglasPeucker simplifies an open chain of points, always keeping its ends
+func douglasPeucker(points []Point, tolerance float64) []Point {
+	if len(points) < 3 {
+		return points
+	}
+
+	keep := make([]bool, len(points))
+	keep[0], keep[len(points)-1] = true, true
+
+	var simplify func(first, last int)
+	simplify = func(first, last int) {
+		index, maxDist := -1, tolerance
+		for i := first + 1; i < last; i++ {
+			if d := segmentDistance(points[i], points[first], points[last]); d > maxDist {
+				index, maxDist = i, d
+			}
+		}
+		if index < 0 {
+			return
+		}
+		keep[index] = true
+		simplify(first, index)
+		simplify(index, last)
+	}
+	simplify(0, len(points)-1)
+
+	var res []Point
+	for i, p := range points {
+		if keep[i] {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// Simplify returns a copy of the polyline where points closer than tolerance
+// pixels to the simplified line are removed, using the Douglas-Peucker
+// algorithm. Both ends are always kept.
+func (p *PolyLine) Simplify(tolerance float64) *PolyLine {
+	return &PolyLine{Points: fromPoints(douglasPeucker(toPoints(p.Points), tolerance))}
+}
+
+// Simplify returns a copy of the polygon where points closer than tolerance
+// pixels to the simplified outline are removed, using the Douglas-Peucker
+// algorithm. The result keeps at least three points.
+func (p *Polygon) Simplify(tolerance float64) *Polygon {
+	points := toPoints(p.Points)
+	if len(points) <= 3 {
+		return &Polygon{Points: fromPoints(points)}
+	}
+
+	// Split the ring at the point farthest from the first one, and simplify
+	// both halves as open chains.
+	far, maxDist := 0, 0.0
+	for i, q := range points {
+		if d := math.Hypot(q.X-points[0].X, q.Y-points[0].Y); d > maxDist {
+			far, maxDist = i, d
+		}
+	}
+
+	first := douglasPeucker(points[:far+1], tolerance)
+	second := douglasPeucker(append(append([]Point{}, points[far:]...), points[0]), tolerance)
+
+	res := append(first, second[1:len(second)-1]...)
+	if len(res) < 3 {
+		return &Polygon{Points: fromPoints(points)}
+	}
+	return &Polygon{Points: fromPoints(res)}
+}
+
+func cross(o, a, b Point) float64 {
+	return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+}
+
+func inTriangle(p, a, b, c Point) bool {
+	return cross(a, b, p) >= 0 && cross(b, c, p) >= 0 && cross(c, a, p) >= 0
+}
+
+// isConvex reports whether the polygon made of the indexed points, with a
+// positive orientation, is convex.
+func isConvex(points []Point, poly []int) bool {
+	for i := range poly {
+		a, b, c := points[poly[i]], points[poly[(i+1)%len(poly)]], points[poly[(i+2)%len(poly)]]
+		if cross(a, b, c) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// triangulate splits a polygon with a positive orientation into triangles
+// by ear clipping.
+func triangulate(points []Point) [][]int {
+	remaining := make([]int, len(points))
+	for i := range remaining {
+		remaining[i] = i
+	}
+
+	var res [][]int
+	for len(remaining) > 3 {
+		found := false
+		for i := range remaining {
+			ia, ib, ic := remaining[(i+len(remaining)-1)%len(remaining)], remaining[i], remaining[(i+1)%len(remaining)]
+			a, b, c := points[ia], points[ib], points[ic]
+			if cross(a, b, c) <= 0 {
+				continue
+			}
+
+			ear := true
+			for _, j := range remaining {
+				if j != ia && j != ib && j != ic && inTriangle(points[j], a, b, c) {
+					ear = false
+					break
+				}
+			}
+			if !ear {
+				continue
+			}
+
+			res = append(res, []int{ia, ib, ic})
+			remaining = append(remaining[:i], remaining[i+1:]...)
+			found = true
+			break
+		}
+		if !found {
+			// Self-intersecting or degenerate polygon, keep the rest as is
+			break
+		}
+	}
+	return append(res, remaining)
+}
+
+// mergeConvex merges two polygons sharing an edge if the result is convex
+func mergeConvex(points []Point, a, b []int) ([]int, bool) {
+	for ai := range a {
+		i, j := a[ai], a[(ai+1)%len(a)]
+		for bi := range b {
+			if b[bi] != j || b[(bi+1)%len(b)] != i {
+				continue
+			}
+
+			merged := make([]int, 0, len(a)+len(b)-2)
+			for k := 0; k < len(a); k++ {
+				merged = append(merged, a[(ai+1+k)%len(a)])
+			}
+			for k := 2; k < len(b); k++ {
+				merged = append(merged, b[(bi+k)%len(b)])
+			}
+			return merged, isConvex(points, merged)
+		}
+	}
+	return nil, false
+}
+
+// ConvexParts decomposes the polygon into convex polygons, using ear
+// clipping followed by the Hertel-Mehlhorn merging of triangles. A convex
+// polygon is returned as a single part.
+func (p *Polygon) ConvexParts() []*Polygon {
+	points := toPoints(p.Points)
+	if len(points) < 3 {
+		return []*Polygon{{Points: fromPoints(points)}}
+	}
+
+	// Work with a positive orientation, and restore the original one after
+	reversed := polygonArea(points) < 0
+	if reversed {
+		for i, j := 0, len(points)-1; i < j; i, j = i+1, j-1 {
+			points[i], points[j] = points[j], points[i]
+		}
+	}
+
+	parts := triangulate(points)
+	for merged := true; merged; {
+		merged = false
+	search:
+		for i := range parts {
+			for j := i + 1; j < len(parts); j++ {
+				if m, ok := mergeConvex(points, parts[i], parts[j]); ok {
+					parts[i] = m
+					parts = append(parts[:j], parts[j+1:]...)
+					merged = true
+					break search
+				}
+			}
+		}
+	}
+
+	res := make([]*Polygon, len(parts))
+	for i, part := range parts {
+		poly := make([]Point, len(part))
+		for k, index := range part {
+			poly[k] = points[index]
+		}
+		if reversed {
+			for a, b := 0, len(poly)-1; a < b; a, b = a+1, b-1 {
+				poly[a], poly[b] = poly[b], poly[a]
+			}
+		}
+		res[i] = &Polygon{Points: fromPoints(poly)}
+	}
+	return res
+}
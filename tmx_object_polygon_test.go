@@ -0,0 +1,52 @@
+package tiled
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolyLineSimplify(t *testing.T) {
+	line := &PolyLine{Points: &Points{{X: 0, Y: 0}, {X: 5, Y: 0.1}, {X: 10, Y: 0}, {X: 10, Y: 5}, {X: 10.1, Y: 10}}}
+
+	simplified := line.Simplify(0.5)
+	assert.Equal(t, Points{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10.1, Y: 10}}, *simplified.Points)
+	assert.Len(t, *line.Points, 5)
+}
+
+func TestPolygonSimplify(t *testing.T) {
+	square := &Polygon{Points: &Points{{X: 0, Y: 0}, {X: 5, Y: 0.1}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0.1, Y: 5}}}
+
+	simplified := square.Simplify(0.5)
+	assert.Equal(t, Points{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}, *simplified.Points)
+}
+
+func TestPolygonConvexParts(t *testing.T) {
+	square := &Polygon{Points: &Points{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}}
+	assert.Len(t, square.ConvexParts(), 1)
+
+	// L shaped polygon, in both orientations
+	l := Points{{X: 0, Y: 0}, {X: 20, Y: 0}, {X: 20, Y: 10}, {X: 10, Y: 10}, {X: 10, Y: 20}, {X: 0, Y: 20}}
+	reversed := make(Points, len(l))
+	for i, p := range l {
+		reversed[len(l)-1-i] = p
+	}
+
+	for _, points := range []Points{l, reversed} {
+		polygon := &Polygon{Points: &points}
+		parts := polygon.ConvexParts()
+		assert.Len(t, parts, 2)
+
+		var area float64
+		for _, part := range parts {
+			pts := toPoints(part.Points)
+			forward, backward := make([]int, len(pts)), make([]int, len(pts))
+			for i := range pts {
+				forward[i], backward[len(pts)-1-i] = i, i
+			}
+			assert.True(t, isConvex(pts, forward) || isConvex(pts, backward))
+			area += (&Object{Polygons: []*Polygon{part}}).Area()
+		}
+		assert.InDelta(t, 300, area, 1e-9)
+	}
+}
@@ -7,6 +7,7 @@ package tiled_test
 
 import (
 	"testing"
+	"testing/fstest"
 
 	"github.com/Tsukumogami-Software/go-tiled"
 )
@@ -25,3 +26,135 @@ func TestLoadTilesetReferencedOnlyByObjectGroup(t *testing.T) {
 		}
 	}
 }
+
+func TestObjectTemplate(t *testing.T) {
+	m, err := tiled.LoadFile("assets/test_template.tmx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objs := m.ObjectGroups[0].Objects
+
+	crate := objs[0]
+	if crate.Name != "crate" || crate.Type != "prop" {
+		t.Errorf("template name and type not applied: %q %q", crate.Name, crate.Type)
+	}
+	if crate.Width != 32 || crate.Height != 32 {
+		t.Errorf("template size not applied: %vx%v", crate.Width, crate.Height)
+	}
+	if crate.X != 32 || crate.Y != 64 {
+		t.Errorf("instance position overridden: %v,%v", crate.X, crate.Y)
+	}
+	// GID 8 of the template tileset maps to firstgid 5 in the map.
+	if crate.GID != 12 {
+		t.Errorf("expected GID 12, got %d", crate.GID)
+	}
+	if crate.Properties.GetInt("weight") != 10 || !crate.Properties.GetBool("breakable") {
+		t.Errorf("template properties not applied: %v", crate.Properties)
+	}
+
+	heavy := objs[1]
+	if heavy.Name != "heavy crate" || heavy.Width != 64 || heavy.Height != 32 {
+		t.Errorf("instance attributes not preserved: %q %vx%v", heavy.Name, heavy.Width, heavy.Height)
+	}
+	if heavy.Properties.GetInt("weight") != 20 || !heavy.Properties.GetBool("breakable") {
+		t.Errorf("instance properties not merged: %v", heavy.Properties)
+	}
+}
+
+func TestObjectTemplateTilesetAndShapes(t *testing.T) {
+	fsys := fstest.MapFS{
+		"tilesets/props.tsx": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="props" tilewidth="16" tileheight="16" tilecount="4" columns="4">
+ <image source="props.png" width="64" height="16"/>
+</tileset>`)},
+		"templates/barrel.tx": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<template>
+ <tileset firstgid="1" source="../tilesets/props.tsx"/>
+ <object name="barrel" gid="3" width="16" height="16" rotation="90"/>
+</template>`)},
+		"templates/zone.tj": {Data: []byte(`{"type": "template",
+ "object": {"name": "zone", "class": "trigger", "width": 0, "height": 0,
+  "polygon": [{"x": 0, "y": 0}, {"x": 8, "y": 0}, {"x": 0, "y": 8}]}}`)},
+		"level.tmx": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="2" tilewidth="16" tileheight="16">
+ <tileset firstgid="1" name="Inline" tilewidth="16" tileheight="16" tilecount="6" columns="3"/>
+ <objectgroup id="1" name="Props">
+  <object id="1" template="templates/barrel.tx" x="16" y="16"/>
+  <object id="2" template="templates/barrel.tx" x="32" y="16" rotation="45"/>
+  <object id="3" template="templates/zone.tj" x="4" y="4"/>
+  <object id="4" template="templates/barrel.tx" x="48" y="16" rotation="0"/>
+ </objectgroup>
+</map>`)},
+	}
+
+	m, err := tiled.LoadFile("level.tmx", tiled.WithFileSystem(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The template tileset is added once, after the tiles of the map tileset
+	if len(m.Tilesets) != 2 || m.Tilesets[1].FirstGID != 7 || m.Tilesets[1].Name != "props" {
+		t.Fatalf("template tileset not added to the map: %d tilesets", len(m.Tilesets))
+	}
+
+	objs := m.ObjectGroups[0].Objects
+	if objs[0].GID != 9 || objs[1].GID != 9 {
+		t.Errorf("expected GID 9, got %d and %d", objs[0].GID, objs[1].GID)
+	}
+	if objs[0].Rotation != 90 || objs[1].Rotation != 45 {
+		t.Errorf("template rotation not applied: %v %v", objs[0].Rotation, objs[1].Rotation)
+	}
+	if objs[3].Rotation != 0 {
+		t.Errorf("rotation overridden to 0 taken from the template: %v", objs[3].Rotation)
+	}
+	if objs[0].Template != objs[1].Template {
+		t.Error("template loaded twice")
+	}
+	tile, err := m.TileGIDToTile(objs[0].GID)
+	if err != nil || tile.ID != 2 || tile.Tileset.Name != "props" {
+		t.Errorf("template tile not resolved: %v", err)
+	}
+
+	zone := objs[2]
+	if zone.Name != "zone" || zone.Class != "trigger" || len(zone.Polygons) != 1 || len(*zone.Polygons[0].Points) != 3 {
+		t.Errorf("JSON template not applied: %q %q %d", zone.Name, zone.Class, len(zone.Polygons))
+	}
+}
+
+func TestObjectTemplateOverrides(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/trap.tx": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<template>
+ <object name="trap" width="16" height="8" visible="0">
+  <polygon points="0,0 16,0 8,8"/>
+ </object>
+</template>`)},
+		"level.tmx": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="2" tilewidth="16" tileheight="16">
+ <objectgroup id="1" name="Traps">
+  <object id="1" template="templates/trap.tx" x="0" y="0"/>
+  <object id="2" template="templates/trap.tx" x="16" y="0" width="0" height="0" visible="1"/>
+ </objectgroup>
+</map>`)},
+	}
+
+	m, err := tiled.LoadFile("level.tmx", tiled.WithFileSystem(fsys))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objs := m.ObjectGroups[0].Objects
+	if objs[0].Visible || objs[0].Width != 16 || objs[0].Height != 8 {
+		t.Errorf("template attributes not applied: visible %v, size %vx%v", objs[0].Visible, objs[0].Width, objs[0].Height)
+	}
+	if !objs[1].Visible || objs[1].Width != 0 || objs[1].Height != 0 {
+		t.Errorf("overridden attributes taken from the template: visible %v, size %vx%v", objs[1].Visible, objs[1].Width, objs[1].Height)
+	}
+
+	// Editing the shape of an instance leaves the others and the template
+	(*objs[0].Polygons[0].Points)[1].X = 32
+	if (*objs[1].Polygons[0].Points)[1].X != 16 || (*objs[0].Template.Object.Polygons[0].Points)[1].X != 16 {
+		t.Error("template shape shared between instances")
+	}
+}
@@ -0,0 +1,119 @@
+package tiled
+
+// propertiesVisitor is called with the properties of an element of a map,
+// tileset or template, and the class the element inherits members from
+type propertiesVisitor func(props *Properties, class string)
+
+// elementWalker walks the elements of a decoded map, tileset or template,
+// calling visit with their properties and path with the paths of the files
+// they refer to
+type elementWalker func(visit propertiesVisitor, path func(*string))
+
+// prepare runs the passes of the loader over the elements walked, before
+// external files are loaded: elements inherit the members of their class,
+// class properties are filled from their types, then variables are
+// substituted, in the members added too
+func (l *loader) prepare(walk elementWalker) {
+	if l == nil {
+		return
+	}
+	if len(l.propertyTypes) > 0 {
+		l.inheritClasses(walk)
+		l.fillPropertyTypes(walk)
+	}
+	if len(l.variables) > 0 {
+		l.expandVariables(walk)
+	}
+}
+
+// prepareMap prepares a decoded map, see prepare
+func (l *loader) prepareMap(m *Map) {
+	if l == nil {
+		return
+	}
+	if m.Properties == nil && l.propertyTypes[m.Class] != nil {
+		m.Properties = &Properties{}
+	}
+	l.prepare(func(visit propertiesVisitor, path func(*string)) {
+		if m.Properties != nil {
+			visit(m.Properties, m.Class)
+		}
+		for _, ts := range m.Tilesets {
+			path(&ts.Source)
+			if len(ts.Source) == 0 {
+				walkTileset(ts, visit, path)
+			}
+		}
+		walkLayerElements(m.Layers, m.ObjectGroups, m.ImageLayers, m.Groups, visit, path)
+	})
+}
+
+// prepareTileset prepares a decoded tileset, see prepare
+func (l *loader) prepareTileset(ts *Tileset) {
+	l.prepare(func(visit propertiesVisitor, path func(*string)) {
+		walkTileset(ts, visit, path)
+	})
+}
+
+// prepareTemplate prepares a decoded template, see prepare
+func (l *loader) prepareTemplate(t *Template) {
+	if t == nil {
+		return
+	}
+	l.prepare(func(visit propertiesVisitor, path func(*string)) {
+		if t.Tileset != nil {
+			path(&t.Tileset.Source)
+		}
+		if t.Object != nil {
+			visit(&t.Object.Properties, t.Object.Class)
+		}
+	})
+}
+
+func walkLayerElements(layers []*Layer, objectGroups []*ObjectGroup, imageLayers []*ImageLayer, groups []*Group, visit propertiesVisitor, path func(*string)) {
+	for _, layer := range layers {
+		visit(&layer.Properties, layer.Class)
+	}
+	for _, g := range objectGroups {
+		walkObjectGroup(g, visit, path)
+	}
+	for _, il := range imageLayers {
+		visit(&il.Properties, il.Class)
+		walkImage(il.Image, path)
+	}
+	for _, g := range groups {
+		visit(&g.Properties, g.Class)
+		walkLayerElements(g.Layers, g.ObjectGroups, g.ImageLayers, g.Groups, visit, path)
+	}
+}
+
+func walkObjectGroup(g *ObjectGroup, visit propertiesVisitor, path func(*string)) {
+	visit(&g.Properties, g.Class)
+	for _, o := range g.Objects {
+		class := o.Class
+		if len(o.TemplateSource) > 0 {
+			// Objects with templates inherit once their template applied
+			class = ""
+		}
+		visit(&o.Properties, class)
+		path(&o.TemplateSource)
+	}
+}
+
+func walkTileset(ts *Tileset, visit propertiesVisitor, path func(*string)) {
+	visit(&ts.Properties, ts.Class)
+	walkImage(ts.Image, path)
+	for _, t := range ts.Tiles {
+		visit(&t.Properties, t.Class)
+		walkImage(t.Image, path)
+		for _, g := range t.ObjectGroups {
+			walkObjectGroup(g, visit, path)
+		}
+	}
+}
+
+func walkImage(img *Image, path func(*string)) {
+	if img != nil {
+		path(&img.Source)
+	}
+}
@@ -23,9 +23,9 @@ SOFTWARE.
 package tiled
 
 import (
-	"encoding/hex"
 	"encoding/xml"
 	"image/color"
+	"path/filepath"
 	"strconv"
 )
 
@@ -36,13 +36,18 @@ type Properties []*Property
 type Property struct {
 	// The name of the property.
 	Name string
-	// The type of the property. Can be string (default), int, float, bool, color or file (since 0.16, with color and file added in 0.17).
+	// The type of the property. Can be string (default), int, float, bool, color or file (since 0.16, with color and file added in 0.17),
+	// object (since 1.4) or class (since 1.8).
 	Type string
 	// The value of the property.
 	// Boolean properties have a value of either "true" or "false".
 	// Color properties are stored in the format #AARRGGBB.
 	// File properties are stored as paths relative from the location of the map file.
 	Value string
+	// The name of the custom type of class and enum properties (since 1.8)
+	PropertyType string
+	// Members of class properties (since 1.8)
+	Properties Properties
 }
 
 // UnmarshalXML implements the xml.Unmarshaler interface for Property. Setting Value even if it's in the inner text.
@@ -57,8 +62,20 @@ func (p *Property) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 		case "value":
 			p.Value = attr.Value
 			valueFoundInAttr = true
+		case "propertytype":
+			p.PropertyType = attr.Value
 		}
 	}
+	if p.Type == "class" {
+		var members struct {
+			Properties Properties `xml:"properties>property"`
+		}
+		if err := d.DecodeElement(&members, &start); err != nil {
+			return err
+		}
+		p.Properties = members.Properties
+		return nil
+	}
 	if valueFoundInAttr {
 		return d.Skip()
 	}
@@ -136,6 +153,17 @@ func (p Properties) GetFloat(name string) float64 {
 	return 0
 }
 
+// GetClass returns the members of the first class property found using
+// name, or nil
+func (p Properties) GetClass(name string) Properties {
+	for _, property := range p {
+		if property.Name == name && property.Type == "class" {
+			return property.Properties
+		}
+	}
+	return nil
+}
+
 // GetColor returns a color.Color by parsing the first property found using
 // name. If unable to parse the value or find the value nil is returned.
 func (p Properties) GetColor(name string) color.Color {
@@ -143,18 +171,114 @@ func (p Properties) GetColor(name string) color.Color {
 		if property.Name != name || property.Type != "color" {
 			continue
 		}
-		str := property.Value
-		if len(str) < 6 {
-			continue
-		}
-		if str[0] == '#' {
-			str = str[1:]
-		}
-		b, err := hex.DecodeString(str)
+		c, err := parseHexColor(property.Value)
 		if err != nil {
 			continue
 		}
-		return &color.RGBA{b[1], b[2], b[3], b[0]}
+		return &c
 	}
 	return nil
 }
+
+// FileResolver resolves paths relative to the file they were loaded from, as
+// Map and Tileset do
+type FileResolver interface {
+	GetFileFullPath(fileName string) string
+}
+
+// GetFile returns the path of the first file property found using name, or
+// an empty string. Paths are relative to the file the properties were loaded
+// from and are resolved with base: the Map, or the Tileset for properties of
+// tilesets and their tiles. A nil base returns the path as written.
+func (p Properties) GetFile(name string, base FileResolver) string {
+	for _, property := range p {
+		if property.Name != name || property.Type != "file" || property.Value == "" {
+			continue
+		}
+		if base == nil || filepath.IsAbs(property.Value) {
+			return property.Value
+		}
+		return base.GetFileFullPath(property.Value)
+	}
+	return ""
+}
+
+// GetObject returns the ID of the object referenced by the first object
+// property found using name, or 0 when unset
+func (p Properties) GetObject(name string) uint32 {
+	for _, property := range p {
+		if property.Name == name && property.Type == "object" {
+			v, err := strconv.ParseUint(property.Value, 10, 32)
+			if err != nil {
+				continue
+			}
+			return uint32(v)
+		}
+	}
+	return 0
+}
+
+// ResolveObject returns the object of m referenced by the first object
+// property found using name, or nil when unset or missing from m
+func (p Properties) ResolveObject(name string, m *Map) *Object {
+	return m.ObjectByID(p.GetObject(name))
+}
+
+// GetProperty returns the value of the first property found using name,
+// converted to T as its Tiled type allows, and whether it was found and
+// converted:
+//
+//   - string holds the value of any property but class ones
+//   - int holds int properties, and the IDs of object properties
+//   - float64 holds float and int properties
+//   - bool holds bool properties
+//   - uint32 holds the IDs of object properties
+//   - color.Color holds color properties
+//   - Properties holds the members of class properties
+//
+// Other types are never found.
+func GetProperty[T any](p Properties, name string) (T, bool) {
+	var v T
+	property := p.find(name)
+	if property == nil {
+		return v, false
+	}
+
+	var ok bool
+	switch v := any(&v).(type) {
+	case *string:
+		*v, ok = property.Value, property.Type != "class"
+	case *int:
+		if property.Type == "int" || property.Type == "object" {
+			n, err := strconv.Atoi(property.Value)
+			*v, ok = n, err == nil
+		}
+	case *float64:
+		if property.Type == "float" || property.Type == "int" {
+			f, err := strconv.ParseFloat(property.Value, 64)
+			*v, ok = f, err == nil
+		}
+	case *bool:
+		if property.Type == "bool" {
+			b, err := strconv.ParseBool(property.Value)
+			*v, ok = b, err == nil
+		}
+	case *uint32:
+		if property.Type == "object" {
+			n, err := strconv.ParseUint(property.Value, 10, 32)
+			*v, ok = uint32(n), err == nil
+		}
+	case *color.Color:
+		if property.Type == "color" {
+			c, err := parseHexColor(property.Value)
+			*v, ok = c, err == nil
+		}
+	case *Properties:
+		*v, ok = property.Properties, property.Type == "class"
+	}
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return v, true
+}
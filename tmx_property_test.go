@@ -23,7 +23,11 @@ SOFTWARE.
 package tiled
 
 import (
+	"bytes"
+	"image/color"
+	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -57,3 +61,308 @@ func TestGetProperty(t *testing.T) {
 	assert.Equal(t, 1.23, props.GetFloat("float-name"))
 	assert.Equal(t, true, props.GetBool("bool-name"))
 }
+
+func TestPropertyTypes(t *testing.T) {
+	project := `{
+  "automappingRulesFile": "",
+  "propertyTypes": [
+    {"id": 1, "name": "Stats", "type": "class", "useAs": ["property", "object"], "members": [
+      {"name": "hp", "type": "int", "value": 10},
+      {"name": "speed", "type": "float", "value": 1.5},
+      {"name": "resist", "type": "class", "propertyType": "Resist", "value": {"fire": 2}}
+    ]},
+    {"id": 2, "name": "Resist", "type": "class", "members": [
+      {"name": "fire", "type": "int", "value": 0},
+      {"name": "ice", "type": "int", "value": 0}
+    ]},
+    {"id": 3, "name": "Dir", "type": "enum", "storageType": "string", "values": ["N", "S"]}
+  ]
+}`
+	types, err := ReadPropertyTypes(bytes.NewBufferString(project))
+	assert.NoError(t, err)
+	assert.Len(t, types, 3)
+	assert.Equal(t, []string{"N", "S"}, types["Dir"].Values)
+	assert.Equal(t, "2", types["Stats"].Members[2].Properties.GetString("fire"))
+
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<properties>
+<property name="boss" type="class" propertytype="Stats">
+<properties>
+<property name="hp" type="int" value="99"/>
+<property name="resist" type="class" propertytype="Resist">
+<properties><property name="ice" type="int" value="5"/></properties>
+</property>
+</properties>
+</property>
+<property name="minion" type="class" propertytype="Stats"/>
+<property name="facing" type="string" propertytype="Dir" value="S"/>
+</properties>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	boss := (*m.Properties)[0]
+	assert.Equal(t, "Stats", boss.PropertyType)
+	assert.Len(t, boss.Properties, 2)
+	assert.Equal(t, "Dir", (*m.Properties)[2].PropertyType)
+
+	m, err = LoadReader(".", bytes.NewBufferString(tmx), WithPropertyTypes(types))
+	assert.NoError(t, err)
+	boss = (*m.Properties)[0]
+	assert.Equal(t, 99, boss.Properties.GetInt("hp"))
+	assert.Equal(t, 1.5, boss.Properties.GetFloat("speed"))
+	resist := boss.Properties.find("resist")
+	assert.Equal(t, 5, resist.Properties.GetInt("ice"))
+	assert.Equal(t, 2, resist.Properties.GetInt("fire"))
+
+	minion := (*m.Properties)[1]
+	assert.Equal(t, 10, minion.Properties.GetInt("hp"))
+	assert.Equal(t, 2, minion.Properties.find("resist").Properties.GetInt("fire"))
+
+	// Defaults are copied, not shared
+	minion.Properties[0].Value = "1"
+	assert.Equal(t, "10", types["Stats"].Members[0].Value)
+}
+
+func TestClassProperties(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<objectgroup id="1" name="Entities">
+<object id="1" name="Orc" x="0" y="0">
+<properties>
+<property name="stats" type="class" propertytype="Stats">
+<properties>
+<property name="hp" type="int" value="30"/>
+<property name="resist" type="class" propertytype="Resist">
+<properties><property name="fire" type="float" value="0.5"/></properties>
+</property>
+</properties>
+</property>
+<property name="target" type="object" value="2"/>
+<property name="empty" type="class" propertytype="Stats"/>
+</properties>
+</object>
+</objectgroup>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	props := m.ObjectGroups[0].Objects[0].Properties
+	stats := props.GetClass("stats")
+	assert.Equal(t, 30, stats.GetInt("hp"))
+	assert.Equal(t, 0.5, stats.GetClass("resist").GetFloat("fire"))
+	assert.Equal(t, "2", props.GetString("target"))
+	assert.Nil(t, props.GetClass("empty"))
+	assert.Nil(t, props.GetClass("target"))
+
+	var out bytes.Buffer
+	assert.NoError(t, newTMXEncoder(&out, ".").encodeMap(m))
+	m, err = LoadReader(".", &out)
+	assert.NoError(t, err)
+	props = m.ObjectGroups[0].Objects[0].Properties
+	assert.Equal(t, "Stats", props[0].PropertyType)
+	assert.Equal(t, 0.5, props.GetClass("stats").GetClass("resist").GetFloat("fire"))
+}
+
+func TestTypedProperties(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<properties>
+<property name="tint" type="color" value="#80ff0000"/>
+<property name="short" type="color" value="#00ff00"/>
+<property name="unset" type="color" value=""/>
+<property name="music" type="file" value="../audio/theme.ogg"/>
+<property name="spawn" type="object" value="12"/>
+<property name="none" type="object" value="0"/>
+</properties>
+</map>`
+
+	m, err := LoadReader("maps", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	props := *m.Properties
+
+	assert.Equal(t, &color.RGBA{R: 0xff, A: 0x80}, props.GetColor("tint"))
+	assert.Equal(t, &color.RGBA{G: 0xff, A: 0xff}, props.GetColor("short"))
+	assert.Nil(t, props.GetColor("unset"))
+	assert.Nil(t, props.GetColor("music"))
+
+	assert.Equal(t, filepath.Join("audio", "theme.ogg"), props.GetFile("music", m))
+	assert.Equal(t, "../audio/theme.ogg", props.GetFile("music", nil))
+	assert.Empty(t, props.GetFile("tint", m))
+
+	assert.Equal(t, uint32(12), props.GetObject("spawn"))
+	assert.Zero(t, props.GetObject("none"))
+	assert.Zero(t, props.GetObject("music"))
+}
+
+func TestResolveObject(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<objectgroup id="1" name="Switches">
+<object id="1" name="Lever" x="0" y="0">
+<properties>
+<property name="opens" type="object" value="3"/>
+<property name="gone" type="object" value="9"/>
+<property name="none" type="object" value="0"/>
+</properties>
+</object>
+</objectgroup>
+<group id="2" name="Castle">
+<objectgroup id="3" name="Doors">
+<object id="3" name="Gate" x="16" y="0"/>
+</objectgroup>
+</group>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	props := m.ObjectGroups[0].Objects[0].Properties
+
+	gate := props.ResolveObject("opens", m)
+	if assert.NotNil(t, gate) {
+		assert.Equal(t, "Gate", gate.Name)
+	}
+	assert.Same(t, m.ObjectGroups[0].Objects[0], m.ObjectByID(1))
+	assert.Nil(t, props.ResolveObject("gone", m))
+	assert.Nil(t, props.ResolveObject("none", m))
+	assert.Nil(t, props.ResolveObject("missing", m))
+}
+
+func TestClassInheritance(t *testing.T) {
+	types, err := ReadPropertyTypes(bytes.NewBufferString(`{"propertyTypes": [
+  {"id": 1, "name": "Door", "type": "class", "useAs": ["object", "layer", "tile"], "members": [
+    {"name": "locked", "type": "bool", "value": true},
+    {"name": "key", "type": "string", "value": "gold"}
+  ]}
+]}`))
+	assert.NoError(t, err)
+
+	fsys := fstest.MapFS{
+		"door.tx": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<template><object name="Door" class="Door" width="16" height="16">
+<properties><property name="key" value="silver"/></properties>
+</object></template>`)},
+		"level.tmx": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="doors" tilewidth="16" tileheight="16" tilecount="1" columns="1">
+<tile id="0" class="Door"/>
+</tileset>
+<layer id="1" name="Doors" class="Door" width="1" height="1"><data encoding="csv">0</data></layer>
+<objectgroup id="2" name="Objects">
+<object id="1" name="Front" class="Door" x="0" y="0">
+<properties><property name="locked" type="bool" value="false"/></properties>
+</object>
+<object id="2" template="door.tx" x="16" y="0"/>
+<object id="3" name="Plain" x="32" y="0"/>
+</objectgroup>
+</map>`)},
+	}
+
+	m, err := LoadFile("level.tmx", WithFileSystem(fsys), WithPropertyTypes(types))
+	assert.NoError(t, err)
+
+	front := m.ObjectGroups[0].Objects[0].Properties
+	assert.False(t, front.GetBool("locked"))
+	assert.Equal(t, "gold", front.GetString("key"))
+	assert.Len(t, front, 2)
+
+	templated := m.ObjectGroups[0].Objects[1].Properties
+	assert.True(t, templated.GetBool("locked"))
+	assert.Equal(t, "silver", templated.GetString("key"))
+	assert.Len(t, templated, 2)
+
+	assert.Empty(t, m.ObjectGroups[0].Objects[2].Properties)
+	assert.Equal(t, "gold", m.Layers[0].Properties.GetString("key"))
+	assert.True(t, m.Tilesets[0].Tiles[0].Properties.GetBool("locked"))
+
+	m, err = LoadFile("level.tmx", WithFileSystem(fsys))
+	assert.NoError(t, err)
+	assert.Len(t, m.ObjectGroups[0].Objects[0].Properties, 1)
+}
+
+func TestLayerTileProperties(t *testing.T) {
+	types, err := ReadPropertyTypes(bytes.NewBufferString(`{"propertyTypes": [
+  {"id": 1, "name": "Wall", "type": "class", "useAs": ["tile"], "members": [
+    {"name": "solid", "type": "bool", "value": true}
+  ]}
+]}`))
+	assert.NoError(t, err)
+
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="3" height="1" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="walls" tilewidth="16" tileheight="16" tilecount="2" columns="2">
+<tile id="0" class="Wall"/>
+<tile id="1"><properties><property name="cost" type="int" value="3"/></properties></tile>
+</tileset>
+<layer id="1" name="Walls" width="3" height="1"><data encoding="csv">1,2,0</data></layer>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx), WithPropertyTypes(types))
+	assert.NoError(t, err)
+	tiles := m.Layers[0].Tiles
+	assert.True(t, tiles[0].Properties().GetBool("solid"))
+	assert.False(t, tiles[1].Properties().GetBool("solid"))
+	assert.Equal(t, 3, tiles[1].Properties().GetInt("cost"))
+	assert.Nil(t, tiles[2].Properties())
+}
+
+func TestGetPropertyGeneric(t *testing.T) {
+	props := Properties{
+		{Name: "cost", Type: "int", Value: "4"},
+		{Name: "speed", Type: "float", Value: "1.5"},
+		{Name: "solid", Type: "bool", Value: "true"},
+		{Name: "label", Value: "door"},
+		{Name: "tint", Type: "color", Value: "#ff102030"},
+		{Name: "target", Type: "object", Value: "7"},
+		{Name: "stats", Type: "class", Properties: Properties{{Name: "hp", Type: "int", Value: "10"}}},
+		{Name: "broken", Type: "int", Value: "many"},
+	}
+
+	cost, ok := GetProperty[int](props, "cost")
+	assert.True(t, ok)
+	assert.Equal(t, 4, cost)
+	f, ok := GetProperty[float64](props, "cost")
+	assert.True(t, ok)
+	assert.Equal(t, 4.0, f)
+	f, ok = GetProperty[float64](props, "speed")
+	assert.True(t, ok)
+	assert.Equal(t, 1.5, f)
+	_, ok = GetProperty[int](props, "speed")
+	assert.False(t, ok)
+
+	solid, ok := GetProperty[bool](props, "solid")
+	assert.True(t, ok)
+	assert.True(t, solid)
+	_, ok = GetProperty[bool](props, "label")
+	assert.False(t, ok)
+
+	label, ok := GetProperty[string](props, "label")
+	assert.True(t, ok)
+	assert.Equal(t, "door", label)
+	s, ok := GetProperty[string](props, "cost")
+	assert.True(t, ok)
+	assert.Equal(t, "4", s)
+
+	c, ok := GetProperty[color.Color](props, "tint")
+	assert.True(t, ok)
+	assert.Equal(t, color.RGBA{R: 0x10, G: 0x20, B: 0x30, A: 0xff}, c)
+
+	id, ok := GetProperty[uint32](props, "target")
+	assert.True(t, ok)
+	assert.Equal(t, uint32(7), id)
+
+	stats, ok := GetProperty[Properties](props, "stats")
+	assert.True(t, ok)
+	assert.Equal(t, 10, stats.GetInt("hp"))
+	_, ok = GetProperty[string](props, "stats")
+	assert.False(t, ok)
+
+	broken, ok := GetProperty[int](props, "broken")
+	assert.False(t, ok)
+	assert.Zero(t, broken)
+	_, ok = GetProperty[int](props, "missing")
+	assert.False(t, ok)
+	_, ok = GetProperty[[]byte](props, "label")
+	assert.False(t, ok)
+}
@@ -0,0 +1,225 @@
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// PropertyType is a custom property type defined in a Tiled project
+type PropertyType struct {
+	ID   int
+	Name string
+	// "class" or "enum"
+	Type string
+	// Members of class types, holding their default values
+	Members Properties
+	// What class types may be used for: "property", "map", "layer",
+	// "object", "tile", "tileset", "wangcolor", "wangset" or "project"
+	UseAs []string
+	// Values of enum types
+	Values []string
+	// How enum values are stored, "string" or "int"
+	StorageType string
+	// Whether several values of an enum type can be combined as flags
+	ValuesAsFlags bool
+}
+
+// PropertyTypes are custom property types, by name
+type PropertyTypes map[string]*PropertyType
+
+// WithPropertyTypes returns an option filling in the members missing from
+// properties of custom class types with their default values, recursively,
+// so the properties of loaded maps hold every member of their class. Maps,
+// layers, objects, tilesets and tiles of a custom class likewise inherit the
+// members of their class they don't set, as Tiled shows them.
+func WithPropertyTypes(types PropertyTypes) LoaderOption {
+	return func(l *loader) {
+		l.propertyTypes = types
+	}
+}
+
+type jsonPropertyType struct {
+	ID            int                 `json:"id"`
+	Name          string              `json:"name"`
+	Type          string              `json:"type"`
+	Members       []*jsonPropertyType `json:"members"`
+	UseAs         []string            `json:"useAs"`
+	Values        []string            `json:"values"`
+	StorageType   string              `json:"storageType"`
+	ValuesAsFlags bool                `json:"valuesAsFlags"`
+	// Set for class members
+	PropertyType string          `json:"propertyType"`
+	Value        json.RawMessage `json:"value"`
+}
+
+// LoadPropertyTypes loads custom property types from a file exported by Tiled
+// (propertytypes.json) or from a Tiled project file (.tiled-project).
+func LoadPropertyTypes(fileName string, options ...LoaderOption) (PropertyTypes, error) {
+	l := newLoader(options...)
+	f, err := l.open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadPropertyTypes(f)
+}
+
+// ReadPropertyTypes reads custom property types in the format of
+// propertytypes.json files, or of Tiled project files.
+func ReadPropertyTypes(r io.Reader) (PropertyTypes, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var defs []*jsonPropertyType
+	if err := json.Unmarshal(data, &defs); err != nil {
+		var project struct {
+			PropertyTypes []*jsonPropertyType `json:"propertyTypes"`
+		}
+		if json.Unmarshal(data, &project) != nil {
+			return nil, err
+		}
+		defs = project.PropertyTypes
+	}
+
+	types := PropertyTypes{}
+	byName := map[string]*jsonPropertyType{}
+	for _, def := range defs {
+		byName[def.Name] = def
+		types[def.Name] = &PropertyType{
+			ID:            def.ID,
+			Name:          def.Name,
+			Type:          def.Type,
+			UseAs:         def.UseAs,
+			Values:        def.Values,
+			StorageType:   def.StorageType,
+			ValuesAsFlags: def.ValuesAsFlags,
+		}
+	}
+	for _, def := range defs {
+		for _, m := range def.Members {
+			p, err := jsonMember(byName, m, m.Value, 0)
+			if err != nil {
+				return nil, fmt.Errorf("tiled: property type %s: %w", def.Name, err)
+			}
+			types[def.Name].Members = append(types[def.Name].Members, p)
+		}
+	}
+	return types, nil
+}
+
+// maxClassDepth bounds the nesting of class members, so types including
+// themselves can't recurse forever
+const maxClassDepth = 32
+
+// jsonMember converts the JSON value of a class member to a Property, using
+// the definitions of class types to convert the values of class members
+func jsonMember(defs map[string]*jsonPropertyType, m *jsonPropertyType, value json.RawMessage, depth int) (*Property, error) {
+	p := &Property{Name: m.Name, Type: m.Type, PropertyType: m.PropertyType}
+	if m.Type != "class" {
+		p.Value = jsonString(value)
+		return p, nil
+	}
+
+	var values map[string]json.RawMessage
+	if len(value) > 0 && string(value) != "null" {
+		if err := json.Unmarshal(value, &values); err != nil {
+			return nil, fmt.Errorf("member %s: %w", m.Name, err)
+		}
+	}
+	def := defs[m.PropertyType]
+	if def == nil || depth >= maxClassDepth {
+		return p, nil
+	}
+	for _, dm := range def.Members {
+		v, ok := values[dm.Name]
+		if !ok {
+			continue
+		}
+		member, err := jsonMember(defs, dm, v, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		p.Properties = append(p.Properties, member)
+	}
+	return p, nil
+}
+
+// inheritClasses appends to the properties of the elements walked the
+// members of their class they don't set
+func (l *loader) inheritClasses(walk elementWalker) {
+	walk(l.inheritClass, func(*string) {})
+}
+
+// inheritClass appends the members of the custom class of an element missing
+// from its properties, with their default values, as Tiled shows them
+func (l *loader) inheritClass(props *Properties, class string) {
+	if l == nil || class == "" {
+		return
+	}
+	l.propertyTypes.fillClass(props, nil, class, 0)
+}
+
+// fillPropertyTypes fills in the members missing from the class properties
+// of the elements walked
+func (l *loader) fillPropertyTypes(walk elementWalker) {
+	walk(func(props *Properties, _ string) {
+		l.propertyTypes.fill(props)
+	}, func(*string) {})
+}
+
+// fill appends the members missing from class properties with their
+// default values, including class properties nested in members
+func (types PropertyTypes) fill(props *Properties) {
+	if len(types) == 0 {
+		return
+	}
+	for _, p := range *props {
+		if p.Type == "class" {
+			types.fillClass(&p.Properties, nil, p.PropertyType, 1)
+		}
+		if len(p.Properties) > 0 {
+			types.fill(&p.Properties)
+		}
+	}
+}
+
+// fillClass appends to the members of a class property the missing ones,
+// from defaults first, then from the defaults of its type
+func (types PropertyTypes) fillClass(props *Properties, defaults Properties, propertyType string, depth int) {
+	if depth >= maxClassDepth {
+		return
+	}
+	types.fillMembers(props, defaults, depth)
+	if t := types[propertyType]; t != nil {
+		types.fillMembers(props, t.Members, depth)
+	}
+}
+
+// fillMembers appends copies of the members missing from props
+func (types PropertyTypes) fillMembers(props *Properties, members Properties, depth int) {
+	for _, m := range members {
+		p := props.find(m.Name)
+		if p == nil {
+			p = &Property{Name: m.Name, Value: m.Value}
+			*props = append(*props, p)
+		}
+		// Members of JSON class properties have guessed types
+		p.Type, p.PropertyType = m.Type, m.PropertyType
+		if p.Type == "class" && m.Type == "class" {
+			types.fillClass(&p.Properties, m.Properties, m.PropertyType, depth+1)
+		}
+	}
+}
+
+// find returns the first property with the given name, or nil
+func (p Properties) find(name string) *Property {
+	for _, property := range p {
+		if property.Name == name {
+			return property
+		}
+	}
+	return nil
+}
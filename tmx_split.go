@@ -0,0 +1,176 @@
+package tiled
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// ErrInvalidSplit error is returned when a map can't be split in regions
+var ErrInvalidSplit = errors.New("tiled: invalid split")
+
+// MapRegion is a part of a map cut by Split
+type MapRegion struct {
+	// Position of the region in the original map, in tiles
+	X, Y int
+	// The map of the region. Its tile layers and objects are moved so the
+	// region starts at 0,0.
+	Map *Map
+}
+
+// Split cuts an orthogonal map into regions of at most width by height
+// tiles, row by row, so large worlds can be streamed piecewise. Region maps
+// share the tilesets of m. Objects go to the region containing their
+// position. Image layers are not split and are left out.
+func (m *Map) Split(width, height int) ([]*MapRegion, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("%w: region size %dx%d", ErrInvalidSplit, width, height)
+	}
+	if m.Orientation != "orthogonal" {
+		return nil, fmt.Errorf("%w: %s orientation", ErrInvalidSplit, m.Orientation)
+	}
+
+	cols := (m.Width + width - 1) / width
+	rows := (m.Height + height - 1) / height
+
+	var res []*MapRegion
+	for row := 0; row < rows; row++ {
+		for col := 0; col < cols; col++ {
+			x, y := col*width, row*height
+			region := *m
+			region.Width = min(width, m.Width-x)
+			region.Height = min(height, m.Height-y)
+			region.Layers = nil
+			region.ObjectGroups = nil
+			region.ImageLayers = nil
+			region.Groups = nil
+
+			s := &splitter{m: m, region: &region, x: x, y: y, cols: cols, rows: rows, width: width, height: height}
+			region.Layers = s.layers(m.Layers)
+			region.ObjectGroups = s.objectGroups(m.ObjectGroups)
+			region.Groups = s.groups(m.Groups)
+
+			res = append(res, &MapRegion{X: x, Y: y, Map: &region})
+		}
+	}
+	return res, nil
+}
+
+// splitter copies the layers of a map into one of its regions
+type splitter struct {
+	m, region     *Map
+	x, y          int
+	cols, rows    int
+	width, height int
+}
+
+func (s *splitter) layers(layers []*Layer) []*Layer {
+	var res []*Layer
+	for _, l := range layers {
+		rl := *l
+		rl._map = s.region
+		rl.data = nil
+		rl.Tiles = make([]*LayerTile, 0, s.region.Width*s.region.Height)
+		rl.empty = true
+		for y := s.y; y < s.y+s.region.Height; y++ {
+			for x := s.x; x < s.x+s.region.Width; x++ {
+				tile := l.Tiles[y*s.m.Width+x]
+				if tile != nil && !tile.IsNil() {
+					rl.empty = false
+				}
+				rl.Tiles = append(rl.Tiles, tile)
+			}
+		}
+		res = append(res, &rl)
+	}
+	return res
+}
+
+// contains reports whether the region holds the position. Positions outside
+// of the map belong to the closest region.
+func (s *splitter) contains(x, y float64) bool {
+	clamp := func(v, n int) int {
+		return max(0, min(v, n-1))
+	}
+	col := clamp(int(math.Floor(x/float64(s.width*s.m.TileWidth))), s.cols)
+	row := clamp(int(math.Floor(y/float64(s.height*s.m.TileHeight))), s.rows)
+	return col*s.width == s.x && row*s.height == s.y
+}
+
+func (s *splitter) objectGroups(groups []*ObjectGroup) []*ObjectGroup {
+	var res []*ObjectGroup
+	for _, g := range groups {
+		rg := *g
+		rg.Objects = nil
+		for _, o := range g.Objects {
+			if !s.contains(o.X, o.Y) {
+				continue
+			}
+			ro := *o
+			ro.X -= float64(s.x * s.m.TileWidth)
+			ro.Y -= float64(s.y * s.m.TileHeight)
+			rg.Objects = append(rg.Objects, &ro)
+		}
+		res = append(res, &rg)
+	}
+	return res
+}
+
+func (s *splitter) groups(groups []*Group) []*Group {
+	var res []*Group
+	for _, g := range groups {
+		rg := *g
+		rg.Layers = s.layers(g.Layers)
+		rg.ObjectGroups = s.objectGroups(g.ObjectGroups)
+		rg.ImageLayers = nil
+		rg.Groups = s.groups(g.Groups)
+		res = append(res, &rg)
+	}
+	return res
+}
+
+// WriteRegions writes each region in dir as its own TMX file, named
+// name_x_y.tmx after the position of the region in tiles, and a name.world
+// Tiled world file indexing them. Paths to tilesets, images and templates
+// are made relative to dir.
+func WriteRegions(dir, name string, regions []*MapRegion) error {
+	world := worldFile{Type: "world"}
+
+	for _, r := range regions {
+		fileName := fmt.Sprintf("%s_%d_%d.tmx", name, r.X, r.Y)
+		if err := writeTMX(filepath.Join(dir, fileName), r.Map); err != nil {
+			return err
+		}
+
+		world.Maps = append(world.Maps, worldFileMap{
+			FileName: fileName,
+			X:        r.X * r.Map.TileWidth,
+			Y:        r.Y * r.Map.TileHeight,
+			Width:    r.Map.Width * r.Map.TileWidth,
+			Height:   r.Map.Height * r.Map.TileHeight,
+		})
+	}
+
+	data, err := json.MarshalIndent(world, "", "    ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name+".world"), data, 0o644)
+}
+
+func writeTMX(fileName string, m *Map) (err error) {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	return newTMXEncoder(f, filepath.Dir(fileName)).encodeMap(m)
+}
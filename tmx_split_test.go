@@ -0,0 +1,64 @@
+package tiled
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplit(t *testing.T) {
+	m, err := LoadFile(filepath.Join(GetAssetsDirectory(), "examples/sewers.tmx"))
+	assert.NoError(t, err)
+
+	regions, err := m.Split(4, 4)
+	assert.NoError(t, err)
+	if !assert.Len(t, regions, 4) {
+		return
+	}
+	assert.Equal(t, 2, regions[1].Map.Width)
+	assert.Equal(t, 4, regions[1].Map.Height)
+	assert.Equal(t, 4, regions[2].Y)
+
+	regions, err = m.Split(3, 3)
+	assert.NoError(t, err)
+	if !assert.Len(t, regions, 4) {
+		return
+	}
+
+	dir := t.TempDir()
+	assert.NoError(t, WriteRegions(dir, "sewers", regions))
+
+	last, err := LoadFile(filepath.Join(dir, "sewers_3_3.tmx"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 3, last.Width)
+	for i, l := range last.Layers {
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 3; x++ {
+				assert.Equal(t, m.Layers[i].Tiles[(y+3)*6+x+3].gid(), l.Tiles[y*3+x].gid())
+			}
+		}
+	}
+	assert.Equal(t, "sewer_tileset.png", filepath.Base(last.Tilesets[0].Image.Source))
+	if assert.Len(t, last.ObjectGroups[0].Objects, 1) {
+		drain := last.ObjectGroups[0].Objects[0]
+		assert.Equal(t, "Drain", drain.Name)
+		assert.Equal(t, 0.0, drain.X)
+		assert.Len(t, drain.Polygons, 1)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "sewers.world"))
+	assert.NoError(t, err)
+	var world worldFile
+	assert.NoError(t, json.Unmarshal(data, &world))
+	if assert.Len(t, world.Maps, 4) {
+		assert.Equal(t, worldFileMap{FileName: "sewers_3_0.tmx", X: 72, Y: 0, Width: 72, Height: 72}, world.Maps[1])
+	}
+
+	_, err = m.Split(0, 3)
+	assert.ErrorIs(t, err, ErrInvalidSplit)
+}
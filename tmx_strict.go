@@ -0,0 +1,443 @@
+package tiled
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ParseMode selects how unknown and inconsistent data is handled while
+// loading a map
+type ParseMode int
+
+const (
+	// ParseDefault ignores unknown attributes and elements, fails on data
+	// that can't be decoded and reports inconsistent data to the warning
+	// handler
+	ParseDefault ParseMode = iota
+	// ParseStrict also fails on unknown attributes and elements of TMX, TSX
+	// and TX documents, on unknown keys of their JSON counterparts, and on
+	// inconsistent data, as asset pipelines want
+	ParseStrict
+	// ParseTolerant loads what can be loaded, reporting unknown attributes,
+	// elements and keys and the data skipped or fixed to the warning handler, as
+	// games loading user-made maps want
+	ParseTolerant
+)
+
+var (
+	// ErrUnknownXML error is returned in strict mode for attributes and
+	// elements that are not part of the TMX format
+	ErrUnknownXML = errors.New("tiled: unknown attribute or element")
+	// ErrUnknownJSON error is returned in strict mode for keys that are not
+	// part of the Tiled JSON format
+	ErrUnknownJSON = errors.New("tiled: unknown JSON key")
+	// ErrInconsistentData error is returned in strict mode for data
+	// contradicting other parts of the map
+	ErrInconsistentData = errors.New("tiled: inconsistent data")
+)
+
+// WithParseMode returns an option selecting how unknown and inconsistent
+// data is handled. Problems tolerated are reported to the handler set with
+// WithWarningHandler.
+func WithParseMode(mode ParseMode) LoaderOption {
+	return func(l *loader) {
+		l.mode = mode
+	}
+}
+
+func (l *loader) parseMode() ParseMode {
+	if l == nil {
+		return ParseDefault
+	}
+	return l.mode
+}
+
+// tolerate reports err to the warning handler, along with what was done
+// about it, and returns nil in tolerant mode. It returns err otherwise.
+func (l *loader) tolerate(err error, format string, args ...any) error {
+	if l.parseMode() != ParseTolerant {
+		return err
+	}
+	l.warning(fmt.Errorf("%w: "+format, append([]any{err}, args...)...))
+	return nil
+}
+
+// inconsistent returns err in strict mode, and reports it to the warning
+// handler otherwise. Unknown attributes and elements are inconsistent with
+// the format.
+func (l *loader) inconsistent(err error) error {
+	if l.parseMode() == ParseStrict {
+		return err
+	}
+	l.warning(err)
+	return nil
+}
+
+// checkConsistency reports tilesets with overlapping GIDs and objects sharing
+// an ID, as errors in strict mode
+func (m *Map) checkConsistency() error {
+	var problems []string
+	var loaded []*Tileset
+	for _, ts := range m.Tilesets {
+		if (ts.Source == "" || ts.SourceLoaded) && ts.TileCount > 0 {
+			loaded = append(loaded, ts)
+		}
+	}
+	for i, ts := range loaded {
+		for _, other := range loaded[i+1:] {
+			if ts.FirstGID < other.FirstGID+uint32(other.TileCount) && other.FirstGID < ts.FirstGID+uint32(ts.TileCount) {
+				problems = append(problems, fmt.Sprintf("GIDs of tilesets %q and %q overlap", ts.Name, other.Name))
+			}
+		}
+	}
+
+	ids := map[uint32]bool{}
+	m.walkLayers(nil, func(g *ObjectGroup) {
+		for _, o := range g.Objects {
+			if o.ID == 0 {
+				continue
+			}
+			if ids[o.ID] {
+				problems = append(problems, fmt.Sprintf("object ID %d of %q is used twice", o.ID, g.Name))
+			}
+			ids[o.ID] = true
+		}
+	})
+
+	for _, p := range problems {
+		if err := m.loader.inconsistent(fmt.Errorf("%w: %s", ErrInconsistentData, p)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkDocument checks the attributes and elements of an XML document
+// decoded into a value of type T against the ones known, in strict and
+// tolerant modes. It returns a reader of the document to decode, which is r
+// in the default mode. JSON documents have their keys checked by checkJSON,
+// others are left to the decoder.
+func checkDocument[T any](l *loader, r io.Reader) (io.Reader, error) {
+	if l.parseMode() == ParseDefault {
+		return r, nil
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '<' {
+		if jt, ok := jsonDocuments[reflect.TypeFor[T]()]; ok && len(trimmed) > 0 && trimmed[0] == '{' {
+			if err := l.checkJSON(data, jt); err != nil {
+				return nil, err
+			}
+		}
+		return bytes.NewReader(data), nil
+	}
+
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var stack []*xmlSchema
+	var names []string
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			// Syntax errors are reported by the decoder
+			break
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			var s *xmlSchema
+			switch {
+			case len(stack) == 0:
+				s = schemaOf(reflect.TypeFor[T]())
+			case stack[len(stack)-1] == nil || stack[len(stack)-1].any:
+				// Inside an unknown element or raw inner XML
+			default:
+				parent := stack[len(stack)-1]
+				if s = parent.children[tok.Name.Local]; s == nil {
+					err := fmt.Errorf("%w: element <%s> in <%s>", ErrUnknownXML, tok.Name.Local, names[len(names)-1])
+					if err := l.inconsistent(err); err != nil {
+						return nil, err
+					}
+				}
+			}
+			if s != nil {
+				for _, attr := range tok.Attr {
+					if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" || s.attrs[attr.Name.Local] {
+						continue
+					}
+					err := fmt.Errorf("%w: attribute %q of <%s>", ErrUnknownXML, attr.Name.Local, tok.Name.Local)
+					if err := l.inconsistent(err); err != nil {
+						return nil, err
+					}
+				}
+			}
+			stack = append(stack, s)
+			names = append(names, tok.Name.Local)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+				names = names[:len(names)-1]
+			}
+		}
+	}
+	return bytes.NewReader(data), nil
+}
+
+// xmlSchema lists the attributes and child elements of an element, as
+// decoded by encoding/xml from the tags of the struct it is decoded into
+type xmlSchema struct {
+	attrs    map[string]bool
+	children map[string]*xmlSchema
+	// Whether any child is accepted, as kept in inner XML
+	any bool
+}
+
+var (
+	xmlSchemasMu sync.Mutex
+	xmlSchemas   = map[reflect.Type]*xmlSchema{}
+
+	// Types decoded into another type by their UnmarshalXML method
+	xmlAliases = map[reflect.Type]reflect.Type{
+		reflect.TypeFor[Layer](): reflect.TypeFor[aliasLayer](),
+	}
+
+	// Attributes and elements of the TMX format skipped when decoding, by
+	// type they are decoded into
+	xmlIgnored = map[reflect.Type][]string{
+		reflect.TypeFor[Map]():         {"nextlayerid", "nextobjectid", "infinite", "compressionlevel", "editorsettings"},
+		reflect.TypeFor[Tileset]():     {"objectalignment", "backgroundcolor", "editorsettings"},
+		reflect.TypeFor[aliasLayer]():  {"x", "y", "width", "height", "locked"},
+		reflect.TypeFor[ObjectGroup](): {"x", "y", "width", "height", "color", "locked"},
+		reflect.TypeFor[ImageLayer]():  {"x", "y", "locked"},
+		reflect.TypeFor[Group]():       {"locked"},
+		reflect.TypeFor[Object]():      {"point"},
+		reflect.TypeFor[Image]():       {"id"},
+		reflect.TypeFor[WangSet]():     {"properties", "wangcornercolor", "wangedgecolor"},
+		reflect.TypeFor[WangColor]():   {"properties"},
+		reflect.TypeFor[WangTile]():    {"hflip", "vflip", "dflip"},
+	}
+)
+
+func newXMLSchema() *xmlSchema {
+	return &xmlSchema{attrs: map[string]bool{}, children: map[string]*xmlSchema{}}
+}
+
+// schemaOf returns the schema of the elements decoded into values of type t
+func schemaOf(t reflect.Type) *xmlSchema {
+	xmlSchemasMu.Lock()
+	defer xmlSchemasMu.Unlock()
+	return buildXMLSchema(t)
+}
+
+func buildXMLSchema(t reflect.Type) *xmlSchema {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		t = t.Elem()
+	}
+	if alias, ok := xmlAliases[t]; ok {
+		t = alias
+	}
+	if s, ok := xmlSchemas[t]; ok {
+		return s
+	}
+
+	s := newXMLSchema()
+	xmlSchemas[t] = s
+	switch {
+	case t == reflect.TypeFor[Property]():
+		// Decoded by hand, see Property.UnmarshalXML
+		for _, name := range []string{"name", "type", "value", "propertytype"} {
+			s.attrs[name] = true
+		}
+		properties := newXMLSchema()
+		properties.children["property"] = s
+		s.children["properties"] = properties
+	case t.Kind() == reflect.Struct:
+		s.addFields(t)
+	}
+	for _, name := range xmlIgnored[t] {
+		s.attrs[name] = true
+		if name == "properties" {
+			// Properties not decoded are still checked
+			s.children[name] = buildXMLSchema(reflect.TypeFor[Property]()).children[name]
+			continue
+		}
+		s.children[name] = &xmlSchema{any: true}
+	}
+	return s
+}
+
+// addFields adds the attributes and elements of the fields of struct type t
+func (s *xmlSchema) addFields(t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("xml")
+		if tag == "-" || !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		if f.Anonymous && tag == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				s.addFields(ft)
+			}
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+		switch {
+		case strings.Contains(opts, "attr"):
+			if name == "" {
+				name = f.Name
+			}
+			s.attrs[name] = true
+			continue
+		case strings.Contains(opts, "innerxml") || strings.Contains(opts, "any"):
+			s.any = true
+			continue
+		case strings.Contains(opts, "chardata") || strings.Contains(opts, "comment"):
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		path := strings.Split(name, ">")
+		parent := s
+		for _, p := range path[:len(path)-1] {
+			child := parent.children[p]
+			if child == nil {
+				child = newXMLSchema()
+				parent.children[p] = child
+			}
+			parent = child
+		}
+		parent.children[path[len(path)-1]] = buildXMLSchema(f.Type)
+	}
+}
+
+var (
+	// JSON types decoded in place of the types of TMX, TSX and TX documents
+	jsonDocuments = map[reflect.Type]reflect.Type{
+		reflect.TypeFor[Map]():      reflect.TypeFor[jsonMap](),
+		reflect.TypeFor[Tileset]():  reflect.TypeFor[jsonTileset](),
+		reflect.TypeFor[Template](): reflect.TypeFor[jsonTemplate](),
+	}
+
+	// Keys of the JSON format skipped when decoding, by type they are
+	// decoded into
+	jsonIgnored = map[reflect.Type][]string{
+		reflect.TypeFor[jsonMap]():       {"infinite", "nextlayerid", "compressionlevel", "editorsettings"},
+		reflect.TypeFor[jsonTileset]():   {"version", "tiledversion", "objectalignment", "backgroundcolor", "editorsettings"},
+		reflect.TypeFor[jsonLayer]():     {"locked", "startx", "starty", "chunks"},
+		reflect.TypeFor[jsonObject]():    {"point"},
+		reflect.TypeFor[jsonWangSet]():   {"properties"},
+		reflect.TypeFor[jsonWangColor](): {"properties"},
+	}
+)
+
+// jsonSchema lists the keys of a JSON object, as decoded by encoding/json
+// from the tags of the struct it is decoded into. Values of keys with a nil
+// schema aren't checked.
+type jsonSchema map[string]jsonSchema
+
+var (
+	jsonSchemasMu sync.Mutex
+	jsonSchemas   = map[reflect.Type]jsonSchema{}
+)
+
+func jsonSchemaOf(t reflect.Type) jsonSchema {
+	jsonSchemasMu.Lock()
+	defer jsonSchemasMu.Unlock()
+	return buildJSONSchema(t)
+}
+
+func buildJSONSchema(t reflect.Type) jsonSchema {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice && t != reflect.TypeFor[json.RawMessage]() {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	if s, ok := jsonSchemas[t]; ok {
+		return s
+	}
+
+	s := jsonSchema{}
+	jsonSchemas[t] = s
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s[name] = buildJSONSchema(f.Type)
+	}
+	for _, name := range jsonIgnored[t] {
+		s[name] = nil
+	}
+	return s
+}
+
+// checkJSON checks the keys of a JSON document decoded into a value of type
+// t against the ones known. Syntax errors are reported by the decoder.
+func (l *loader) checkJSON(data []byte, t reflect.Type) error {
+	var doc any
+	if json.Unmarshal(data, &doc) != nil {
+		return nil
+	}
+	return l.checkJSONValue(doc, jsonSchemaOf(t), "")
+}
+
+func (l *loader) checkJSONValue(v any, s jsonSchema, path string) error {
+	switch v := v.(type) {
+	case []any:
+		for i, item := range v {
+			if err := l.checkJSONValue(item, s, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		if s == nil {
+			return nil
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			child, ok := s[key]
+			if !ok {
+				err := fmt.Errorf("%w: key %q", ErrUnknownJSON, key)
+				if path != "" {
+					err = fmt.Errorf("%w in %s", err, path)
+				}
+				if err := l.inconsistent(err); err != nil {
+					return err
+				}
+				continue
+			}
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			if err := l.checkJSONValue(v[key], child, childPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
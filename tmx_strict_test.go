@@ -0,0 +1,228 @@
+package tiled
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseModeUnknownXML(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16" nextlayerid="2" nextobjectid="1" infinite="0" shiny="1">
+<layer id="1" name="Ground" width="1" height="1">
+<properties><property name="cost" type="int" value="2"/></properties>
+<data encoding="csv">0</data>
+</layer>
+<sparkles/>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, m.Layers[0].Properties.GetInt("cost"))
+
+	_, err = LoadReader(".", bytes.NewBufferString(tmx), WithParseMode(ParseStrict))
+	assert.ErrorIs(t, err, ErrUnknownXML)
+	assert.ErrorContains(t, err, `attribute "shiny" of <map>`)
+
+	var warnings []error
+	m, err = LoadReader(".", bytes.NewBufferString(tmx), WithParseMode(ParseTolerant),
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }))
+	assert.NoError(t, err)
+	assert.Len(t, m.Layers, 1)
+	if assert.Len(t, warnings, 2) {
+		assert.ErrorIs(t, warnings[0], ErrUnknownXML)
+		assert.ErrorContains(t, warnings[1], "element <sparkles> in <map>")
+	}
+
+	_, err = LoadFile(GetAssetsDirectory()+"/test.tmx", WithParseMode(ParseStrict))
+	assert.NoError(t, err)
+}
+
+func TestParseModeInconsistentData(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="1" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="a" tilewidth="16" tileheight="16" tilecount="4" columns="4"/>
+<tileset firstgid="3" name="b" tilewidth="16" tileheight="16" tilecount="4" columns="4"/>
+<objectgroup id="1" name="Spawns">
+<object id="1" x="0" y="0"/>
+<object id="1" x="16" y="0"/>
+</objectgroup>
+</map>`
+
+	var warnings []error
+	_, err := LoadReader(".", bytes.NewBufferString(tmx), WithWarningHandler(func(err error) { warnings = append(warnings, err) }))
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 2)
+
+	_, err = LoadReader(".", bytes.NewBufferString(tmx), WithParseMode(ParseStrict))
+	assert.ErrorIs(t, err, ErrInconsistentData)
+	assert.ErrorContains(t, err, `GIDs of tilesets "a" and "b" overlap`)
+}
+
+func TestParseModeTolerant(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="hexagonal" width="2" height="2" tilewidth="16" tileheight="16" staggeraxis="z">
+<tileset firstgid="1" name="tiles" tilewidth="16" tileheight="16" tilecount="4" columns="4"/>
+<layer id="1" name="Short" width="2" height="2"><data encoding="csv">1,2,3</data></layer>
+<layer id="2" name="Full" width="2" height="2"><data encoding="csv">1,1,1,1</data></layer>
+<layer id="3" name="Missing" width="2" height="2"/>
+<layer id="4" name="Unknown" width="2" height="2"><data encoding="morse">.-</data></layer>
+<objectgroup id="5" name="Objects">
+<object id="1" gid="1" x="0" y="0"/>
+</objectgroup>
+</map>`
+
+	_, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.ErrorIs(t, err, ErrInvalidStagger)
+
+	var warnings []error
+	m, err := LoadReader(".", bytes.NewBufferString(tmx), WithParseMode(ParseTolerant),
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }))
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 4)
+	assert.Equal(t, AxisY, m.StaggerAxis)
+
+	short := m.Layers[0]
+	if assert.Len(t, short.Tiles, 4) {
+		assert.Equal(t, uint32(2), short.Tiles[2].ID)
+		assert.True(t, short.Tiles[3].IsNil())
+	}
+	assert.True(t, m.Layers[2].IsEmpty())
+	assert.Len(t, m.Layers[2].Tiles, 4)
+	assert.True(t, m.Layers[3].IsEmpty())
+}
+
+func TestParseModeTolerantInvalidGIDs(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="1" tilewidth="16" tileheight="16">
+<tileset firstgid="5" name="tiles" tilewidth="16" tileheight="16" tilecount="4" columns="4"/>
+<layer id="1" name="Ground" width="2" height="1"><data encoding="csv">5,1</data></layer>
+<objectgroup id="2" name="Objects">
+<object id="1" gid="2" x="0" y="0"/>
+</objectgroup>
+</map>`
+
+	_, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.ErrorIs(t, err, ErrInvalidTileGID)
+
+	var warnings []error
+	m, err := LoadReader(".", bytes.NewBufferString(tmx), WithParseMode(ParseTolerant),
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }))
+	assert.NoError(t, err)
+	assert.Len(t, warnings, 2)
+	assert.False(t, m.Layers[0].Tiles[0].IsNil())
+	assert.True(t, m.Layers[0].Tiles[1].IsNil())
+	assert.Equal(t, uint32(0), m.ObjectGroups[0].Objects[0].GID)
+}
+
+// tmxFormat lists the elements of the TMX format with their attributes, by
+// path from the root element, as documented for Tiled 1.10
+var tmxFormat = map[string][]string{
+	"map":                              {"version", "tiledversion", "class", "orientation", "renderorder", "compressionlevel", "width", "height", "tilewidth", "tileheight", "hexsidelength", "staggeraxis", "staggerindex", "parallaxoriginx", "parallaxoriginy", "backgroundcolor", "nextlayerid", "nextobjectid", "infinite"},
+	"map/editorsettings":               {},
+	"map/properties/property":          {"name", "type", "propertytype", "value"},
+	"map/tileset":                      {"firstgid", "source", "name", "class", "tilewidth", "tileheight", "spacing", "margin", "tilecount", "columns", "objectalignment", "tilerendersize", "fillmode", "backgroundcolor"},
+	"map/tileset/image":                {"format", "id", "source", "trans", "width", "height"},
+	"map/tileset/image/data":           {"encoding", "compression"},
+	"map/tileset/tileoffset":           {"x", "y"},
+	"map/tileset/grid":                 {"orientation", "width", "height"},
+	"map/tileset/properties/property":  {"name", "type", "propertytype", "value"},
+	"map/tileset/terraintypes/terrain": {"name", "tile"},
+	"map/tileset/terraintypes/terrain/properties/property":       {"name", "type", "propertytype", "value"},
+	"map/tileset/transformations":                                {"hflip", "vflip", "rotate", "preferuntransformed"},
+	"map/tileset/tile":                                           {"id", "type", "class", "terrain", "probability", "x", "y", "width", "height"},
+	"map/tileset/tile/properties/property":                       {"name", "type", "propertytype", "value"},
+	"map/tileset/tile/image":                                     {"format", "source", "trans", "width", "height"},
+	"map/tileset/tile/objectgroup/object":                        {"id", "name", "x", "y", "width", "height"},
+	"map/tileset/tile/animation/frame":                           {"tileid", "duration"},
+	"map/tileset/wangsets/wangset":                               {"name", "class", "tile", "type"},
+	"map/tileset/wangsets/wangset/properties/property":           {"name", "type", "propertytype", "value"},
+	"map/tileset/wangsets/wangset/wangcolor":                     {"name", "class", "color", "tile", "probability"},
+	"map/tileset/wangsets/wangset/wangcolor/properties/property": {"name", "type", "propertytype", "value"},
+	"map/tileset/wangsets/wangset/wangtile":                      {"tileid", "wangid", "hflip", "vflip", "dflip"},
+	"map/layer":                                                  {"id", "name", "class", "x", "y", "width", "height", "opacity", "visible", "locked", "tintcolor", "offsetx", "offsety", "parallaxx", "parallaxy"},
+	"map/layer/properties/property":                              {"name", "type", "propertytype", "value"},
+	"map/layer/data":                                             {"encoding", "compression"},
+	"map/objectgroup":                                            {"id", "name", "class", "color", "x", "y", "width", "height", "opacity", "visible", "locked", "tintcolor", "offsetx", "offsety", "parallaxx", "parallaxy", "draworder"},
+	"map/objectgroup/properties/property":                        {"name", "type", "propertytype", "value"},
+	"map/objectgroup/object":                                     {"id", "name", "type", "class", "x", "y", "width", "height", "rotation", "gid", "visible", "template"},
+	"map/objectgroup/object/properties/property":                 {"name", "type", "propertytype", "value"},
+	"map/objectgroup/object/ellipse":                             {},
+	"map/objectgroup/object/point":                               {},
+	"map/objectgroup/object/polygon":                             {"points"},
+	"map/objectgroup/object/polyline":                            {"points"},
+	"map/objectgroup/object/text":                                {"fontfamily", "pixelsize", "wrap", "color", "bold", "italic", "underline", "strikeout", "kerning", "halign", "valign"},
+	"map/imagelayer":                                             {"id", "name", "class", "offsetx", "offsety", "parallaxx", "parallaxy", "x", "y", "opacity", "visible", "locked", "tintcolor", "repeatx", "repeaty"},
+	"map/imagelayer/image":                                       {"format", "source", "trans", "width", "height"},
+	"map/group":                                                  {"id", "name", "class", "offsetx", "offsety", "parallaxx", "parallaxy", "opacity", "visible", "locked", "tintcolor"},
+	"map/group/properties/property":                              {"name", "type", "propertytype", "value"},
+	"map/group/layer":                                            {"id", "name", "locked"},
+	"map/group/objectgroup/object/point":                         {},
+	"map/group/imagelayer/image":                                 {"source"},
+	"map/group/group/group":                                      {"id", "name"},
+}
+
+func TestXMLSchemaCoversTMX(t *testing.T) {
+	for path, attrs := range tmxFormat {
+		names := strings.Split(path, "/")
+		s := schemaOf(reflect.TypeFor[Map]())
+		for _, name := range names[1:] {
+			if s = s.children[name]; s == nil {
+				break
+			}
+		}
+		if !assert.NotNil(t, s, "element %s", path) {
+			continue
+		}
+		for _, attr := range attrs {
+			assert.True(t, s.attrs[attr], "attribute %q of %s", attr, path)
+		}
+	}
+
+	template := schemaOf(reflect.TypeFor[Template]())
+	assert.NotNil(t, template.children["tileset"])
+	assert.NotNil(t, template.children["object"].children["point"])
+}
+
+func TestParseModeStrictPoint(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" tiledversion="1.10.2" orientation="orthogonal" renderorder="right-down" width="1" height="1" tilewidth="16" tileheight="16" infinite="0" nextlayerid="2" nextobjectid="2">
+<objectgroup id="1" name="Spawns">
+<object id="1" name="Player" x="8" y="8"><point/></object>
+</objectgroup>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx), WithParseMode(ParseStrict))
+	assert.NoError(t, err)
+	assert.Equal(t, "Player", m.ObjectGroups[0].Objects[0].Name)
+}
+
+func TestParseModeUnknownJSON(t *testing.T) {
+	_, err := LoadJSONReader(GetAssetsDirectory(), bytes.NewBufferString(jsonTestMap), WithParseMode(ParseStrict))
+	assert.NoError(t, err)
+
+	tmj := `{"type": "map", "version": "1.10", "orientation": "orthogonal", "width": 1, "height": 1,
+  "tilewidth": 16, "tileheight": 16, "infinite": false, "nextlayerid": 2, "tilesets": [],
+  "layers": [{"type": "objectgroup", "id": 1, "name": "Spawns", "objects": [
+    {"id": 1, "name": "Player", "x": 8, "y": 8, "point": true, "sparkles": 3}
+  ]}]}`
+
+	_, err = LoadJSONReader(".", bytes.NewBufferString(tmj))
+	assert.NoError(t, err)
+
+	_, err = LoadJSONReader(".", bytes.NewBufferString(tmj), WithParseMode(ParseStrict))
+	assert.ErrorIs(t, err, ErrUnknownJSON)
+	assert.ErrorContains(t, err, `key "sparkles" in layers[0].objects[0]`)
+
+	var warnings []error
+	m, err := LoadJSONReader(".", bytes.NewBufferString(tmj), WithParseMode(ParseTolerant),
+		WithWarningHandler(func(err error) { warnings = append(warnings, err) }))
+	assert.NoError(t, err)
+	assert.Len(t, m.ObjectGroups[0].Objects, 1)
+	if assert.Len(t, warnings, 1) {
+		assert.ErrorIs(t, warnings[0], ErrUnknownJSON)
+	}
+}
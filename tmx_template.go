@@ -22,8 +22,10 @@ SOFTWARE.
 
 package tiled
 
-// Template is used for custom properties.
+// Template is an object template loaded from a .tx file.
 type Template struct {
+	// Tileset referenced by the template object, if it is a tile object.
 	Tileset *Tileset `xml:"tileset"`
-	Object  *Object  `xml:"object"`
+	// The template object.
+	Object *Object `xml:"object"`
 }
@@ -1,9 +1,36 @@
 package tiled
 
 import (
+	"encoding/xml"
 	"errors"
+	"fmt"
 	"image"
 	"path/filepath"
+	"time"
+)
+
+// ErrTileNotFound error is returned for tile IDs without tile data in a tileset
+var ErrTileNotFound = errors.New("tiled: tile not found in tileset")
+
+// TileRenderSize is the size tiles of a tileset are rendered at on tile layers
+type TileRenderSize string
+
+const (
+	// TileRenderSizeTile renders tiles at the tile size of their tileset
+	TileRenderSizeTile TileRenderSize = "tile"
+	// TileRenderSizeGrid renders tiles at the tile size of the map
+	TileRenderSizeGrid TileRenderSize = "grid"
+)
+
+// FillMode is how tiles rendered at the map tile size are scaled
+type FillMode string
+
+const (
+	// FillModeStretch stretches tiles to the map tile size
+	FillModeStretch FillMode = "stretch"
+	// FillModePreserveAspectFit scales tiles to fit the map tile size,
+	// keeping their aspect ratio
+	FillModePreserveAspectFit FillMode = "preserve-aspect-fit"
 )
 
 // Tileset is collection of tiles
@@ -40,8 +67,16 @@ type Tileset struct {
 	TileCount int `xml:"tilecount,attr"`
 	// The number of tile columns in the tileset. For image collection tilesets it is editable and is used when displaying the tileset. (since 0.15)
 	Columns int `xml:"columns,attr"`
+	// The size to use when rendering tiles from this tileset on a tile layer, TileRenderSizeTile (default) or TileRenderSizeGrid. (since 1.9)
+	TileRenderSize TileRenderSize `xml:"tilerendersize,attr"`
+	// The fill mode to use when rendering tiles from this tileset at the grid size, FillModeStretch (default) or FillModePreserveAspectFit. (since 1.9)
+	FillMode FillMode `xml:"fillmode,attr"`
 	// Offset in pixels, to be applied when drawing a tile from the related tileset. When not present, no offset is applied.
 	TileOffset *TilesetTileOffset `xml:"tileoffset"`
+	// Orientation and size of the grid of the tiles in the tile collision editor and Wang set views (since 1.0)
+	Grid *TilesetGrid `xml:"grid"`
+	// Transformations allowed when placing tiles with the terrain and Wang tools. When not present, none is allowed. (since 1.5)
+	Transformations *TilesetTransformations `xml:"transformations"`
 	// Custom properties
 	Properties Properties `xml:"properties>property"`
 	// Embedded image
@@ -56,6 +91,15 @@ type Tileset struct {
 	tiles map[uint32]*TilesetTile
 }
 
+// UnmarshalXML decodes a single XML element beginning with the given start element.
+func (ts *Tileset) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	item := (*aliasTileset)(ts)
+	if err := d.DecodeElement(item, &start); err != nil {
+		return startElementError(start, item, err)
+	}
+	return nil
+}
+
 // BaseDir returns the base directory.
 func (ts *Tileset) BaseDir() string {
 	return ts.baseDir
@@ -79,6 +123,51 @@ type TilesetTileOffset struct {
 	Y int `xml:"y,attr"`
 }
 
+// TilesetGrid is the grid of the tiles of a tileset, used for isometric tiles
+type TilesetGrid struct {
+	// Orientation of the grid, "orthogonal" (default) or "isometric"
+	Orientation string `xml:"orientation,attr"`
+	// Width of a grid cell
+	Width int `xml:"width,attr"`
+	// Height of a grid cell
+	Height int `xml:"height,attr"`
+}
+
+// TilesetTransformations lists the transformations the terrain and Wang tools
+// may apply to the tiles of a tileset
+type TilesetTransformations struct {
+	// Whether tiles can be flipped horizontally
+	HFlip bool `xml:"hflip,attr"`
+	// Whether tiles can be flipped vertically
+	VFlip bool `xml:"vflip,attr"`
+	// Whether tiles can be rotated in 90-degree increments
+	Rotate bool `xml:"rotate,attr"`
+	// Whether untransformed tiles are preferred over transformed ones
+	PreferUntransformed bool `xml:"preferuntransformed,attr"`
+}
+
+// AllowsTransform reports whether a tile of the tileset can be placed with
+// the given flips, as they are set on a LayerTile. Rotations are made of a
+// diagonal flip and a horizontal or vertical flip, a half turn of both
+// flips. Tiles can always be placed untransformed.
+func (ts *Tileset) AllowsTransform(horizontal, vertical, diagonal bool) bool {
+	t := ts.Transformations
+	if t == nil {
+		return !horizontal && !vertical && !diagonal
+	}
+	if t.Rotate && (t.HFlip || t.VFlip) {
+		return true
+	}
+	if diagonal {
+		// Quarter turns flip one axis, mirrored quarter turns both or none
+		return t.Rotate && horizontal != vertical
+	}
+	if horizontal && vertical {
+		return t.Rotate || t.HFlip && t.VFlip
+	}
+	return (!horizontal || t.HFlip) && (!vertical || t.VFlip)
+}
+
 // Terrain type
 type Terrain struct {
 	// The name of the terrain type.
@@ -126,9 +215,34 @@ type TilesetTile struct {
 // AnimationFrame is single frame of animation
 type AnimationFrame struct {
 	// The local ID of a tile within the parent tileset.
-	TileID uint32 `xml:"tileid,attr"`
+	TileID uint32 `xml:"tileid,attr" json:"tileid"`
 	// How long (in milliseconds) this frame should be displayed before advancing to the next frame.
-	Duration uint32 `xml:"duration,attr"`
+	Duration uint32 `xml:"duration,attr" json:"duration"`
+}
+
+// Length returns how long the frame is displayed
+func (f *AnimationFrame) Length() time.Duration {
+	return time.Duration(f.Duration) * time.Millisecond
+}
+
+// AnimationDuration returns the time taken by one loop of the tile animation,
+// 0 for tiles without animation
+func (t *TilesetTile) AnimationDuration() time.Duration {
+	var total time.Duration
+	for _, f := range t.Animation {
+		total += f.Length()
+	}
+	return total
+}
+
+// FrameAt returns the animation frame displayed after elapsed time, the
+// animation looping, or nil for tiles without animation. See Animator to
+// animate the tiles of layers.
+func (t *TilesetTile) FrameAt(elapsed time.Duration) *AnimationFrame {
+	if len(t.Animation) == 0 {
+		return nil
+	}
+	return activeFrame(t.Animation, elapsed)
 }
 
 // GetTileRect returns a rectangle that contains the tile in the tileset.Image
@@ -162,6 +276,15 @@ func (ts *Tileset) cacheTiles() {
 	}
 }
 
+// Tile returns the TilesetTile with the given ID, or nil if the tile has no
+// specific data. Unlike GetTilesetTile, a missing tile is not an error.
+func (ts *Tileset) Tile(tileID uint32) *TilesetTile {
+	if ts.tiles == nil {
+		ts.cacheTiles()
+	}
+	return ts.tiles[tileID]
+}
+
 // GetTilesetTile returns TilesetTile by tileID
 func (ts *Tileset) GetTilesetTile(tileID uint32) (*TilesetTile, error) {
 	if tileID == 0 {
@@ -175,8 +298,22 @@ func (ts *Tileset) GetTilesetTile(tileID uint32) (*TilesetTile, error) {
 	tilesetTile, ok := ts.tiles[tileID]
 
 	if !ok {
-		return nil, errors.New("no tilesetTile matches the given Id")
+		return nil, fmt.Errorf("%w: tile %d of tileset %q", ErrTileNotFound, tileID, ts.Name)
 	}
 
 	return tilesetTile, nil
 }
+
+// WangIDForTile returns the first Wang set of the tileset holding the tile
+// with the given ID, along with the Wang ID of the tile in that set.
+func (ts *Tileset) WangIDForTile(tileID uint32) (*WangSet, WangID, error) {
+	for _, w := range ts.WangSets {
+		for _, t := range w.WangTiles {
+			if t.TileID == tileID {
+				id, err := w.wangID(t)
+				return w, id, err
+			}
+		}
+	}
+	return nil, WangID{}, fmt.Errorf("%w: tile %d of tileset %q", ErrWangTileNotFound, tileID, ts.Name)
+}
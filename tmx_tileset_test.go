@@ -23,7 +23,10 @@ SOFTWARE.
 package tiled
 
 import (
+	"bytes"
+	"encoding/base64"
 	"image"
+	"image/png"
 	"os"
 	"path/filepath"
 	"testing"
@@ -259,6 +262,8 @@ var testLoadTilesetTileFile = &TilesetTile{
 			OffsetX:    0,
 			OffsetY:    0,
 			Opacity:    1,
+			ParallaxX:  1,
+			ParallaxY:  1,
 			Properties: nil,
 			Visible:    true,
 		},
@@ -288,3 +293,139 @@ func TestLoadTile(t *testing.T) {
 	tile := tsx.Tiles[0]
 	assert.Equal(t, testLoadTilesetTileFile, tile)
 }
+
+func TestTilesetTile(t *testing.T) {
+	ts := &Tileset{Name: "props", Tiles: []*TilesetTile{{ID: 0}, nil, {ID: 7}}}
+	assert.Same(t, ts.Tiles[0], ts.Tile(0))
+	assert.Same(t, ts.Tiles[2], ts.Tile(7))
+	assert.Nil(t, ts.Tile(3))
+}
+
+func TestEmbeddedImage(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.Pix[3] = 0xff
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, img))
+	data := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="1" tileheight="1">
+<tileset firstgid="1" name="embedded" tilewidth="1" tileheight="1" tilecount="2" columns="2">
+<image format="png" width="2" height="1">
+<data encoding="base64">
+` + data + `
+</data>
+</image>
+</tileset>
+<layer id="1" name="Ground" width="1" height="1"><data encoding="csv">2</data></layer>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	ts := m.Tilesets[0]
+	assert.True(t, ts.Image.Embedded())
+	assert.Equal(t, "png", ts.Image.Format)
+	decoded, err := ts.Image.EmbeddedData()
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), decoded)
+
+	var out bytes.Buffer
+	assert.NoError(t, newTMXEncoder(&out, ".").encodeMap(m))
+	m, err = LoadReader(".", &out)
+	assert.NoError(t, err)
+	decoded, err = m.Tilesets[0].Image.EmbeddedData()
+	assert.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), decoded)
+
+	assert.False(t, (&Image{Source: "tiles.png"}).Embedded())
+	_, err = (&Image{Data: &Data{Encoding: "csv", RawData: []byte("1,2")}}).EmbeddedData()
+	assert.ErrorIs(t, err, ErrUnknownImageEncoding)
+}
+
+func TestTilesetGridAndTransformations(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="walls" tilewidth="16" tileheight="32" tilecount="0" columns="0">
+<tileoffset x="0" y="16"/>
+<grid orientation="isometric" width="32" height="16"/>
+<transformations hflip="1" vflip="0" rotate="0" preferuntransformed="1"/>
+</tileset>
+<layer id="1" name="Ground" width="1" height="1"><data encoding="csv">0</data></layer>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	ts := m.Tilesets[0]
+	assert.Equal(t, &TilesetTileOffset{X: 0, Y: 16}, ts.TileOffset)
+	assert.Equal(t, &TilesetGrid{Orientation: "isometric", Width: 32, Height: 16}, ts.Grid)
+	assert.Equal(t, &TilesetTransformations{HFlip: true, PreferUntransformed: true}, ts.Transformations)
+
+	var out bytes.Buffer
+	assert.NoError(t, newTMXEncoder(&out, ".").encodeMap(m))
+	assert.Contains(t, out.String(), `<grid orientation="isometric" width="32" height="16">`)
+	assert.Contains(t, out.String(), `<transformations hflip="1" vflip="0" rotate="0" preferuntransformed="1">`)
+
+	m, err = LoadJSONReader(".", bytes.NewBufferString(`{
+  "orientation": "orthogonal", "width": 1, "height": 1, "tilewidth": 16, "tileheight": 16, "layers": [],
+  "tilesets": [{"firstgid": 1, "name": "walls", "tilewidth": 16, "tileheight": 32,
+    "grid": {"orientation": "isometric", "width": 32, "height": 16},
+    "transformations": {"hflip": false, "vflip": true, "rotate": true, "preferuntransformed": false}}]
+}`))
+	assert.NoError(t, err)
+	ts = m.Tilesets[0]
+	assert.Equal(t, &TilesetGrid{Orientation: "isometric", Width: 32, Height: 16}, ts.Grid)
+	assert.Equal(t, &TilesetTransformations{VFlip: true, Rotate: true}, ts.Transformations)
+}
+
+func TestTilesetAllowsTransform(t *testing.T) {
+	ts := &Tileset{}
+	assert.True(t, ts.AllowsTransform(false, false, false))
+	assert.False(t, ts.AllowsTransform(true, false, false))
+
+	ts.Transformations = &TilesetTransformations{HFlip: true}
+	assert.True(t, ts.AllowsTransform(true, false, false))
+	assert.False(t, ts.AllowsTransform(false, true, false))
+	assert.False(t, ts.AllowsTransform(true, true, false))
+
+	ts.Transformations = &TilesetTransformations{Rotate: true}
+	assert.True(t, ts.AllowsTransform(true, false, true))
+	assert.True(t, ts.AllowsTransform(true, true, false))
+	assert.True(t, ts.AllowsTransform(false, true, true))
+	assert.False(t, ts.AllowsTransform(true, false, false))
+	assert.False(t, ts.AllowsTransform(false, false, true))
+
+	ts.Transformations = &TilesetTransformations{HFlip: true, VFlip: true}
+	assert.True(t, ts.AllowsTransform(true, true, false))
+	assert.False(t, ts.AllowsTransform(true, false, true))
+
+	ts.Transformations = &TilesetTransformations{VFlip: true, Rotate: true}
+	assert.True(t, ts.AllowsTransform(false, false, true))
+}
+
+func TestTilesetRenderSize(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<tileset firstgid="1" name="large" tilewidth="64" tileheight="32" tilecount="0" columns="0" tilerendersize="grid" fillmode="preserve-aspect-fit"/>
+<tileset firstgid="2" name="native" tilewidth="16" tileheight="16" tilecount="0" columns="0"/>
+<layer id="1" name="Ground" width="1" height="1"><data encoding="csv">0</data></layer>
+</map>`
+
+	m, err := LoadReader(".", bytes.NewBufferString(tmx))
+	assert.NoError(t, err)
+	assert.Equal(t, TileRenderSizeGrid, m.Tilesets[0].TileRenderSize)
+	assert.Equal(t, FillModePreserveAspectFit, m.Tilesets[0].FillMode)
+	assert.Equal(t, TileRenderSize(""), m.Tilesets[1].TileRenderSize)
+
+	var out bytes.Buffer
+	assert.NoError(t, newTMXEncoder(&out, ".").encodeMap(m))
+	assert.Contains(t, out.String(), `tilerendersize="grid" fillmode="preserve-aspect-fit"`)
+
+	m, err = LoadJSONReader(".", bytes.NewBufferString(`{
+  "orientation": "orthogonal", "width": 1, "height": 1, "tilewidth": 16, "tileheight": 16, "layers": [],
+  "tilesets": [{"firstgid": 1, "name": "large", "tilewidth": 64, "tileheight": 32,
+    "tilerendersize": "grid", "fillmode": "stretch"}]
+}`))
+	assert.NoError(t, err)
+	assert.Equal(t, TileRenderSizeGrid, m.Tilesets[0].TileRenderSize)
+	assert.Equal(t, FillModeStretch, m.Tilesets[0].FillMode)
+}
@@ -0,0 +1,107 @@
+package tiled
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrUnknownTransform error is returned when loading a map with a transform
+// name that was not registered
+var ErrUnknownTransform = errors.New("tiled: unknown transform")
+
+// Transform modifies a map once it is loaded, as a step of an asset pipeline
+type Transform func(m *Map) error
+
+var (
+	transformsMu sync.RWMutex
+	transforms   = map[string]Transform{
+		"without-devonly": func(m *Map) error {
+			m.Exclude(devOnlyProperties...)
+			return nil
+		},
+	}
+)
+
+// RegisterTransform registers a transform under the given name, so it can be
+// applied with WithTransforms. It is meant to be called from init functions
+// of packages sharing pipeline steps, and panics if the name is already
+// registered or t is nil.
+func RegisterTransform(name string, t Transform) {
+	transformsMu.Lock()
+	defer transformsMu.Unlock()
+	if t == nil {
+		panic("tiled: RegisterTransform transform is nil")
+	}
+	if _, dup := transforms[name]; dup {
+		panic("tiled: RegisterTransform called twice for transform " + name)
+	}
+	transforms[name] = t
+}
+
+// unregisterTransform removes a registered transform, so tests can register
+// theirs without leaking them
+func unregisterTransform(name string) {
+	transformsMu.Lock()
+	defer transformsMu.Unlock()
+	delete(transforms, name)
+}
+
+// Transforms returns the names of the registered transforms
+func Transforms() []string {
+	transformsMu.RLock()
+	defer transformsMu.RUnlock()
+	names := make([]string, 0, len(transforms))
+	for name := range transforms {
+		names = append(names, name)
+	}
+	return names
+}
+
+// WithTransforms returns an option applying the registered transforms with
+// the given names to loaded maps, in order, after any other option. Loading
+// fails with ErrUnknownTransform if a name is not registered.
+func WithTransforms(names ...string) LoaderOption {
+	return func(l *loader) {
+		for _, name := range names {
+			l.transforms = append(l.transforms, namedTransform{name: name})
+		}
+	}
+}
+
+// WithTransform returns an option applying t to loaded maps, in order with
+// the transforms of WithTransforms
+func WithTransform(t Transform) LoaderOption {
+	return func(l *loader) {
+		l.transforms = append(l.transforms, namedTransform{t: t})
+	}
+}
+
+// namedTransform is a transform given directly, or by name resolved when
+// loading so transforms may be registered after options are created
+type namedTransform struct {
+	name string
+	t    Transform
+}
+
+// transform applies the transforms of the loader to a loaded map
+func (l *loader) transform(m *Map) error {
+	for _, nt := range l.transforms {
+		t := nt.t
+		if t == nil {
+			transformsMu.RLock()
+			t = transforms[nt.name]
+			transformsMu.RUnlock()
+			if t == nil {
+				return fmt.Errorf("%w: %s", ErrUnknownTransform, nt.name)
+			}
+		}
+		if err := t(m); err != nil {
+			if nt.name != "" {
+				return fmt.Errorf("tiled: transform %s: %w", nt.name, err)
+			}
+			return err
+		}
+	}
+	return nil
+}
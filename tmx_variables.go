@@ -0,0 +1,60 @@
+package tiled
+
+import "regexp"
+
+// variablePattern matches ${VAR} placeholders
+var variablePattern = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// WithVariables returns an option substituting ${VAR} placeholders in
+// property values with the given variables while the map is loaded,
+// including the properties of external tilesets and templates. Unknown
+// variables are left as is.
+func WithVariables(vars map[string]string) LoaderOption {
+	return func(l *loader) {
+		l.variables = vars
+	}
+}
+
+// WithPathVariables returns an option also substituting variables in the
+// paths of external tilesets, images and templates, so for example asset
+// roots can change per environment. See WithVariables.
+func WithPathVariables() LoaderOption {
+	return func(l *loader) {
+		l.pathVariables = true
+	}
+}
+
+func (l *loader) expand(s string) string {
+	if l == nil || len(l.variables) == 0 {
+		return s
+	}
+	return variablePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if v, ok := l.variables[match[2:len(match)-1]]; ok {
+			return v
+		}
+		return match
+	})
+}
+
+// expandVariables substitutes variables in the property values, including
+// the members of class properties, and the paths of the elements walked
+func (l *loader) expandVariables(walk elementWalker) {
+	walk(func(props *Properties, _ string) {
+		l.expandProperties(props)
+	}, l.expandPath)
+}
+
+func (l *loader) expandProperties(props *Properties) {
+	for _, p := range *props {
+		p.Value = l.expand(p.Value)
+		if len(p.Properties) > 0 {
+			l.expandProperties(&p.Properties)
+		}
+	}
+}
+
+func (l *loader) expandPath(path *string) {
+	if l.pathVariables {
+		*path = l.expand(*path)
+	}
+}
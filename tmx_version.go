@@ -0,0 +1,75 @@
+package tiled
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SupportedVersion is the newest version of the TMX and JSON formats known to
+// the parser
+const SupportedVersion = "1.10"
+
+// ErrUnsupportedVersion error is reported when a map or tileset is in a
+// format newer than SupportedVersion, whose new features may be dropped
+var ErrUnsupportedVersion = errors.New("tiled: unsupported format version")
+
+// VersionError reports a map or tileset in a format newer than
+// SupportedVersion. It is reported to the warning handler, and returned in
+// strict mode.
+type VersionError struct {
+	// Name of the map file or of the tileset
+	Name string
+	// Format version of the file, and version of Tiled that saved it if known
+	Version      string
+	TiledVersion string
+}
+
+// Error returns the error message, naming the file and its format version
+func (e *VersionError) Error() string {
+	msg := fmt.Sprintf("%s: %s is in format %s", ErrUnsupportedVersion, e.Name, e.Version)
+	if e.TiledVersion != "" {
+		msg += " saved by Tiled " + e.TiledVersion
+	}
+	return msg + ", newer than " + SupportedVersion
+}
+
+// Unwrap returns ErrUnsupportedVersion
+func (e *VersionError) Unwrap() error {
+	return ErrUnsupportedVersion
+}
+
+// checkVersion reports files in a format newer than SupportedVersion.
+// Versions that can't be parsed are left to the decoder.
+func (l *loader) checkVersion(name, version, tiledVersion string) error {
+	if compareVersions(version, SupportedVersion) <= 0 {
+		return nil
+	}
+	return l.inconsistent(&VersionError{Name: name, Version: version, TiledVersion: tiledVersion})
+}
+
+// compareVersions compares dot separated versions number by number, as
+// cmp.Compare does, treating versions that can't be parsed as older
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < max(len(as), len(bs)); i++ {
+		var x, y int
+		var err error
+		if i < len(as) {
+			if x, err = strconv.Atoi(as[i]); err != nil {
+				return -1
+			}
+		}
+		if i < len(bs) {
+			if y, err = strconv.Atoi(bs[i]); err != nil {
+				return 1
+			}
+		}
+		if c := cmp.Compare(x, y); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
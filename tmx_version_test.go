@@ -0,0 +1,46 @@
+package tiled
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("1.10", "1.10"))
+	assert.Equal(t, 1, compareVersions("1.10", "1.9"))
+	assert.Equal(t, -1, compareVersions("1.2", "1.10"))
+	assert.Equal(t, 1, compareVersions("1.10.1", "1.10"))
+	assert.Equal(t, 0, compareVersions("1.10.0", "1.10"))
+	assert.Equal(t, -1, compareVersions("", "1.10"))
+	assert.Equal(t, -1, compareVersions("beta", "1.10"))
+}
+
+func TestLoadNewerVersion(t *testing.T) {
+	tmx := `<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.12" tiledversion="1.12.0" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16">
+<layer id="1" name="Ground" width="1" height="1"><data encoding="csv">0</data></layer>
+</map>`
+
+	var warnings []error
+	m, err := LoadReader(".", bytes.NewBufferString(tmx), WithWarningHandler(func(err error) { warnings = append(warnings, err) }))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.12", m.Version)
+	assert.Equal(t, "1.12.0", m.TiledVersion)
+	if assert.Len(t, warnings, 1) {
+		assert.ErrorIs(t, warnings[0], ErrUnsupportedVersion)
+		var verr *VersionError
+		if assert.ErrorAs(t, warnings[0], &verr) {
+			assert.Equal(t, "1.12", verr.Version)
+		}
+		assert.EqualError(t, warnings[0], "tiled: unsupported format version: map is in format 1.12 saved by Tiled 1.12.0, newer than 1.10")
+	}
+
+	_, err = LoadReader(".", bytes.NewBufferString(tmx), WithParseMode(ParseStrict))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	_, err = LoadTilesetReader(".", bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="2.0" name="ground" tilewidth="8" tileheight="8" tilecount="0" columns="0"/>`), WithParseMode(ParseStrict))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
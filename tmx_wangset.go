@@ -2,10 +2,19 @@ package tiled
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
 )
 
+var (
+	// ErrInvalidWangID is returned for malformed Wang IDs, or Wang IDs
+	// referring to missing colors
+	ErrInvalidWangID = errors.New("tiled: invalid Wang ID")
+	// ErrWangTileNotFound is returned for tiles missing from a Wang set
+	ErrWangTileNotFound = errors.New("tiled: tile not found in Wang set")
+)
+
 // WangSets contains the list of Wang sets defined for this tileset.
 // https://doc.mapeditor.org/en/stable/reference/tmx-map-format/#wangsets
 // Can contain any number: <wangset>
@@ -77,53 +86,143 @@ const (
 	TopLeft
 )
 
-// GetWangColors returns the wangcolors for the tileId. If corner type is used it will return an array of len 4
-// topRight, bottomRight, bottom left, top left
-// if corner type is not used it will return an array of len 8 in the following order.
-// top, top right, right, bottom right, bottom, bottom left, left, top left
-// if there is no wangcolor assigned to a part of the tile it will return an nil pointer instead for that index
+// GetWangColors returns the Wang colors of the tile with the given ID at
+// each WangPosition, in the following order:
+// top, top right, right, bottom right, bottom, bottom left, left, top left.
+// Positions without a Wang color assigned map to a nil pointer. Tiles whose
+// Wang ID refers to colors missing from the set fail with ErrInvalidWangID.
 func (w *WangSet) GetWangColors(tileID uint32) (map[WangPosition]*WangColor, error) {
 	if w.WangColors == nil {
-		return nil, errors.New("no wangcolors found on this wangset")
+		return nil, fmt.Errorf("%w: no Wang colors in Wang set %q", ErrWangTileNotFound, w.Name)
 	}
 
-	var tile *WangTile
-	for _, t := range w.WangTiles {
-		if t.TileID == tileID {
-			tile = t
-			break
-		}
+	id, err := w.WangIDForTile(tileID)
+	if err != nil {
+		return nil, err
 	}
-	if tile == nil {
-		return nil, errors.New("no wangtile matches the given Id")
+
+	wangColors := make(map[WangPosition]*WangColor, len(id))
+	for i, c := range id {
+		wangColors[WangPosition(i)] = w.Color(c)
 	}
+	return wangColors, nil
+}
 
-	// convert from CSV to array of strings
-	wangIDsString := strings.Split(tile.WangID, ",")
+// WangID holds the indexes of the Wang colors of a tile at each WangPosition,
+// starting from 1, 0 meaning unset
+type WangID [8]int
 
-	// convert from array of strings to slice of uint32
-	var wangIDs []uint32 // will contain a slice of the wangIDs
-	for _, v := range wangIDsString {
-		id64, err := strconv.ParseUint(v, 10, 32)
+// ParseWangID parses a Wang ID in the comma separated format of Tiled 1.5 and
+// later, or in the 0xCECECECE format used before.
+func ParseWangID(s string) (WangID, error) {
+	var id WangID
+	if hex, ok := strings.CutPrefix(s, "0x"); ok {
+		n, err := strconv.ParseUint(hex, 16, 32)
 		if err != nil {
-			return nil, errors.New("internal error")
+			return id, fmt.Errorf("%w %q", ErrInvalidWangID, s)
+		}
+		for i := range id {
+			id[i] = int(n >> (4 * i) & 0xf)
+		}
+		return id, nil
+	}
+
+	parts := strings.Split(s, ",")
+	if len(parts) != len(id) {
+		return id, fmt.Errorf("%w %q", ErrInvalidWangID, s)
+	}
+	for i, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || n < 0 {
+			return id, fmt.Errorf("%w %q", ErrInvalidWangID, s)
 		}
+		id[i] = n
+	}
+	return id, nil
+}
 
-		// uint64 to uint32
-		id := uint32(id64)
+// CornerWangID returns the Wang ID of a tile with the given corner colors and
+// unset edges
+func CornerWangID(topRight, bottomRight, bottomLeft, topLeft int) WangID {
+	return WangID{TopRight: topRight, BottomRight: bottomRight, BottomLeft: bottomLeft, TopLeft: topLeft}
+}
+
+// EdgeWangID returns the Wang ID of a tile with the given edge colors and
+// unset corners
+func EdgeWangID(top, right, bottom, left int) WangID {
+	return WangID{Top: top, Right: right, Bottom: bottom, Left: left}
+}
 
-		wangIDs = append(wangIDs, id)
+// Matches reports whether id has the colors of pattern, unset positions of
+// pattern matching any color
+func (id WangID) Matches(pattern WangID) bool {
+	for i, c := range pattern {
+		if c != 0 && id[i] != c {
+			return false
+		}
 	}
+	return true
+}
 
-	wangColors := make(map[WangPosition]*WangColor)
+// String returns the Wang ID in the comma separated format
+func (id WangID) String() string {
+	parts := make([]string, len(id))
+	for i, c := range id {
+		parts[i] = strconv.Itoa(c)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Color returns the Wang color of the given index, starting from 1, or nil
+// when unset or out of range
+func (w *WangSet) Color(index int) *WangColor {
+	if index < 1 || index > len(w.WangColors) {
+		return nil
+	}
+	return w.WangColors[index-1]
+}
 
-	for i, id := range wangIDs {
-		if id == 0 { // no color assigned if id is 0, set to nil
-			wangColors[WangPosition(i)] = nil
-		} else {
-			wangColors[WangPosition(i)] = w.WangColors[id-1]
+// wangID parses the Wang ID of a tile of the set, checking its colors exist
+func (w *WangSet) wangID(t *WangTile) (WangID, error) {
+	id, err := ParseWangID(t.WangID)
+	if err != nil {
+		return id, fmt.Errorf("%w of tile %d of Wang set %q", err, t.TileID, w.Name)
+	}
+	for _, c := range id {
+		if c > len(w.WangColors) {
+			return id, fmt.Errorf("%w %q of tile %d of Wang set %q", ErrInvalidWangID, t.WangID, t.TileID, w.Name)
 		}
 	}
+	return id, nil
+}
 
-	return wangColors, nil
+// WangIDForTile returns the Wang ID of the tile with the given ID
+func (w *WangSet) WangIDForTile(tileID uint32) (WangID, error) {
+	for _, t := range w.WangTiles {
+		if t.TileID == tileID {
+			return w.wangID(t)
+		}
+	}
+	return WangID{}, fmt.Errorf("%w: tile %d of Wang set %q", ErrWangTileNotFound, tileID, w.Name)
+}
+
+// TilesMatching returns the IDs of the tiles whose Wang ID matches pattern,
+// for example built with CornerWangID, in the order of the Wang tiles. Tiles
+// with the same colors are alternatives of a Wang tile, to be picked from
+// according to their probability when auto-tiling. Tiles with an invalid
+// Wang ID are skipped, their errors joined in the returned error.
+func (w *WangSet) TilesMatching(pattern WangID) ([]uint32, error) {
+	var res []uint32
+	var errs []error
+	for _, t := range w.WangTiles {
+		id, err := w.wangID(t)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if id.Matches(pattern) {
+			res = append(res, t.TileID)
+		}
+	}
+	return res, errors.Join(errs...)
 }
@@ -0,0 +1,89 @@
+package tiled
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWangID(t *testing.T) {
+	id, err := ParseWangID("0,1,0,2,0,1,0,3")
+	assert.NoError(t, err)
+	assert.Equal(t, CornerWangID(1, 2, 1, 3), id)
+	assert.Equal(t, "0,1,0,2,0,1,0,3", id.String())
+
+	// Before Tiled 1.5, top edge in the lowest bits
+	id, err = ParseWangID("0x30102010")
+	assert.NoError(t, err)
+	assert.Equal(t, CornerWangID(1, 2, 1, 3), id)
+
+	_, err = ParseWangID("1,2,3")
+	assert.ErrorIs(t, err, ErrInvalidWangID)
+	_, err = ParseWangID("0xzz")
+	assert.ErrorIs(t, err, ErrInvalidWangID)
+}
+
+func TestWangSetQueries(t *testing.T) {
+	ts, err := LoadTilesetReader(".", bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="terrain" tilewidth="16" tileheight="16" tilecount="8" columns="4">
+ <wangsets>
+  <wangset name="Corners" type="corner" tile="-1">
+   <wangcolor name="Grass" color="#00ff00" tile="-1" probability="1"/>
+   <wangcolor name="Water" color="#0000ff" tile="-1" probability="1"/>
+   <wangtile tileid="0" wangid="0,1,0,1,0,1,0,1"/>
+   <wangtile tileid="1" wangid="0,2,0,2,0,1,0,1"/>
+   <wangtile tileid="2" wangid="0,2,0,2,0,1,0,1"/>
+   <wangtile tileid="3" wangid="0,2,0,2,0,2,0,2"/>
+  </wangset>
+  <wangset name="Edges" type="edge" tile="-1">
+   <wangcolor name="Road" color="#ff7700" tile="-1" probability="1"/>
+   <wangtile tileid="4" wangid="1,0,0,0,1,0,0,0"/>
+   <wangtile tileid="5" wangid="0,0,3,0,0,0,0,0"/>
+  </wangset>
+ </wangsets>
+</tileset>`))
+	assert.NoError(t, err)
+	corners, edges := ts.WangSets[0], ts.WangSets[1]
+
+	id, err := corners.WangIDForTile(1)
+	assert.NoError(t, err)
+	assert.Equal(t, CornerWangID(2, 2, 1, 1), id)
+	assert.Equal(t, "Water", corners.Color(id[TopRight]).Name)
+	assert.Nil(t, corners.Color(id[Top]))
+
+	_, err = corners.WangIDForTile(4)
+	assert.ErrorIs(t, err, ErrWangTileNotFound)
+
+	tiles, err := corners.TilesMatching(CornerWangID(2, 2, 1, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{1, 2}, tiles)
+
+	// Unset positions match any color
+	tiles, err = corners.TilesMatching(CornerWangID(2, 0, 0, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, []uint32{1, 2, 3}, tiles)
+
+	set, id, err := ts.WangIDForTile(4)
+	assert.NoError(t, err)
+	assert.Equal(t, edges, set)
+	assert.Equal(t, EdgeWangID(1, 0, 1, 0), id)
+
+	// Colors missing from the set
+	_, _, err = ts.WangIDForTile(5)
+	assert.ErrorIs(t, err, ErrInvalidWangID)
+	_, err = edges.GetWangColors(5)
+	assert.ErrorIs(t, err, ErrInvalidWangID)
+	colors, err := edges.GetWangColors(4)
+	assert.NoError(t, err)
+	assert.Equal(t, edges.Color(1), colors[Top])
+	assert.Nil(t, colors[Right])
+
+	// Tiles with bad Wang IDs are skipped, the others still match
+	tiles, err = edges.TilesMatching(EdgeWangID(1, 0, 0, 0))
+	assert.ErrorIs(t, err, ErrInvalidWangID)
+	assert.Equal(t, []uint32{4}, tiles)
+
+	_, _, err = ts.WangIDForTile(7)
+	assert.ErrorIs(t, err, ErrWangTileNotFound)
+}
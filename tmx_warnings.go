@@ -0,0 +1,72 @@
+package tiled
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrDeprecated error is reported for elements of the format Tiled replaced
+// by others
+var ErrDeprecated = errors.New("tiled: deprecated element")
+
+// ParseWarning is a problem tolerated while loading a map, such as an unknown
+// attribute in tolerant mode, a deprecated element or data fixed by the
+// loader. The kind of problem can be told with errors.Is, against
+// ErrUnknownXML, ErrInconsistentData, ErrDeprecated or ErrUnsupportedVersion
+// among others.
+type ParseWarning struct {
+	// File the problem was found in, empty for maps loaded from readers
+	File string
+	Err  error
+}
+
+// Error returns the message of the problem, prefixed with the file it was
+// found in if known
+func (w ParseWarning) Error() string {
+	if w.File == "" {
+		return w.Err.Error()
+	}
+	return w.File + ": " + w.Err.Error()
+}
+
+// Unwrap returns the problem found
+func (w ParseWarning) Unwrap() error {
+	return w.Err
+}
+
+// Warnings returns the problems tolerated while loading the map and the
+// tilesets and templates it references, in the order they were found. They
+// are also passed to the handler set with WithWarningHandler.
+func (m *Map) Warnings() []ParseWarning {
+	if m.loader == nil {
+		return nil
+	}
+	return slices.Clone(m.loader.warnings)
+}
+
+// forMap returns a copy of l collecting the warnings of a map loaded from
+// file, so maps loaded with the same options don't share their warnings
+func (l *loader) forMap(file string) *loader {
+	ml := *l
+	ml.file = file
+	ml.warnings = nil
+	return &ml
+}
+
+// inFile reports warnings in file until the returned function is called
+func (l *loader) inFile(file string) func() {
+	if l == nil {
+		return func() {}
+	}
+	prev := l.file
+	l.file = file
+	return func() { l.file = prev }
+}
+
+// checkDeprecated warns about the deprecated elements of a tileset
+func (l *loader) checkDeprecated(ts *Tileset) {
+	if len(ts.TerrainTypes) > 0 {
+		l.warning(fmt.Errorf("%w: terrain types of tileset %q, replaced by Wang sets since Tiled 1.5", ErrDeprecated, ts.Name))
+	}
+}
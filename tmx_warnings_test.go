@@ -0,0 +1,43 @@
+package tiled
+
+import (
+	"bytes"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapWarnings(t *testing.T) {
+	fsys := fstest.MapFS{
+		"maps/level.tmx": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16" shiny="yes">
+<tileset firstgid="1" source="ground.tsx"/>
+<layer id="1" name="Ground" width="1" height="1"><data encoding="csv">1</data></layer>
+</map>`)},
+		"maps/ground.tsx": {Data: []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<tileset version="1.10" name="ground" tilewidth="16" tileheight="16" tilecount="1" columns="1">
+<terraintypes><terrain name="grass" tile="0"/></terraintypes>
+</tileset>`)},
+	}
+
+	var handled []error
+	m, err := LoadFile("maps/level.tmx", WithFileSystem(fsys), WithParseMode(ParseTolerant),
+		WithWarningHandler(func(err error) { handled = append(handled, err) }))
+	assert.NoError(t, err)
+
+	warnings := m.Warnings()
+	if assert.Len(t, warnings, 2) {
+		assert.Equal(t, "maps/level.tmx", warnings[0].File)
+		assert.ErrorIs(t, warnings[0], ErrUnknownXML)
+		assert.Equal(t, "maps/ground.tsx", warnings[1].File)
+		assert.ErrorIs(t, warnings[1], ErrDeprecated)
+		assert.EqualError(t, warnings[1], `maps/ground.tsx: tiled: deprecated element: terrain types of tileset "ground", replaced by Wang sets since Tiled 1.5`)
+	}
+	assert.Len(t, handled, 2)
+
+	m, err = LoadReader(".", bytes.NewBufferString(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="1" height="1" tilewidth="16" tileheight="16"/>`))
+	assert.NoError(t, err)
+	assert.Empty(t, m.Warnings())
+}
@@ -0,0 +1,179 @@
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// worldFile is the JSON format of Tiled world files
+type worldFile struct {
+	Maps                 []worldFileMap     `json:"maps"`
+	Patterns             []worldFilePattern `json:"patterns,omitempty"`
+	OnlyShowAdjacentMaps bool               `json:"onlyShowAdjacentMaps,omitempty"`
+	Type                 string             `json:"type"`
+}
+
+type worldFileMap struct {
+	FileName string `json:"fileName"`
+	X        int    `json:"x"`
+	Y        int    `json:"y"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// worldFilePattern matches map files next to the world file, placing each at
+// its first two captured numbers times the multipliers plus the offsets
+type worldFilePattern struct {
+	Regexp      string `json:"regexp"`
+	MultiplierX int    `json:"multiplierX"`
+	MultiplierY int    `json:"multiplierY"`
+	OffsetX     int    `json:"offsetX"`
+	OffsetY     int    `json:"offsetY"`
+}
+
+// WorldMap is a map of a world, placed in world coordinates
+type WorldMap struct {
+	// Path of the map file, relative to the world file
+	FileName string
+	// Position of the top left corner of the map in the world, in pixels
+	X, Y int
+	Map  *Map
+}
+
+// Bounds returns the area covered by the map in the world, in pixels
+func (wm *WorldMap) Bounds() image.Rectangle {
+	return image.Rect(wm.X, wm.Y, wm.X+wm.Map.Width*wm.Map.TileWidth, wm.Y+wm.Map.Height*wm.Map.TileHeight)
+}
+
+// World is a set of maps placed relative to each other, loaded from a Tiled
+// world file
+type World struct {
+	Maps []*WorldMap
+	// Whether Tiled only shows the maps next to the current one
+	OnlyShowAdjacentMaps bool
+}
+
+// LoadWorld loads a Tiled world file (.world) along with every map it
+// references. Maps matched by the patterns of the world are discovered in the
+// directory of the world file. Maps in the JSON format (.tmj, .json) are supported.
+func LoadWorld(fileName string, options ...LoaderOption) (*World, error) {
+	l := newLoader(options...)
+	return l.LoadWorld(fileName)
+}
+
+// LoadWorld loads a Tiled world file (.world) along with every map it
+// references.
+func (l *loader) LoadWorld(fileName string) (*World, error) {
+	f, err := l.open(fileName)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	var wf worldFile
+	if err := json.Unmarshal(data, &wf); err != nil {
+		return nil, err
+	}
+
+	w := &World{OnlyShowAdjacentMaps: wf.OnlyShowAdjacentMaps}
+	dir := filepath.Dir(fileName)
+	for _, e := range wf.Maps {
+		m, err := l.loadAnyFile(filepath.Join(dir, e.FileName))
+		if err != nil {
+			return nil, err
+		}
+		w.Maps = append(w.Maps, &WorldMap{FileName: e.FileName, X: e.X, Y: e.Y, Map: m})
+	}
+
+	if len(wf.Patterns) == 0 {
+		return w, nil
+	}
+	entries, err := l.readDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range wf.Patterns {
+		// Tiled matches patterns against whole file names
+		re, err := regexp.Compile("^(?:" + p.Regexp + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("tiled: invalid world pattern %q: %w", p.Regexp, err)
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			match := re.FindStringSubmatch(name)
+			if entry.IsDir() || len(match) < 3 || w.Map(name) != nil {
+				continue
+			}
+			x, errX := strconv.Atoi(match[1])
+			y, errY := strconv.Atoi(match[2])
+			if errX != nil || errY != nil {
+				continue
+			}
+			m, err := l.loadAnyFile(filepath.Join(dir, name))
+			if err != nil {
+				return nil, err
+			}
+			w.Maps = append(w.Maps, &WorldMap{
+				FileName: name,
+				X:        x*p.MultiplierX + p.OffsetX,
+				Y:        y*p.MultiplierY + p.OffsetY,
+				Map:      m,
+			})
+		}
+	}
+	return w, nil
+}
+
+// readDir lists a directory using the loader's FileSystem, or the local file
+// system if it is nil
+func (l *loader) readDir(name string) ([]fs.DirEntry, error) {
+	if l.FileSystem == nil {
+		return os.ReadDir(filepath.FromSlash(name))
+	}
+	return fs.ReadDir(l.FileSystem, filepath.ToSlash(name))
+}
+
+// loadAnyFile loads a map in the TMX or JSON format, after its extension
+func (l *loader) loadAnyFile(fileName string) (*Map, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".tmj", ".json":
+		return l.LoadJSONFile(fileName)
+	default:
+		return l.LoadFile(fileName)
+	}
+}
+
+// MapAt returns the map covering the point at x, y in world pixels, or nil
+func (w *World) MapAt(x, y int) *WorldMap {
+	p := image.Pt(x, y)
+	for _, wm := range w.Maps {
+		if p.In(wm.Bounds()) {
+			return wm
+		}
+	}
+	return nil
+}
+
+// Map returns the map with the given file name, as listed in the world file,
+// or nil
+func (w *World) Map(fileName string) *WorldMap {
+	for _, wm := range w.Maps {
+		if wm.FileName == fileName {
+			return wm
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package tiled
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadWorld(t *testing.T) {
+	tmx := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="2" height="2" tilewidth="8" tileheight="8">
+<layer id="1" name="Ground" width="2" height="2"><data encoding="csv">0,0,0,0</data></layer>
+</map>`)
+	fsys := fstest.MapFS{
+		"worlds/overworld.world": {Data: []byte(`{
+  "maps": [
+    {"fileName": "maps/a.tmx", "x": 0, "y": 0, "width": 16, "height": 16},
+    {"fileName": "maps/b.tmx", "x": 16, "y": -16, "width": 16, "height": 16}
+  ],
+  "onlyShowAdjacentMaps": true,
+  "type": "world"
+}`)},
+		"worlds/maps/a.tmx": {Data: tmx},
+		"worlds/maps/b.tmx": {Data: tmx},
+	}
+
+	w, err := LoadWorld("worlds/overworld.world", WithFileSystem(fsys))
+	assert.NoError(t, err)
+	assert.True(t, w.OnlyShowAdjacentMaps)
+	assert.Len(t, w.Maps, 2)
+	assert.Equal(t, "Ground", w.Maps[0].Map.Layers[0].Name)
+
+	b := w.Map("maps/b.tmx")
+	if assert.NotNil(t, b) {
+		assert.Equal(t, 16, b.X)
+		assert.Equal(t, -16, b.Y)
+		assert.Equal(t, 32, b.Bounds().Max.X)
+	}
+	assert.Equal(t, b, w.MapAt(20, -1))
+	assert.Equal(t, w.Maps[0], w.MapAt(0, 15))
+	assert.Nil(t, w.MapAt(0, -1))
+
+	_, err = LoadWorld("worlds/missing.world", WithFileSystem(fsys))
+	assert.Error(t, err)
+}
+
+func TestLoadWorldPatterns(t *testing.T) {
+	tmx := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<map version="1.10" orientation="orthogonal" width="4" height="4" tilewidth="8" tileheight="8">
+</map>`)
+	fsys := fstest.MapFS{
+		"world/islands.world": {Data: []byte(`{
+  "maps": [{"fileName": "home.tmx", "x": -32, "y": 0}],
+  "patterns": [{"regexp": "island_(\\d+)_(\\d+)\\.tmx", "multiplierX": 32, "multiplierY": 32, "offsetX": 0, "offsetY": -32}],
+  "type": "world"
+}`)},
+		"world/home.tmx":        {Data: tmx},
+		"world/island_0_0.tmx":  {Data: tmx},
+		"world/island_2_1.tmx":  {Data: tmx},
+		"world/island_2_1.tmx~": {Data: tmx},
+		"world/notes.txt":       {Data: []byte("island_3_3.tmx")},
+	}
+
+	w, err := LoadWorld("world/islands.world", WithFileSystem(fsys))
+	assert.NoError(t, err)
+	assert.Len(t, w.Maps, 3)
+	if m := w.Map("island_2_1.tmx"); assert.NotNil(t, m) {
+		assert.Equal(t, 64, m.X)
+		assert.Equal(t, 0, m.Y)
+	}
+	if m := w.Map("island_0_0.tmx"); assert.NotNil(t, m) {
+		assert.Equal(t, 0, m.X)
+		assert.Equal(t, -32, m.Y)
+	}
+	assert.Equal(t, -32, w.Map("home.tmx").X)
+}